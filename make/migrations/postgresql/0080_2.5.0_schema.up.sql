@@ -0,0 +1,65 @@
+/* the accessories(e.g. cosign signatures) attached to the subject artifacts */
+CREATE TABLE IF NOT EXISTS artifact_accessory (
+ id SERIAL PRIMARY KEY NOT NULL,
+ artifact_id bigint NOT NULL,
+ subject_artifact_id bigint NOT NULL,
+ type varchar(256) NOT NULL,
+ size bigint,
+ digest varchar(1024),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT fk_accessory_artifact_id FOREIGN KEY(artifact_id) REFERENCES artifact(id) ON DELETE CASCADE,
+ CONSTRAINT fk_accessory_subject_artifact_id FOREIGN KEY(subject_artifact_id) REFERENCES artifact(id) ON DELETE CASCADE,
+ CONSTRAINT unique_artifact_accessory UNIQUE (artifact_id, subject_artifact_id)
+);
+
+/* the retry policy of the webhook deliveries, e.g. {"max_retries":3,"backoff":10,"max_backoff":600} */
+ALTER TABLE notification_policy ADD COLUMN IF NOT EXISTS retry_policy text;
+/* the address of the target that the notification job delivers to, used to re-deliver the job */
+ALTER TABLE notification_job ADD COLUMN IF NOT EXISTS target_address varchar(1024);
+
+/* the delivery attempts of the notification jobs */
+CREATE TABLE IF NOT EXISTS notification_job_attempt (
+ id SERIAL PRIMARY KEY NOT NULL,
+ job_id int NOT NULL,
+ status_code int,
+ response text,
+ error text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT fk_attempt_notification_job_id FOREIGN KEY(job_id) REFERENCES notification_job(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_job_attempt_job_id ON notification_job_attempt (job_id);
+
+/* the candidate artifacts evaluated by the dry run retention executions */
+CREATE TABLE IF NOT EXISTS retention_dry_run_candidate (
+ id SERIAL PRIMARY KEY NOT NULL,
+ execution_id int NOT NULL,
+ task_id int NOT NULL,
+ repository varchar(256) NOT NULL,
+ digest varchar(255) NOT NULL,
+ tags text,
+ push_time timestamp,
+ pull_time timestamp,
+ action varchar(16) NOT NULL,
+ rules text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ CONSTRAINT fk_retention_dry_run_candidate_execution_id FOREIGN KEY(execution_id) REFERENCES execution(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_dry_run_candidate_execution_id ON retention_dry_run_candidate (execution_id);
+
+/* the rules to rewrite the repository path and tag of the destination artifacts */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS rewrite_rules text;
+
+/* the time-of-day bandwidth windows of the replication policy */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS bandwidth_windows text;
+
+/* whether to copy the blobs by chunks so that the interrupted copy can be resumed */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS copy_by_chunk boolean DEFAULT false;
+
+/* whether to sync the labels of artifacts and the metadata of projects between Harbor instances */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS sync_metadata boolean DEFAULT false;
+
+/* the expiration time of the robot which has been notified, the robots expired before the upgrading aren't notified */
+ALTER TABLE robot ADD COLUMN IF NOT EXISTS notified_expiresat bigint DEFAULT 0;
+UPDATE robot SET notified_expiresat = expiresat WHERE expiresat > 0 AND expiresat <= extract(epoch from now());
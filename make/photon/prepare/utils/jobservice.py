@@ -18,6 +18,9 @@ def prepare_job_service(config_dict):
     # Job log is stored in data dir
     job_log_dir = os.path.join('/data', "job_logs")
     prepare_dir(job_log_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
+    # The OCI image layouts for the air-gapped replication are shared by core and jobservice
+    oci_layout_dir = os.path.join('/data', "oci-layout")
+    prepare_dir(oci_layout_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
     # Render Jobservice env
     render_jinja(
         job_service_env_template_path,
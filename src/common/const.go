@@ -158,6 +158,10 @@ const (
 	// Global notification enable configuration
 	NotificationEnable = "notification_enable"
 
+	// Audit log forwarding setting items
+	AuditLogForwardEndpoint = "audit_log_forward_endpoint"
+	SkipAuditLogDatabase    = "skip_audit_log_database"
+
 	// Quota setting items for project
 	QuotaPerProjectEnable = "quota_per_project_enable"
 	StoragePerProject     = "storage_per_project"
@@ -59,6 +59,7 @@ const (
 	ResourceArtifactLabel         = Resource("artifact-label")
 	ResourcePreatPolicy           = Resource("preheat-policy")
 	ResourcePreatInstance         = Resource("preheat-instance")
+	ResourceProxyWarmUp           = Resource("proxy-warm-up")
 	ResourceSelf                  = Resource("") // subresource for self
 
 	ResourceAuditLog           = Resource("audit-log")
@@ -70,6 +71,7 @@ const (
 	ResourceReplication        = Resource("replication")
 	ResourceDistribution       = Resource("distribution")
 	ResourceGarbageCollection  = Resource("garbage-collection")
+	ResourcePurgeAuditLog      = Resource("purge-audit")
 	ResourceReplicationAdapter = Resource("replication-adapter")
 	ResourceReplicationPolicy  = Resource("replication-policy")
 	ResourceScanAll            = Resource("scan-all")
@@ -121,6 +121,10 @@ var (
 			{Resource: rbac.ResourcePreatPolicy, Action: rbac.ActionUpdate},
 			{Resource: rbac.ResourcePreatPolicy, Action: rbac.ActionDelete},
 			{Resource: rbac.ResourcePreatPolicy, Action: rbac.ActionList},
+
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionCreate},
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionRead},
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionList},
 		},
 
 		"maintainer": {
@@ -202,6 +206,10 @@ var (
 
 			{Resource: rbac.ResourceArtifactLabel, Action: rbac.ActionCreate},
 			{Resource: rbac.ResourceArtifactLabel, Action: rbac.ActionDelete},
+
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionCreate},
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionRead},
+			{Resource: rbac.ResourceProxyWarmUp, Action: rbac.ActionList},
 		},
 
 		"developer": {
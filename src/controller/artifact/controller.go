@@ -35,6 +35,8 @@ import (
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/accessory"
+	accessorymodel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	"github.com/goharbor/harbor/src/pkg/artifact"
 	"github.com/goharbor/harbor/src/pkg/artifactrash"
 	"github.com/goharbor/harbor/src/pkg/artifactrash/model"
@@ -47,6 +49,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/registry"
 	"github.com/goharbor/harbor/src/pkg/repository"
 	"github.com/goharbor/harbor/src/pkg/signature"
+	"github.com/goharbor/harbor/src/pkg/signature/cosign"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	"github.com/opencontainers/go-digest"
 )
@@ -115,6 +118,7 @@ func NewController() Controller {
 		repoMgr:      repository.Mgr,
 		artMgr:       artifact.Mgr,
 		artrashMgr:   artifactrash.Mgr,
+		accessoryMgr: accessory.Mgr,
 		blobMgr:      blob.Mgr,
 		sigMgr:       signature.GetManager(),
 		labelMgr:     label.Mgr,
@@ -129,6 +133,7 @@ type controller struct {
 	repoMgr      repository.Manager
 	artMgr       artifact.Manager
 	artrashMgr   artifactrash.Manager
+	accessoryMgr accessory.Manager
 	blobMgr      blob.Manager
 	sigMgr       signature.Manager
 	labelMgr     label.Manager
@@ -146,6 +151,12 @@ func (c *controller) Ensure(ctx context.Context, repository, digest string, tags
 		if err = c.tagCtl.Ensure(ctx, artifact.RepositoryID, artifact.ID, tag); err != nil {
 			return false, 0, err
 		}
+		// the tag is a cosign signature tag, attach the signature to the subject artifact
+		if subjectDigest, ok := cosign.ParseSignatureTag(tag); ok {
+			if err = c.ensureSignature(ctx, artifact, repository, subjectDigest); err != nil {
+				return false, 0, err
+			}
+		}
 	}
 	// fire event
 	e := &metadata.PushArtifactEventMetadata{
@@ -219,6 +230,20 @@ func (c *controller) ensureArtifact(ctx context.Context, repository, digest stri
 	return created, artifact, nil
 }
 
+// ensure the signature artifact is attached to the subject artifact as an accessory
+func (c *controller) ensureSignature(ctx context.Context, signature *artifact.Artifact, repository, subjectDigest string) error {
+	subject, err := c.artMgr.GetByDigest(ctx, repository, subjectDigest)
+	if err != nil {
+		// the subject artifact doesn't exist, it isn't a real signature, skip
+		if errors.IsNotFoundErr(err) {
+			log.Warningf("the subject artifact %s@%s of signature %s not found, skip", repository, subjectDigest, signature.Digest)
+			return nil
+		}
+		return err
+	}
+	return c.accessoryMgr.Ensure(ctx, subject.ID, signature.ID, signature.Size, signature.Digest, accessorymodel.TypeCosignSignature)
+}
+
 func (c *controller) Count(ctx context.Context, query *q.Query) (int64, error) {
 	return c.artMgr.Count(ctx, query)
 }
@@ -331,6 +356,19 @@ func (c *controller) deleteDeeply(ctx context.Context, id int64, isRoot bool) er
 		}
 	}
 
+	// delete the accessories(e.g. signatures) attached to the root artifact
+	if isRoot {
+		accessories, err := c.accessoryMgr.List(ctx, q.New(q.KeyWords{"SubjectArtifactID": id}))
+		if err != nil {
+			return err
+		}
+		for _, acc := range accessories {
+			if err = c.deleteDeeply(ctx, acc.ArtifactID, true); err != nil && !errors.IsErr(err, errors.NotFoundCode) {
+				return err
+			}
+		}
+	}
+
 	// delete all tags that attached to the root artifact
 	if isRoot {
 		var ids []int64
@@ -582,6 +620,9 @@ func (c *controller) assembleArtifact(ctx context.Context, art *artifact.Artifac
 	if option.WithLabel {
 		c.populateLabels(ctx, artifact)
 	}
+	if option.WithAccessory {
+		c.populateAccessories(ctx, artifact)
+	}
 	return artifact
 }
 
@@ -617,6 +658,15 @@ func (c *controller) populateLabels(ctx context.Context, art *Artifact) {
 	art.Labels = labels
 }
 
+func (c *controller) populateAccessories(ctx context.Context, art *Artifact) {
+	accessories, err := c.accessoryMgr.List(ctx, q.New(q.KeyWords{"SubjectArtifactID": art.ID}))
+	if err != nil {
+		log.Errorf("failed to list accessories of artifact %d: %v", art.ID, err)
+		return
+	}
+	art.Accessories = accessories
+}
+
 func (c *controller) populateAdditionLinks(ctx context.Context, artifact *Artifact) {
 	types := processor.Get(artifact.MediaType).ListAdditionTypes(ctx, &artifact.Artifact)
 	if len(types) > 0 {
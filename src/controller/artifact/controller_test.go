@@ -28,12 +28,14 @@ import (
 	"github.com/goharbor/harbor/src/lib/icon"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
+	accessorymodel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	"github.com/goharbor/harbor/src/pkg/artifact"
 	"github.com/goharbor/harbor/src/pkg/label/model"
 	repomodel "github.com/goharbor/harbor/src/pkg/repository/model"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	tagtesting "github.com/goharbor/harbor/src/testing/controller/tag"
 	ormtesting "github.com/goharbor/harbor/src/testing/lib/orm"
+	accessorytesting "github.com/goharbor/harbor/src/testing/pkg/accessory"
 	arttesting "github.com/goharbor/harbor/src/testing/pkg/artifact"
 	artrashtesting "github.com/goharbor/harbor/src/testing/pkg/artifactrash"
 	"github.com/goharbor/harbor/src/testing/pkg/blob"
@@ -63,6 +65,7 @@ type controllerTestSuite struct {
 	repoMgr      *repotesting.Manager
 	artMgr       *arttesting.Manager
 	artrashMgr   *artrashtesting.FakeManager
+	accessoryMgr *accessorytesting.Manager
 	blobMgr      *blob.Manager
 	tagCtl       *tagtesting.FakeController
 	labelMgr     *label.Manager
@@ -75,6 +78,7 @@ func (c *controllerTestSuite) SetupTest() {
 	c.repoMgr = &repotesting.Manager{}
 	c.artMgr = &arttesting.Manager{}
 	c.artrashMgr = &artrashtesting.FakeManager{}
+	c.accessoryMgr = &accessorytesting.Manager{}
 	c.blobMgr = &blob.Manager{}
 	c.tagCtl = &tagtesting.FakeController{}
 	c.labelMgr = &label.Manager{}
@@ -85,6 +89,7 @@ func (c *controllerTestSuite) SetupTest() {
 		repoMgr:      c.repoMgr,
 		artMgr:       c.artMgr,
 		artrashMgr:   c.artrashMgr,
+		accessoryMgr: c.accessoryMgr,
 		blobMgr:      c.blobMgr,
 		tagCtl:       c.tagCtl,
 		labelMgr:     c.labelMgr,
@@ -105,7 +110,8 @@ func (c *controllerTestSuite) TestAssembleArtifact() {
 		TagOption: &tag.Option{
 			WithImmutableStatus: false,
 		},
-		WithLabel: true,
+		WithLabel:     true,
+		WithAccessory: true,
 	}
 	tg := &tag.Tag{
 		Tag: model_tag.Tag{
@@ -126,12 +132,22 @@ func (c *controllerTestSuite) TestAssembleArtifact() {
 	c.labelMgr.On("ListByArtifact", mock.Anything, mock.Anything).Return([]*model.Label{
 		lb,
 	}, nil)
+	acc := &accessorymodel.Accessory{
+		ID:                1,
+		ArtifactID:        2,
+		SubjectArtifactID: 1,
+		Type:              accessorymodel.TypeCosignSignature,
+	}
+	c.accessoryMgr.On("List", mock.Anything, mock.Anything).Return([]*accessorymodel.Accessory{
+		acc,
+	}, nil)
 	artifact := c.ctl.assembleArtifact(ctx, art, option)
 	c.Require().NotNil(artifact)
 	c.Equal(art.ID, artifact.ID)
 	c.Equal(icon.DigestOfIconDefault, artifact.Icon)
 	c.Contains(artifact.Tags, tg)
 	c.Contains(artifact.Labels, lb)
+	c.Contains(artifact.Accessories, acc)
 	// TODO check other fields of option
 }
 
@@ -239,6 +255,46 @@ func (c *controllerTestSuite) TestEnsure() {
 	c.Equal(int64(1), id)
 }
 
+func (c *controllerTestSuite) TestEnsureSignature() {
+	digest := "sha256:418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180"
+	sigDigest := "sha256:a20b28da4ed9ba8a2e5f8a3e1bfd2bdc9ba1f5d6bcd7a3de0f1d1ba0d1c4fd3a"
+
+	// the signature artifact already exists and the subject artifact exists
+	c.artMgr.On("GetByDigest", mock.Anything, mock.Anything, sigDigest).Return(&artifact.Artifact{
+		ID:     2,
+		Digest: sigDigest,
+		Size:   100,
+	}, nil)
+	c.artMgr.On("GetByDigest", mock.Anything, mock.Anything, digest).Return(&artifact.Artifact{
+		ID:     1,
+		Digest: digest,
+	}, nil)
+	c.tagCtl.On("Ensure").Return(nil)
+	c.accessoryMgr.On("Ensure", mock.Anything, int64(1), int64(2), int64(100), sigDigest,
+		accessorymodel.TypeCosignSignature).Return(nil)
+	_, id, err := c.ctl.Ensure(orm.NewContext(nil, &ormtesting.FakeOrmer{}), "library/hello-world", sigDigest,
+		"sha256-418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180.sig")
+	c.Require().Nil(err)
+	c.Equal(int64(2), id)
+	c.accessoryMgr.AssertExpectations(c.T())
+
+	// reset the mock
+	c.SetupTest()
+
+	// the subject artifact doesn't exist
+	c.artMgr.On("GetByDigest", mock.Anything, mock.Anything, sigDigest).Return(&artifact.Artifact{
+		ID:     2,
+		Digest: sigDigest,
+	}, nil)
+	c.artMgr.On("GetByDigest", mock.Anything, mock.Anything, digest).Return(nil, errors.NotFoundError(nil))
+	c.tagCtl.On("Ensure").Return(nil)
+	_, _, err = c.ctl.Ensure(orm.NewContext(nil, &ormtesting.FakeOrmer{}), "library/hello-world", sigDigest,
+		"sha256-418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180.sig")
+	c.Require().Nil(err)
+	c.accessoryMgr.AssertNotCalled(c.T(), "Ensure", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (c *controllerTestSuite) TestCount() {
 	c.artMgr.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
 	total, err := c.ctl.Count(nil, nil)
@@ -465,6 +521,19 @@ func (c *controllerTestSuite) TestDeleteDeeply() {
 	}, nil)
 	err = c.ctl.deleteDeeply(nil, 1, false)
 	c.Require().Nil(err)
+
+	// reset the mock
+	c.SetupTest()
+
+	// root artifact with accessories that fail to be listed
+	c.artMgr.On("Get", mock.Anything, mock.Anything).Return(&artifact.Artifact{ID: 1}, nil)
+	c.tagCtl.On("List").Return(nil, nil)
+	c.repoMgr.On("Get", mock.Anything, mock.Anything).Return(&repomodel.RepoRecord{}, nil)
+	c.artMgr.On("ListReferences", mock.Anything, mock.Anything).Return([]*artifact.Reference{}, nil)
+	c.accessoryMgr.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("failed to list"))
+	err = c.ctl.deleteDeeply(orm.NewContext(nil, &ormtesting.FakeOrmer{}), 1, true)
+	c.Require().NotNil(err)
+	c.artMgr.AssertNotCalled(c.T(), "Delete", mock.Anything, mock.Anything)
 }
 
 func (c *controllerTestSuite) TestCopy() {
@@ -16,12 +16,22 @@ package auditlog
 
 import (
 	"context"
+	"sync"
+
 	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/lib/config"
 	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/pkg/audit"
 	am "github.com/goharbor/harbor/src/pkg/audit/model"
 )
 
+var (
+	fwdLock     sync.Mutex
+	fwdEndpoint string
+	fwd         *asyncForwarder
+)
+
 // Handler - audit log handler
 type Handler struct {
 }
@@ -54,6 +64,18 @@ func (h *Handler) Handle(ctx context.Context, value interface{}) error {
 		log.Errorf("Can not handler this event type! %#v", v)
 	}
 	if auditLog != nil {
+		endpoint := config.AuditLogForwardEndpoint(ctx)
+		if len(endpoint) > 0 {
+			// the audit log is forwarded in the background, when it is skipped from the database
+			// the forwarder falls back to the database on failure to avoid losing the audit log
+			skipDB := config.SkipAuditLogDatabase(ctx)
+			err := forward(endpoint, auditLog, skipDB)
+			if err != nil {
+				log.Errorf("failed to forward audit log to %s: %v", endpoint, err)
+			} else if skipDB {
+				return nil
+			}
+		}
 		_, err := audit.Mgr.Create(ctx, auditLog)
 		if err != nil {
 			log.Debugf("add audit log err: %v", err)
@@ -62,6 +84,42 @@ func (h *Handler) Handle(ctx context.Context, value interface{}) error {
 	return nil
 }
 
+// forward queues the audit log to be sent to the endpoint
+func forward(endpoint string, auditLog *am.AuditLog, fallback bool) error {
+	f, err := getForwarder(endpoint)
+	if err != nil {
+		return err
+	}
+	return f.enqueue(auditLog, fallback)
+}
+
+// saveAuditLog saves the audit log into the database
+func saveAuditLog(auditLog *am.AuditLog) {
+	if _, err := audit.Mgr.Create(orm.Context(), auditLog); err != nil {
+		log.Errorf("failed to save audit log: %v", err)
+	}
+}
+
+// getForwarder returns the cached forwarder if the endpoint isn't changed, otherwise creates a new one
+func getForwarder(endpoint string) (*asyncForwarder, error) {
+	fwdLock.Lock()
+	defer fwdLock.Unlock()
+	if fwd != nil && fwdEndpoint == endpoint {
+		return fwd, nil
+	}
+	f, err := NewForwarder(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if fwd != nil {
+		if err := fwd.Close(); err != nil {
+			log.Warningf("failed to close the audit log forwarder of %s: %v", fwdEndpoint, err)
+		}
+	}
+	fwd, fwdEndpoint = newAsyncForwarder(f, saveAuditLog), endpoint
+	return fwd, nil
+}
+
 // IsStateful ...
 func (h *Handler) IsStateful() bool {
 	return false
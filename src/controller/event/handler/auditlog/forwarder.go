@@ -0,0 +1,268 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	am "github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const (
+	// the facility "log audit"(13) and the severity "informational"(6)
+	syslogPriority = 13*8 + 6
+	syslogAppName  = "harbor"
+	syslogMsgID    = "audit"
+	dialTimeout    = 5 * time.Second
+	writeTimeout   = 5 * time.Second
+	httpTimeout    = 10 * time.Second
+	// the max count of the audit logs waiting to be forwarded
+	queueSize = 1000
+)
+
+// Forwarder forwards the audit logs to the external endpoint
+type Forwarder interface {
+	// Forward sends the audit log to the endpoint
+	Forward(ctx context.Context, auditLog *am.AuditLog) error
+	// Close releases the resources held by the forwarder
+	Close() error
+}
+
+// NewForwarder creates the forwarder according to the scheme of the endpoint:
+// "tcp" and "udp" for syslog(RFC5424), "http" and "https" for HTTP
+func NewForwarder(endpoint string) (Forwarder, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid audit log forward endpoint %s", endpoint)
+	}
+	switch u.Scheme {
+	case "tcp", "udp":
+		if len(u.Host) == 0 {
+			return nil, errors.Errorf("invalid audit log forward endpoint %s: empty host", endpoint)
+		}
+		hostname, err := os.Hostname()
+		if err != nil || len(hostname) == 0 {
+			hostname = "-"
+		}
+		return &syslogForwarder{
+			network:  u.Scheme,
+			address:  u.Host,
+			hostname: hostname,
+		}, nil
+	case "http", "https":
+		return &httpForwarder{
+			endpoint: endpoint,
+			client: &http.Client{
+				Transport: commonhttp.GetHTTPTransport(),
+				Timeout:   httpTimeout,
+			},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q of audit log forward endpoint %s", u.Scheme, endpoint)
+	}
+}
+
+// syslogForwarder sends the audit logs as RFC5424 messages, the messages sent via TCP
+// are framed with the octet counting method defined in RFC6587
+type syslogForwarder struct {
+	network  string
+	address  string
+	hostname string
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+func (s *syslogForwarder) Forward(ctx context.Context, auditLog *am.AuditLog) error {
+	msg, err := s.format(auditLog)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// retry once with a new connection as the cached one may be closed by the server
+	for i := 0; i < 2; i++ {
+		if s.conn == nil {
+			conn, err := net.DialTimeout(s.network, s.address, dialTimeout)
+			if err != nil {
+				return errors.Wrapf(err, "failed to connect to syslog server %s://%s", s.network, s.address)
+			}
+			s.conn = conn
+		}
+		if err = s.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err == nil {
+			if _, err = s.conn.Write(msg); err == nil {
+				return nil
+			}
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return errors.Wrapf(err, "failed to send audit log to syslog server %s://%s", s.network, s.address)
+}
+
+func (s *syslogForwarder) format(auditLog *am.AuditLog) ([]byte, error) {
+	content, err := json.Marshal(auditLog)
+	if err != nil {
+		return nil, err
+	}
+	opTime := auditLog.OpTime
+	if opTime.IsZero() {
+		opTime = time.Now()
+	}
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s", syslogPriority, opTime.UTC().Format(time.RFC3339Nano),
+		s.hostname, syslogAppName, os.Getpid(), syslogMsgID, content)
+	if s.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg), nil
+}
+
+func (s *syslogForwarder) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// httpForwarder posts the audit logs as JSON to the endpoint
+type httpForwarder struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (h *httpForwarder) Forward(ctx context.Context, auditLog *am.AuditLog) error {
+	content, err := json.Marshal(auditLog)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send audit log to %s", h.endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("failed to send audit log to %s, status code: %d", h.endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+func (h *httpForwarder) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
+
+// queuedAuditLog is the audit log waiting to be forwarded
+type queuedAuditLog struct {
+	auditLog *am.AuditLog
+	// whether to save the audit log into the database when the forwarding fails
+	fallback bool
+}
+
+// asyncForwarder forwards the audit logs in the background through a bounded queue,
+// so that a slow or unreachable endpoint doesn't block the event handling
+type asyncForwarder struct {
+	forwarder Forwarder
+	// saves the audit log into the database when the forwarding fails
+	save  func(auditLog *am.AuditLog)
+	queue chan *queuedAuditLog
+	done  chan struct{}
+	stop  sync.Once
+}
+
+func newAsyncForwarder(forwarder Forwarder, save func(auditLog *am.AuditLog)) *asyncForwarder {
+	a := &asyncForwarder{
+		forwarder: forwarder,
+		save:      save,
+		queue:     make(chan *queuedAuditLog, queueSize),
+		done:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// enqueue puts the audit log into the queue, it returns an error rather than blocking when the queue is full
+func (a *asyncForwarder) enqueue(auditLog *am.AuditLog, fallback bool) error {
+	select {
+	case <-a.done:
+		return errors.New("the audit log forwarder is closed")
+	default:
+	}
+	select {
+	case a.queue <- &queuedAuditLog{auditLog: auditLog, fallback: fallback}:
+		return nil
+	default:
+		return errors.New("the audit log forward queue is full")
+	}
+}
+
+func (a *asyncForwarder) run() {
+	for {
+		select {
+		case item := <-a.queue:
+			a.forward(item)
+		case <-a.done:
+			// save the pending audit logs rather than forwarding them to the stale endpoint
+			for {
+				select {
+				case item := <-a.queue:
+					if item.fallback {
+						a.save(item.auditLog)
+					}
+				default:
+					if err := a.forwarder.Close(); err != nil {
+						log.Warningf("failed to close the audit log forwarder: %v", err)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *asyncForwarder) forward(item *queuedAuditLog) {
+	if err := a.forwarder.Forward(context.Background(), item.auditLog); err != nil {
+		log.Errorf("failed to forward audit log: %v", err)
+		if item.fallback {
+			a.save(item.auditLog)
+		}
+	}
+}
+
+// Close stops the background forwarding
+func (a *asyncForwarder) Close() error {
+	a.stop.Do(func() { close(a.done) })
+	return nil
+}
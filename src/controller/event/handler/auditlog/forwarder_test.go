@@ -0,0 +1,238 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	am "github.com/goharbor/harbor/src/pkg/audit/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type forwarderTestSuite struct {
+	suite.Suite
+	auditLog *am.AuditLog
+}
+
+func (f *forwarderTestSuite) SetupTest() {
+	f.auditLog = &am.AuditLog{
+		ProjectID:    1,
+		Operation:    "create",
+		ResourceType: "project",
+		Resource:     "library",
+		Username:     "admin",
+		OpTime:       time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *forwarderTestSuite) TestNewForwarder() {
+	_, err := NewForwarder("ftp://localhost:21")
+	f.Error(err)
+
+	_, err = NewForwarder("tcp://")
+	f.Error(err)
+
+	fwd, err := NewForwarder("udp://localhost:514")
+	f.Require().Nil(err)
+	f.IsType(&syslogForwarder{}, fwd)
+
+	fwd, err = NewForwarder("https://localhost/audit")
+	f.Require().Nil(err)
+	f.IsType(&httpForwarder{}, fwd)
+}
+
+func (f *forwarderTestSuite) TestSyslogTCP() {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	f.Require().Nil(err)
+	defer ln.Close()
+
+	msgs := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			// octet counting framing: "MSG-LEN SP SYSLOG-MSG"
+			l, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(l))
+			if err != nil {
+				return
+			}
+			buf := make([]byte, n)
+			if _, err = io.ReadFull(reader, buf); err != nil {
+				return
+			}
+			msgs <- string(buf)
+		}
+	}()
+
+	fwd, err := NewForwarder("tcp://" + ln.Addr().String())
+	f.Require().Nil(err)
+	defer fwd.Close()
+
+	f.Require().Nil(fwd.Forward(context.TODO(), f.auditLog))
+	f.Require().Nil(fwd.Forward(context.TODO(), f.auditLog))
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-msgs:
+			f.assertSyslogMsg(msg)
+		case <-time.After(5 * time.Second):
+			f.Fail("timeout waiting for the syslog message")
+		}
+	}
+}
+
+func (f *forwarderTestSuite) TestSyslogUDP() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	f.Require().Nil(err)
+	defer conn.Close()
+
+	fwd, err := NewForwarder("udp://" + conn.LocalAddr().String())
+	f.Require().Nil(err)
+	defer fwd.Close()
+	f.Require().Nil(fwd.Forward(context.TODO(), f.auditLog))
+
+	buf := make([]byte, 4096)
+	f.Require().Nil(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	f.Require().Nil(err)
+	f.assertSyslogMsg(string(buf[:n]))
+}
+
+func (f *forwarderTestSuite) TestHTTP() {
+	received := make(chan *am.AuditLog, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.Equal(http.MethodPost, r.Method)
+		f.Equal("application/json", r.Header.Get("Content-Type"))
+		auditLog := &am.AuditLog{}
+		if err := json.NewDecoder(r.Body).Decode(auditLog); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- auditLog
+	}))
+	defer server.Close()
+
+	fwd, err := NewForwarder(server.URL)
+	f.Require().Nil(err)
+	defer fwd.Close()
+	f.Require().Nil(fwd.Forward(context.TODO(), f.auditLog))
+	auditLog := <-received
+	f.Equal(f.auditLog.Resource, auditLog.Resource)
+	f.Equal(f.auditLog.Username, auditLog.Username)
+
+	failure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failure.Close()
+	fwd, err = NewForwarder(failure.URL)
+	f.Require().Nil(err)
+	f.Error(fwd.Forward(context.TODO(), f.auditLog))
+}
+
+func (f *forwarderTestSuite) TestGetForwarder() {
+	fwd1, err := getForwarder("udp://127.0.0.1:514")
+	f.Require().Nil(err)
+	fwd2, err := getForwarder("udp://127.0.0.1:514")
+	f.Require().Nil(err)
+	f.Same(fwd1, fwd2)
+
+	fwd3, err := getForwarder("http://127.0.0.1/audit")
+	f.Require().Nil(err)
+	f.NotSame(fwd1, fwd3)
+}
+
+func (f *forwarderTestSuite) TestAsyncForwarder() {
+	saved := make(chan *am.AuditLog, 2)
+	save := func(auditLog *am.AuditLog) { saved <- auditLog }
+
+	// forwarded successfully, nothing is saved
+	fake := &fakeForwarder{forwarded: make(chan *am.AuditLog, 1)}
+	a := newAsyncForwarder(fake, save)
+	f.Require().Nil(a.enqueue(f.auditLog, true))
+	select {
+	case <-fake.forwarded:
+	case <-time.After(5 * time.Second):
+		f.Fail("timeout waiting for the audit log being forwarded")
+	}
+	f.Require().Nil(a.Close())
+	f.Error(a.enqueue(f.auditLog, true))
+	f.Len(saved, 0)
+
+	// failed to forward, saved only when falling back is required
+	fake = &fakeForwarder{forwarded: make(chan *am.AuditLog, 2), err: errors.New("failure")}
+	a = newAsyncForwarder(fake, save)
+	defer a.Close()
+	f.Require().Nil(a.enqueue(f.auditLog, false))
+	f.Require().Nil(a.enqueue(f.auditLog, true))
+	select {
+	case auditLog := <-saved:
+		f.Same(f.auditLog, auditLog)
+	case <-time.After(5 * time.Second):
+		f.Fail("timeout waiting for the audit log being saved")
+	}
+	f.Len(saved, 0)
+}
+
+func (f *forwarderTestSuite) TestAsyncForwarderQueueFull() {
+	// the forwarder isn't started, so the queue won't be consumed
+	a := &asyncForwarder{
+		queue: make(chan *queuedAuditLog, 1),
+		done:  make(chan struct{}),
+	}
+	f.Require().Nil(a.enqueue(f.auditLog, true))
+	f.Error(a.enqueue(f.auditLog, true))
+}
+
+func (f *forwarderTestSuite) assertSyslogMsg(msg string) {
+	f.True(strings.HasPrefix(msg, "<110>1 2021-01-01T00:00:00Z "), msg)
+	f.Contains(msg, " harbor ")
+	f.Contains(msg, ` audit - {"id":0,"project_id":1,"operation":"create"`)
+}
+
+type fakeForwarder struct {
+	forwarded chan *am.AuditLog
+	err       error
+}
+
+func (f *fakeForwarder) Forward(ctx context.Context, auditLog *am.AuditLog) error {
+	f.forwarded <- auditLog
+	return f.err
+}
+
+func (f *fakeForwarder) Close() error {
+	return nil
+}
+
+func TestForwarderTestSuite(t *testing.T) {
+	suite.Run(t, &forwarderTestSuite{})
+}
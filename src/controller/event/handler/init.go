@@ -10,6 +10,7 @@ import (
 	"github.com/goharbor/harbor/src/controller/event/handler/replication"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/artifact"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/chart"
+	"github.com/goharbor/harbor/src/controller/event/handler/webhook/project"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/quota"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/scan"
 	"github.com/goharbor/harbor/src/controller/event/metadata"
@@ -35,6 +36,16 @@ func init() {
 	notifier.Subscribe(event.TopicDeleteArtifact, &scan.DelArtHandler{})
 	notifier.Subscribe(event.TopicReplication, &artifact.ReplicationHandler{})
 	notifier.Subscribe(event.TopicTagRetention, &artifact.RetentionHandler{})
+	notifier.Subscribe(event.TopicCreateProject, &project.Handler{})
+	notifier.Subscribe(event.TopicDeleteProject, &project.Handler{})
+	notifier.Subscribe(event.TopicAddMember, &project.Handler{})
+	notifier.Subscribe(event.TopicUpdateMemberRole, &project.Handler{})
+	notifier.Subscribe(event.TopicRemoveMember, &project.Handler{})
+	notifier.Subscribe(event.TopicCreateRobot, &project.Handler{})
+	notifier.Subscribe(event.TopicRobotExpired, &project.Handler{})
+	notifier.Subscribe(event.TopicCreatePolicy, &project.Handler{})
+	notifier.Subscribe(event.TopicUpdatePolicy, &project.Handler{})
+	notifier.Subscribe(event.TopicDeletePolicy, &project.Handler{})
 
 	// replication
 	notifier.Subscribe(event.TopicPushArtifact, &replication.Handler{})
@@ -84,9 +84,10 @@ func (r *Handler) handlePushArtifact(ctx context.Context, event *event.PushArtif
 				},
 				Artifacts: []*model.Artifact{
 					{
-						Type:   art.Type,
-						Digest: art.Digest,
-						Tags:   event.Tags,
+						Type:     art.Type,
+						Digest:   art.Digest,
+						Tags:     event.Tags,
+						PushTime: art.PushTime,
 					}},
 			},
 		},
@@ -106,9 +107,10 @@ func (r *Handler) handleDeleteArtifact(ctx context.Context, event *event.DeleteA
 				},
 				Artifacts: []*model.Artifact{
 					{
-						Type:   art.Type,
-						Digest: art.Digest,
-						Tags:   event.Tags,
+						Type:     art.Type,
+						Digest:   art.Digest,
+						Tags:     event.Tags,
+						PushTime: art.PushTime,
 					}},
 			},
 			Deleted: true,
@@ -140,9 +142,10 @@ func (r *Handler) handleCreateTag(ctx context.Context, event *event.CreateTagEve
 				},
 				Artifacts: []*model.Artifact{
 					{
-						Type:   art.Type,
-						Digest: art.Digest,
-						Tags:   []string{event.Tag},
+						Type:     art.Type,
+						Digest:   art.Digest,
+						Tags:     []string{event.Tag},
+						PushTime: art.PushTime,
 					}},
 			},
 		},
@@ -162,9 +165,10 @@ func (r *Handler) handleDeleteTag(ctx context.Context, event *event.DeleteTagEve
 				},
 				Artifacts: []*model.Artifact{
 					{
-						Type:   art.Type,
-						Digest: art.Digest,
-						Tags:   []string{event.Tag},
+						Type:     art.Type,
+						Digest:   art.Digest,
+						Tags:     []string{event.Tag},
+						PushTime: art.PushTime,
 					}},
 			},
 			Deleted:     true,
@@ -26,13 +26,16 @@ func SendHookWithPolicies(policies []*policy_model.Policy, payload *notifyModel.
 	errRet := false
 	for _, ply := range policies {
 		targets := ply.Targets
-		for _, target := range targets {
+		for i, target := range targets {
 			evt := &event.Event{}
 			hookMetadata := &event.HookMetaData{
-				EventType: eventType,
-				PolicyID:  ply.ID,
-				Payload:   payload,
-				Target:    &target,
+				ProjectID:   ply.ProjectID,
+				EventType:   eventType,
+				PolicyID:    ply.ID,
+				Payload:     payload,
+				Target:      &target,
+				TargetIndex: i,
+				RetryPolicy: ply.RetryPolicy,
 			}
 			// It should never affect evaluating other policies when one is failed, but error should return
 			if err := evt.Build(hookMetadata); err == nil {
@@ -0,0 +1,166 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/handler/util"
+	eventModel "github.com/goharbor/harbor/src/controller/event/model"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// Handler preprocess the project, member, robot account and policy events
+type Handler struct {
+}
+
+// Name ...
+func (p *Handler) Name() string {
+	return "ProjectWebhook"
+}
+
+// Handle ...
+func (p *Handler) Handle(ctx context.Context, value interface{}) error {
+	if !config.NotificationEnable(ctx) {
+		log.Debug("notification feature is not enabled")
+		return nil
+	}
+
+	payload, projectID, err := constructPayload(value)
+	if err != nil {
+		return err
+	}
+
+	var policies []*policy_model.Policy
+	if projectID > 0 {
+		policies, err = notification.PolicyMgr.GetRelatedPolices(ctx, projectID, payload.Type)
+		if err != nil {
+			log.Errorf("failed to find policy for %s event: %v", payload.Type, err)
+			return err
+		}
+	}
+	// the system level policies receive the events of the system level resources and the
+	// project lifecycle events, as no project level policy exists when the project is created
+	if projectID == 0 || payload.Type == event.TopicCreateProject || payload.Type == event.TopicDeleteProject {
+		systemPolicies, err := notification.PolicyMgr.GetRelatedPolices(ctx, policy_model.SystemLevelProjectID, payload.Type)
+		if err != nil {
+			log.Errorf("failed to find system level policy for %s event: %v", payload.Type, err)
+			return err
+		}
+		policies = append(policies, systemPolicies...)
+	}
+	if len(policies) == 0 {
+		log.Debugf("cannot find policy for %s event: %v", payload.Type, value)
+		return nil
+	}
+
+	if projectID > 0 && len(payload.EventData.Project.Name) == 0 {
+		prj, err := project.Ctl.Get(ctx, projectID)
+		if err != nil {
+			log.Errorf("failed to get project %d, error: %v", projectID, err)
+			return err
+		}
+		payload.EventData.Project.Name = prj.Name
+	}
+
+	return util.SendHookWithPolicies(policies, payload, payload.Type)
+}
+
+// IsStateful ...
+func (p *Handler) IsStateful() bool {
+	return false
+}
+
+// constructPayload builds the payload of the event and returns the ID of the project that the event belongs to
+func constructPayload(value interface{}) (*model.Payload, int64, error) {
+	var (
+		payload   *model.Payload
+		projectID int64
+	)
+	switch e := value.(type) {
+	case *event.CreateProjectEvent:
+		if e == nil {
+			return nil, 0, errors.New("nil create project event")
+		}
+		projectID = e.ProjectID
+		payload = newPayload(e.EventType, e.Operator, e.OccurAt.Unix(), e.ProjectID, e.Project)
+	case *event.DeleteProjectEvent:
+		if e == nil {
+			return nil, 0, errors.New("nil delete project event")
+		}
+		projectID = e.ProjectID
+		payload = newPayload(e.EventType, e.Operator, e.OccurAt.Unix(), e.ProjectID, e.Project)
+	case *event.MemberEvent:
+		if e == nil {
+			return nil, 0, errors.New("nil member event")
+		}
+		projectID = e.ProjectID
+		payload = newPayload(e.EventType, e.Operator, e.OccurAt.Unix(), e.ProjectID, e.Project)
+		payload.EventData.Member = &eventModel.Member{
+			ID:         e.MemberID,
+			EntityID:   e.EntityID,
+			EntityName: e.EntityName,
+			EntityType: e.EntityType,
+			Role:       e.Role,
+		}
+	case *event.RobotEvent:
+		if e == nil {
+			return nil, 0, errors.New("nil robot event")
+		}
+		projectID = e.ProjectID
+		payload = newPayload(e.EventType, e.Operator, e.OccurAt.Unix(), e.ProjectID, "")
+		payload.EventData.Robot = &eventModel.Robot{
+			ID:        e.RobotID,
+			Name:      e.Name,
+			Level:     e.Level,
+			ExpiresAt: e.ExpiresAt,
+		}
+	case *event.PolicyEvent:
+		if e == nil {
+			return nil, 0, errors.New("nil policy event")
+		}
+		projectID = e.ProjectID
+		payload = newPayload(e.EventType, e.Operator, e.OccurAt.Unix(), e.ProjectID, "")
+		payload.EventData.Policy = &eventModel.Policy{
+			ID:   e.PolicyID,
+			Name: e.PolicyName,
+			Type: e.PolicyType,
+		}
+	default:
+		return nil, 0, errors.Errorf("invalid project event type %T", value)
+	}
+	return payload, projectID, nil
+}
+
+func newPayload(eventType, operator string, occurAt, projectID int64, projectName string) *model.Payload {
+	return &model.Payload{
+		Type:     eventType,
+		OccurAt:  occurAt,
+		Operator: operator,
+		EventData: &model.EventData{
+			Project: &eventModel.Project{
+				ID:   projectID,
+				Name: projectName,
+			},
+		},
+	}
+}
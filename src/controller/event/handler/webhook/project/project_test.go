@@ -0,0 +1,174 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/lib/config"
+	_ "github.com/goharbor/harbor/src/pkg/config/inmemory"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	testing_notification "github.com/goharbor/harbor/src/testing/pkg/notification/policy"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type projectHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (p *projectHandlerTestSuite) SetupSuite() {
+	config.InitWithSettings(map[string]interface{}{
+		common.NotificationEnable: true,
+	})
+}
+
+func (p *projectHandlerTestSuite) TestConstructPayload() {
+	now := time.Now()
+	// member event
+	payload, projectID, err := constructPayload(&event.MemberEvent{
+		EventType:  event.TopicAddMember,
+		ProjectID:  1,
+		Project:    "library",
+		MemberID:   2,
+		EntityID:   3,
+		EntityName: "tester",
+		EntityType: "u",
+		Role:       "developer",
+		Operator:   "admin",
+		OccurAt:    now,
+	})
+	p.Require().Nil(err)
+	p.Equal(int64(1), projectID)
+	p.Equal(event.TopicAddMember, payload.Type)
+	p.Equal("admin", payload.Operator)
+	p.Equal(now.Unix(), payload.OccurAt)
+	p.Equal("library", payload.EventData.Project.Name)
+	p.Require().NotNil(payload.EventData.Member)
+	p.Equal("tester", payload.EventData.Member.EntityName)
+	p.Equal("developer", payload.EventData.Member.Role)
+
+	// robot event
+	payload, projectID, err = constructPayload(&event.RobotEvent{
+		EventType: event.TopicRobotExpired,
+		RobotID:   1,
+		Name:      "robot$library+test",
+		Level:     "project",
+		ProjectID: 1,
+		ExpiresAt: now.Unix(),
+		OccurAt:   now,
+	})
+	p.Require().Nil(err)
+	p.Equal(int64(1), projectID)
+	p.Equal(event.TopicRobotExpired, payload.Type)
+	p.Require().NotNil(payload.EventData.Robot)
+	p.Equal("robot$library+test", payload.EventData.Robot.Name)
+	p.Equal(now.Unix(), payload.EventData.Robot.ExpiresAt)
+
+	// policy event
+	payload, projectID, err = constructPayload(&event.PolicyEvent{
+		EventType:  event.TopicDeletePolicy,
+		PolicyType: event.PolicyTypeReplication,
+		PolicyID:   1,
+		PolicyName: "rule",
+		ProjectID:  2,
+		OccurAt:    now,
+	})
+	p.Require().Nil(err)
+	p.Equal(int64(2), projectID)
+	p.Equal(event.TopicDeletePolicy, payload.Type)
+	p.Require().NotNil(payload.EventData.Policy)
+	p.Equal("rule", payload.EventData.Policy.Name)
+	p.Equal(event.PolicyTypeReplication, payload.EventData.Policy.Type)
+
+	// project event
+	payload, projectID, err = constructPayload(&event.CreateProjectEvent{
+		EventType: event.TopicCreateProject,
+		ProjectID: 1,
+		Project:   "library",
+		OccurAt:   now,
+	})
+	p.Require().Nil(err)
+	p.Equal(int64(1), projectID)
+	p.Equal(event.TopicCreateProject, payload.Type)
+	p.Equal("library", payload.EventData.Project.Name)
+
+	// unsupported event
+	_, _, err = constructPayload(&event.QuotaEvent{})
+	p.NotNil(err)
+}
+
+func (p *projectHandlerTestSuite) TestHandleWithoutProject() {
+	mgr := notification.PolicyMgr
+	defer func() {
+		notification.PolicyMgr = mgr
+	}()
+	// only the system level policies are looked up for the system level robot
+	policyMgr := &testing_notification.Manager{}
+	policyMgr.On("GetRelatedPolices", mock.Anything, policy_model.SystemLevelProjectID, event.TopicCreateRobot).Return(nil, nil).Once()
+	notification.PolicyMgr = policyMgr
+
+	handler := &Handler{}
+	p.Nil(handler.Handle(context.TODO(), &event.RobotEvent{
+		EventType: event.TopicCreateRobot,
+		RobotID:   1,
+		Name:      "robot$test",
+		Level:     "system",
+		OccurAt:   time.Now(),
+	}))
+	policyMgr.AssertExpectations(p.T())
+}
+
+func (p *projectHandlerTestSuite) TestHandleProjectCreation() {
+	mgr := notification.PolicyMgr
+	defer func() {
+		notification.PolicyMgr = mgr
+	}()
+	// both the project and system level policies are looked up for the project creation
+	policyMgr := &testing_notification.Manager{}
+	policyMgr.On("GetRelatedPolices", mock.Anything, int64(1), event.TopicCreateProject).Return(nil, nil).Once()
+	policyMgr.On("GetRelatedPolices", mock.Anything, policy_model.SystemLevelProjectID, event.TopicCreateProject).Return(nil, nil).Once()
+	notification.PolicyMgr = policyMgr
+
+	handler := &Handler{}
+	p.Nil(handler.Handle(context.TODO(), &event.CreateProjectEvent{
+		EventType: event.TopicCreateProject,
+		ProjectID: 1,
+		Project:   "library",
+		OccurAt:   time.Now(),
+	}))
+	policyMgr.AssertExpectations(p.T())
+
+	// only the project level policies are looked up for the other project events
+	policyMgr = &testing_notification.Manager{}
+	policyMgr.On("GetRelatedPolices", mock.Anything, int64(1), event.TopicAddMember).Return(nil, nil).Once()
+	notification.PolicyMgr = policyMgr
+	p.Nil(handler.Handle(context.TODO(), &event.MemberEvent{
+		EventType: event.TopicAddMember,
+		ProjectID: 1,
+		Project:   "library",
+		OccurAt:   time.Now(),
+	}))
+	policyMgr.AssertExpectations(p.T())
+}
+
+func TestProjectHandlerTestSuite(t *testing.T) {
+	suite.Run(t, &projectHandlerTestSuite{})
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+// MemberMetaData defines the metadata of project member event
+type MemberMetaData struct {
+	// the topic of event: add member, update member role or remove member
+	Topic      string
+	ProjectID  int64
+	Project    string
+	MemberID   int
+	EntityID   int
+	EntityName string
+	EntityType string
+	Role       string
+	Operator   string
+}
+
+// Resolve project member metadata into project member event
+func (m *MemberMetaData) Resolve(evt *event.Event) error {
+	switch m.Topic {
+	case event2.TopicAddMember, event2.TopicUpdateMemberRole, event2.TopicRemoveMember:
+	default:
+		return fmt.Errorf("not supported member event topic %s", m.Topic)
+	}
+
+	evt.Topic = m.Topic
+	evt.Data = &event2.MemberEvent{
+		EventType:  m.Topic,
+		ProjectID:  m.ProjectID,
+		Project:    m.Project,
+		MemberID:   m.MemberID,
+		EntityID:   m.EntityID,
+		EntityName: m.EntityName,
+		EntityType: m.EntityType,
+		Role:       m.Role,
+		Operator:   m.Operator,
+		OccurAt:    time.Now(),
+	}
+	return nil
+}
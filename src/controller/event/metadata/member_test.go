@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/stretchr/testify/suite"
+)
+
+type memberEventTestSuite struct {
+	suite.Suite
+}
+
+func (m *memberEventTestSuite) TestResolve() {
+	e := &event.Event{}
+	metadata := &MemberMetaData{
+		Topic:      event2.TopicAddMember,
+		ProjectID:  1,
+		Project:    "library",
+		MemberID:   2,
+		EntityID:   3,
+		EntityName: "tester",
+		EntityType: "u",
+		Role:       "developer",
+		Operator:   "admin",
+	}
+	err := metadata.Resolve(e)
+	m.Require().Nil(err)
+	m.Equal(event2.TopicAddMember, e.Topic)
+	data, ok := e.Data.(*event2.MemberEvent)
+	m.Require().True(ok)
+	m.Equal(event2.TopicAddMember, data.EventType)
+	m.Equal("library", data.Project)
+	m.Equal("tester", data.EntityName)
+	m.Equal("developer", data.Role)
+	m.Equal("admin", data.Operator)
+}
+
+func (m *memberEventTestSuite) TestResolveUnsupportedTopic() {
+	metadata := &MemberMetaData{
+		Topic: event2.TopicCreateRobot,
+	}
+	m.NotNil(metadata.Resolve(&event.Event{}))
+}
+
+func TestMemberEventTestSuite(t *testing.T) {
+	suite.Run(t, &memberEventTestSuite{})
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+// PolicyMetaData defines the metadata of retention, immutable and replication policy event
+type PolicyMetaData struct {
+	// the topic of event: create, update or delete policy
+	Topic string
+	// the type of policy: retention, immutable or replication
+	PolicyType string
+	PolicyID   int64
+	PolicyName string
+	ProjectID  int64
+	Operator   string
+}
+
+// Resolve policy metadata into policy event
+func (p *PolicyMetaData) Resolve(evt *event.Event) error {
+	switch p.Topic {
+	case event2.TopicCreatePolicy, event2.TopicUpdatePolicy, event2.TopicDeletePolicy:
+	default:
+		return fmt.Errorf("not supported policy event topic %s", p.Topic)
+	}
+	switch p.PolicyType {
+	case event2.PolicyTypeRetention, event2.PolicyTypeImmutable, event2.PolicyTypeReplication:
+	default:
+		return fmt.Errorf("not supported policy type %s", p.PolicyType)
+	}
+
+	evt.Topic = p.Topic
+	evt.Data = &event2.PolicyEvent{
+		EventType:  p.Topic,
+		PolicyType: p.PolicyType,
+		PolicyID:   p.PolicyID,
+		PolicyName: p.PolicyName,
+		ProjectID:  p.ProjectID,
+		Operator:   p.Operator,
+		OccurAt:    time.Now(),
+	}
+	return nil
+}
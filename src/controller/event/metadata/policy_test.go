@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/stretchr/testify/suite"
+)
+
+type policyEventTestSuite struct {
+	suite.Suite
+}
+
+func (p *policyEventTestSuite) TestResolve() {
+	e := &event.Event{}
+	metadata := &PolicyMetaData{
+		Topic:      event2.TopicUpdatePolicy,
+		PolicyType: event2.PolicyTypeImmutable,
+		PolicyID:   1,
+		ProjectID:  2,
+		Operator:   "admin",
+	}
+	err := metadata.Resolve(e)
+	p.Require().Nil(err)
+	p.Equal(event2.TopicUpdatePolicy, e.Topic)
+	data, ok := e.Data.(*event2.PolicyEvent)
+	p.Require().True(ok)
+	p.Equal(event2.TopicUpdatePolicy, data.EventType)
+	p.Equal(event2.PolicyTypeImmutable, data.PolicyType)
+	p.Equal(int64(1), data.PolicyID)
+	p.Equal(int64(2), data.ProjectID)
+	p.Equal("admin", data.Operator)
+}
+
+func (p *policyEventTestSuite) TestResolveUnsupported() {
+	metadata := &PolicyMetaData{
+		Topic:      event2.TopicDeleteProject,
+		PolicyType: event2.PolicyTypeRetention,
+	}
+	p.NotNil(metadata.Resolve(&event.Event{}))
+
+	metadata = &PolicyMetaData{
+		Topic:      event2.TopicCreatePolicy,
+		PolicyType: "preheat",
+	}
+	p.NotNil(metadata.Resolve(&event.Event{}))
+}
+
+func TestPolicyEventTestSuite(t *testing.T) {
+	suite.Run(t, &policyEventTestSuite{})
+}
@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+// RobotMetaData defines the metadata of robot account event
+type RobotMetaData struct {
+	// the topic of event: create robot or robot expired
+	Topic     string
+	RobotID   int64
+	Name      string
+	Level     string
+	ProjectID int64
+	ExpiresAt int64
+	Operator  string
+}
+
+// Resolve robot account metadata into robot account event
+func (r *RobotMetaData) Resolve(evt *event.Event) error {
+	switch r.Topic {
+	case event2.TopicCreateRobot, event2.TopicRobotExpired:
+	default:
+		return fmt.Errorf("not supported robot event topic %s", r.Topic)
+	}
+
+	evt.Topic = r.Topic
+	evt.Data = &event2.RobotEvent{
+		EventType: r.Topic,
+		RobotID:   r.RobotID,
+		Name:      r.Name,
+		Level:     r.Level,
+		ProjectID: r.ProjectID,
+		ExpiresAt: r.ExpiresAt,
+		Operator:  r.Operator,
+		OccurAt:   time.Now(),
+	}
+	return nil
+}
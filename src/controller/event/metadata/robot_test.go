@@ -0,0 +1,60 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/stretchr/testify/suite"
+)
+
+type robotEventTestSuite struct {
+	suite.Suite
+}
+
+func (r *robotEventTestSuite) TestResolve() {
+	e := &event.Event{}
+	metadata := &RobotMetaData{
+		Topic:     event2.TopicRobotExpired,
+		RobotID:   1,
+		Name:      "robot$library+test",
+		Level:     "project",
+		ProjectID: 2,
+		ExpiresAt: 1609459200,
+	}
+	err := metadata.Resolve(e)
+	r.Require().Nil(err)
+	r.Equal(event2.TopicRobotExpired, e.Topic)
+	data, ok := e.Data.(*event2.RobotEvent)
+	r.Require().True(ok)
+	r.Equal(event2.TopicRobotExpired, data.EventType)
+	r.Equal(int64(1), data.RobotID)
+	r.Equal("robot$library+test", data.Name)
+	r.Equal(int64(2), data.ProjectID)
+	r.Equal(int64(1609459200), data.ExpiresAt)
+}
+
+func (r *robotEventTestSuite) TestResolveUnsupportedTopic() {
+	metadata := &RobotMetaData{
+		Topic: event2.TopicAddMember,
+	}
+	r.NotNil(metadata.Resolve(&event.Event{}))
+}
+
+func TestRobotEventTestSuite(t *testing.T) {
+	suite.Run(t, &robotEventTestSuite{})
+}
@@ -59,3 +59,33 @@ type RetentionRule struct {
 	// Selector attached to the rule for filtering scope (e.g: repositories or namespaces)
 	ScopeSelectors map[string][]*rule.Selector `json:"scope_selectors,omitempty"`
 }
+
+// Project describes the project infos
+type Project struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Member describes the project member infos
+type Member struct {
+	ID         int    `json:"id"`
+	EntityID   int    `json:"entity_id,omitempty"`
+	EntityName string `json:"entity_name,omitempty"`
+	EntityType string `json:"entity_type,omitempty"`
+	Role       string `json:"role,omitempty"`
+}
+
+// Robot describes the robot account infos
+type Robot struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Level     string `json:"level,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// Policy describes the retention, immutable or replication policy infos
+type Policy struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
@@ -49,6 +49,24 @@ const (
 	TopicReplication     = "REPLICATION"
 	TopicArtifactLabeled = "ARTIFACT_LABELED"
 	TopicTagRetention    = "TAG_RETENTION"
+	// the topics of the project member changes
+	TopicAddMember        = "ADD_MEMBER"
+	TopicUpdateMemberRole = "UPDATE_MEMBER_ROLE"
+	TopicRemoveMember     = "REMOVE_MEMBER"
+	// the topics of the robot account changes
+	TopicCreateRobot  = "CREATE_ROBOT"
+	TopicRobotExpired = "ROBOT_EXPIRED"
+	// the topics of the retention, immutable and replication policy changes
+	TopicCreatePolicy = "CREATE_POLICY"
+	TopicUpdatePolicy = "UPDATE_POLICY"
+	TopicDeletePolicy = "DELETE_POLICY"
+)
+
+// the types of the policies whose changes are published
+const (
+	PolicyTypeRetention   = "retention"
+	PolicyTypeImmutable   = "immutable"
+	PolicyTypeReplication = "replication"
 )
 
 // CreateProjectEvent is the creating project event
@@ -382,3 +400,55 @@ func (r *RetentionEvent) String() string {
 	return fmt.Sprintf("TaskID-%d Status-%s Deleted-%s OccurAt-%s",
 		r.TaskID, r.Status, candidates, r.OccurAt.Format("2006-01-02 15:04:05"))
 }
+
+// MemberEvent is project member related event data to publish
+type MemberEvent struct {
+	EventType  string
+	ProjectID  int64
+	Project    string
+	MemberID   int
+	EntityID   int
+	EntityName string
+	EntityType string
+	Role       string
+	Operator   string
+	OccurAt    time.Time
+}
+
+func (m *MemberEvent) String() string {
+	return fmt.Sprintf("Project-%s MemberID-%d Entity-%s(%s) Role-%s Operator-%s OccurAt-%s",
+		m.Project, m.MemberID, m.EntityName, m.EntityType, m.Role, m.Operator, m.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// RobotEvent is robot account related event data to publish
+type RobotEvent struct {
+	EventType string
+	RobotID   int64
+	Name      string
+	Level     string
+	ProjectID int64
+	ExpiresAt int64
+	Operator  string
+	OccurAt   time.Time
+}
+
+func (r *RobotEvent) String() string {
+	return fmt.Sprintf("RobotID-%d Name-%s Level-%s ProjectID-%d ExpiresAt-%d Operator-%s OccurAt-%s",
+		r.RobotID, r.Name, r.Level, r.ProjectID, r.ExpiresAt, r.Operator, r.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// PolicyEvent is retention, immutable and replication policy related event data to publish
+type PolicyEvent struct {
+	EventType  string
+	PolicyType string
+	PolicyID   int64
+	PolicyName string
+	ProjectID  int64
+	Operator   string
+	OccurAt    time.Time
+}
+
+func (p *PolicyEvent) String() string {
+	return fmt.Sprintf("PolicyType-%s PolicyID-%d PolicyName-%s ProjectID-%d Operator-%s OccurAt-%s",
+		p.PolicyType, p.PolicyID, p.PolicyName, p.ProjectID, p.Operator, p.OccurAt.Format("2006-01-02 15:04:05"))
+}
@@ -3,8 +3,13 @@ package immutable
 import (
 	"context"
 	"fmt"
+
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/immutable"
+	"github.com/goharbor/harbor/src/pkg/notification"
 
 	"github.com/goharbor/harbor/src/pkg/immutable/model"
 )
@@ -47,12 +52,27 @@ func (r *DefaultAPIController) GetImmutableRule(ctx context.Context, id int64) (
 
 // DeleteImmutableRule ...
 func (r *DefaultAPIController) DeleteImmutableRule(ctx context.Context, id int64) error {
-	return r.manager.DeleteImmutableRule(ctx, id)
+	m, err := r.manager.GetImmutableRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = r.manager.DeleteImmutableRule(ctx, id); err != nil {
+		return err
+	}
+	if m != nil {
+		fireEvent(ctx, event.TopicDeletePolicy, id, m.ProjectID)
+	}
+	return nil
 }
 
 // CreateImmutableRule ...
 func (r *DefaultAPIController) CreateImmutableRule(ctx context.Context, m *model.Metadata) (int64, error) {
-	return r.manager.CreateImmutableRule(ctx, m)
+	id, err := r.manager.CreateImmutableRule(ctx, m)
+	if err != nil {
+		return 0, err
+	}
+	fireEvent(ctx, event.TopicCreatePolicy, id, m.ProjectID)
+	return id, nil
 }
 
 // UpdateImmutableRule ...
@@ -65,9 +85,15 @@ func (r *DefaultAPIController) UpdateImmutableRule(ctx context.Context, projectI
 		return fmt.Errorf("the immutable tag rule is not found id:%v", m.ID)
 	}
 	if m0.Disabled != m.Disabled {
-		return r.manager.EnableImmutableRule(ctx, m.ID, m.Disabled)
+		err = r.manager.EnableImmutableRule(ctx, m.ID, m.Disabled)
+	} else {
+		err = r.manager.UpdateImmutableRule(ctx, projectID, m)
 	}
-	return r.manager.UpdateImmutableRule(ctx, projectID, m)
+	if err != nil {
+		return err
+	}
+	fireEvent(ctx, event.TopicUpdatePolicy, m.ID, projectID)
+	return nil
 }
 
 // ListImmutableRules ...
@@ -80,6 +106,17 @@ func (r *DefaultAPIController) Count(ctx context.Context, query *q.Query) (int64
 	return r.manager.Count(ctx, query)
 }
 
+// fireEvent adds the immutable rule event into the context
+func fireEvent(ctx context.Context, topic string, id, projectID int64) {
+	notification.AddEvent(ctx, &metadata.PolicyMetaData{
+		Topic:      topic,
+		PolicyType: event.PolicyTypeImmutable,
+		PolicyID:   id,
+		ProjectID:  projectID,
+		Operator:   operator.FromContext(ctx),
+	})
+}
+
 // NewAPIController ...
 func NewAPIController(immutableMgr immutable.Manager) Controller {
 	return &DefaultAPIController{
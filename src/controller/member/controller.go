@@ -19,12 +19,18 @@ import (
 	"fmt"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/core/auth"
 	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/member"
 	"github.com/goharbor/harbor/src/pkg/member/models"
+	"github.com/goharbor/harbor/src/pkg/notification"
 	"github.com/goharbor/harbor/src/pkg/project"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/user"
 	"github.com/goharbor/harbor/src/pkg/usergroup"
 )
@@ -100,7 +106,11 @@ func (c *controller) UpdateRole(ctx context.Context, projectNameOrID interface{}
 	if p == nil {
 		return errors.BadRequestError(nil).WithMessage("project is not found")
 	}
-	return c.mgr.UpdateRole(ctx, p.ProjectID, memberID, role)
+	if err := c.mgr.UpdateRole(ctx, p.ProjectID, memberID, role); err != nil {
+		return err
+	}
+	c.fireEvent(ctx, event.TopicUpdateMemberRole, p, memberID, nil)
+	return nil
 }
 
 func (c *controller) Get(ctx context.Context, projectNameOrID interface{}, memberID int) (*models.Member, error) {
@@ -206,7 +216,12 @@ func (c *controller) Create(ctx context.Context, projectNameOrID interface{}, re
 		// Return invalid role error
 		return 0, ErrInvalidRole
 	}
-	return c.mgr.AddProjectMember(ctx, member)
+	id, err := c.mgr.AddProjectMember(ctx, member)
+	if err != nil {
+		return 0, err
+	}
+	c.fireEvent(ctx, event.TopicAddMember, p, id, nil)
+	return id, nil
 }
 
 func isValidRole(role int) bool {
@@ -242,5 +257,41 @@ func (c *controller) Delete(ctx context.Context, projectNameOrID interface{}, me
 	if err != nil {
 		return err
 	}
-	return c.mgr.Delete(ctx, p.ProjectID, memberID)
+	// get the member before deleting for the event
+	m, err := c.mgr.Get(ctx, p.ProjectID, memberID)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return c.mgr.Delete(ctx, p.ProjectID, memberID)
+		}
+		return err
+	}
+	if err := c.mgr.Delete(ctx, p.ProjectID, memberID); err != nil {
+		return err
+	}
+	c.fireEvent(ctx, event.TopicRemoveMember, p, memberID, m)
+	return nil
+}
+
+// fireEvent adds the project member event into the context, the member is
+// loaded by the ID if it isn't provided. The failure only is logged as the
+// member change has been done
+func (c *controller) fireEvent(ctx context.Context, topic string, p *proModels.Project, memberID int, m *models.Member) {
+	if m == nil {
+		var err error
+		if m, err = c.mgr.Get(ctx, p.ProjectID, memberID); err != nil {
+			log.Errorf("failed to get the member %d of project %d for %s event: %v", memberID, p.ProjectID, topic, err)
+			return
+		}
+	}
+	notification.AddEvent(ctx, &metadata.MemberMetaData{
+		Topic:      topic,
+		ProjectID:  p.ProjectID,
+		Project:    p.Name,
+		MemberID:   m.ID,
+		EntityID:   m.EntityID,
+		EntityName: m.Entityname,
+		EntityType: m.EntityType,
+		Role:       m.Rolename,
+		Operator:   operator.FromContext(ctx),
+	})
 }
@@ -23,7 +23,6 @@ import (
 	"time"
 
 	"github.com/docker/distribution"
-	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/blob"
 	"github.com/goharbor/harbor/src/controller/event/operator"
@@ -44,6 +43,10 @@ const (
 	sleepIntervalSec    = 20
 	// keep manifest list in cache for one week
 	manifestListCacheInterval = 7 * 24 * 60 * 60 * time.Second
+	// keep the validation time of the cached manifest for one month
+	manifestValidatedInterval = 30 * 24 * 60 * 60 * time.Second
+	// keep the tags listed from the remote server in cache for 5 minutes
+	tagListCacheInterval = 5 * 60 * time.Second
 )
 
 var (
@@ -68,6 +71,11 @@ type Controller interface {
 	HeadManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) (bool, *distribution.Descriptor, error)
 	// EnsureTag ensure tag for digest
 	EnsureTag(ctx context.Context, art lib.ArtifactInfo, tagName string) error
+	// CachedManifestAge returns how long the manifest cached locally hasn't been validated against the remote server,
+	// the exist is false when the manifest isn't cached
+	CachedManifestAge(ctx context.Context, art lib.ArtifactInfo) (age time.Duration, exist bool, err error)
+	// ProxyTags lists the tags of the repository in the remote server, the result is cached for a while
+	ProxyTags(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) ([]string, error)
 }
 
 type controller struct {
@@ -165,7 +173,7 @@ func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo,
 		err = c.cache.Fetch(getManifestListKey(art.Repository, string(desc.Digest)), &content)
 		if err == nil {
 			log.Debugf("Get the manifest list with key=cache:%v", getManifestListKey(art.Repository, string(desc.Digest)))
-			return true, &ManifestList{content, string(desc.Digest), manifestlist.MediaTypeManifestList}, nil
+			return true, &ManifestList{content, string(desc.Digest), manifestListContentType(content)}, nil
 		}
 		if err == cache.ErrNotFound {
 			log.Debugf("Digest is not found in manifest list cache, key=cache:%v", getManifestListKey(art.Repository, string(desc.Digest)))
@@ -173,7 +181,48 @@ func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo,
 			log.Errorf("Failed to get manifest list from cache, error: %v", err)
 		}
 	}
-	return a != nil && string(desc.Digest) == a.Digest, nil, nil // digest matches
+	matched := a != nil && string(desc.Digest) == a.Digest // digest matches
+	if matched {
+		c.markValidated(art)
+	}
+	return matched, nil, nil
+}
+
+// markValidated records the time that the tag is validated against the remote server
+func (c *controller) markValidated(art lib.ArtifactInfo) {
+	if c.cache == nil || len(art.Tag) == 0 {
+		return
+	}
+	if err := c.cache.Save(getManifestValidatedKey(art.Repository, art.Tag), time.Now().Unix(), manifestValidatedInterval); err != nil {
+		log.Warningf("failed to save the validation time of %s:%s, error: %v", art.Repository, art.Tag, err)
+	}
+}
+
+func (c *controller) CachedManifestAge(ctx context.Context, art lib.ArtifactInfo) (time.Duration, bool, error) {
+	a, err := c.local.GetManifest(ctx, art)
+	if err != nil {
+		return 0, false, err
+	}
+	if a == nil {
+		return 0, false, nil
+	}
+	// fallback to the push time if the tag has never been validated since cached
+	validated := a.PushTime
+	if c.cache != nil && len(art.Tag) > 0 {
+		var ts int64
+		if err := c.cache.Fetch(getManifestValidatedKey(art.Repository, art.Tag), &ts); err == nil {
+			validated = time.Unix(ts, 0)
+		}
+	}
+	return time.Since(validated), true, nil
+}
+
+func getManifestValidatedKey(repo, tag string) string {
+	return "manifestvalidated:" + repo + ":" + tag
+}
+
+func getTagListKey(repo string) string {
+	return "taglist:" + repo
 }
 
 func getManifestListKey(repo, dig string) string {
@@ -194,6 +243,7 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 		}
 		return man, err
 	}
+	c.markValidated(art)
 	ct, _, err := man.Payload()
 	if err != nil {
 		return man, err
@@ -207,12 +257,14 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 			log.Errorf("failed to get manifest, error %v", err)
 		}
 		// Push manifest to local when pull with digest, or artifact not found, or digest mismatch
+		pushed := false
 		if len(art.Tag) == 0 || a == nil || a.Digest != dig {
 			artInfo := art
 			if len(artInfo.Digest) == 0 {
 				artInfo.Digest = dig
 			}
 			c.waitAndPushManifest(ctx, remoteRepo, man, artInfo, ct, remote)
+			pushed = true
 		}
 
 		// Query artifact after push
@@ -223,6 +275,12 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 			}
 		}
 		if a != nil {
+			// record the upstream registry which the cached artifact comes from
+			if upstream := remote.Upstream(); pushed && upstream > 0 {
+				if err := c.local.UpdateUpstream(bCtx, a, upstream); err != nil {
+					log.Errorf("failed to record the upstream registry of artifact %s@%s, error %v", a.RepositoryName, a.Digest, err)
+				}
+			}
 			SendPullEvent(a, art.Tag, operator)
 		}
 	}(operator.FromContext(ctx))
@@ -230,6 +288,29 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 	return man, nil
 }
 
+func (c *controller) ProxyTags(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) ([]string, error) {
+	var tags []string
+	if c.cache != nil {
+		err := c.cache.Fetch(getTagListKey(art.Repository), &tags)
+		if err == nil {
+			return tags, nil
+		}
+		if err != cache.ErrNotFound {
+			log.Errorf("failed to get the tag list of %s from cache, error: %v", art.Repository, err)
+		}
+	}
+	tags, err := remote.ListTags(getRemoteRepo(art))
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		if err := c.cache.Save(getTagListKey(art.Repository), tags, tagListCacheInterval); err != nil {
+			log.Warningf("failed to cache the tag list of %s, error: %v", art.Repository, err)
+		}
+	}
+	return tags, nil
+}
+
 func (c *controller) HeadManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) (bool, *distribution.Descriptor, error) {
 	remoteRepo := getRemoteRepo(art)
 	ref := getReference(art)
@@ -239,7 +320,7 @@ func (c *controller) HeadManifest(ctx context.Context, art lib.ArtifactInfo, rem
 func (c *controller) ProxyBlob(ctx context.Context, p *proModels.Project, art lib.ArtifactInfo) (int64, io.ReadCloser, error) {
 	remoteRepo := getRemoteRepo(art)
 	log.Debugf("The blob doesn't exist, proxy the request to the target server, url:%v", remoteRepo)
-	rHelper, err := NewRemoteHelper(ctx, p.RegistryID)
+	rHelper, err := NewRemoteHelper(ctx, p.ProxyRegistryIDs()...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -20,15 +20,17 @@ import (
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/blob"
 	"github.com/goharbor/harbor/src/lib"
-	_ "github.com/goharbor/harbor/src/lib/cache"
+	"github.com/goharbor/harbor/src/lib/cache"
 	"github.com/goharbor/harbor/src/lib/errors"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	testproxy "github.com/goharbor/harbor/src/testing/controller/proxy"
+	testcache "github.com/goharbor/harbor/src/testing/lib/cache"
 	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"io"
 	"testing"
+	"time"
 )
 
 type localInterfaceMock struct {
@@ -59,7 +61,8 @@ func (l *localInterfaceMock) BlobExist(ctx context.Context, art lib.ArtifactInfo
 }
 
 func (l *localInterfaceMock) PushBlob(localRepo string, desc distribution.Descriptor, bReader io.ReadCloser) error {
-	panic("implement me")
+	args := l.Called(localRepo, desc, bReader)
+	return args.Error(0)
 }
 
 func (l *localInterfaceMock) PushManifest(repo string, tag string, manifest distribution.Manifest) error {
@@ -72,12 +75,22 @@ func (l *localInterfaceMock) PushManifestList(ctx context.Context, repo string,
 }
 
 func (l *localInterfaceMock) CheckDependencies(ctx context.Context, repo string, man distribution.Manifest) []distribution.Descriptor {
-	panic("implement me")
+	args := l.Called(ctx, repo, man)
+	var descs []distribution.Descriptor
+	if args.Get(0) != nil {
+		descs = args.Get(0).([]distribution.Descriptor)
+	}
+	return descs
 }
 
 func (l *localInterfaceMock) DeleteManifest(repo, ref string) {
 }
 
+func (l *localInterfaceMock) UpdateUpstream(ctx context.Context, art *artifact.Artifact, registryID int64) error {
+	args := l.Called(ctx, art, registryID)
+	return args.Error(0)
+}
+
 type proxyControllerTestSuite struct {
 	suite.Suite
 	local  *localInterfaceMock
@@ -149,6 +162,68 @@ func (p *proxyControllerTestSuite) TestUseLocalBlob_False() {
 	p.Assert().False(result)
 }
 
+func (p *proxyControllerTestSuite) TestCachedManifestAge() {
+	ctx := context.Background()
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+
+	// not cached
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	_, exist, err := p.ctr.CachedManifestAge(ctx, art)
+	p.Require().Nil(err)
+	p.False(exist)
+
+	// never validated since cached, use the push time
+	a := &artifact.Artifact{}
+	a.PushTime = time.Now().Add(-2 * time.Hour)
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(a, nil)
+	c := &testcache.Cache{}
+	c.On("Fetch", mock.Anything, mock.Anything).Return(cache.ErrNotFound).Once()
+	p.ctr.(*controller).cache = c
+	age, exist, err := p.ctr.CachedManifestAge(ctx, art)
+	p.Require().Nil(err)
+	p.True(exist)
+	p.True(age >= 2*time.Hour)
+
+	// validated recently
+	c.On("Fetch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*(args.Get(1).(*int64)) = time.Now().Add(-time.Minute).Unix()
+	}).Return(nil).Once()
+	age, exist, err = p.ctr.CachedManifestAge(ctx, art)
+	p.Require().Nil(err)
+	p.True(exist)
+	p.True(age < time.Hour)
+}
+
+func (p *proxyControllerTestSuite) TestProxyTags() {
+	ctx := context.Background()
+	art := lib.ArtifactInfo{ProjectName: "dockerhub", Repository: "dockerhub/library/hello-world"}
+	c := &testcache.Cache{}
+	p.ctr.(*controller).cache = c
+
+	// cached
+	c.On("Fetch", "taglist:dockerhub/library/hello-world", mock.Anything).Run(func(args mock.Arguments) {
+		*(args.Get(1).(*[]string)) = []string{"latest"}
+	}).Return(nil).Once()
+	tags, err := p.ctr.ProxyTags(ctx, art, p.remote)
+	p.Require().Nil(err)
+	p.Equal([]string{"latest"}, tags)
+
+	// list from the remote server and cache the result
+	c.On("Fetch", "taglist:dockerhub/library/hello-world", mock.Anything).Return(cache.ErrNotFound).Once()
+	p.remote.On("ListTags", "library/hello-world").Return([]string{"latest", "linux"}, nil).Once()
+	c.On("Save", "taglist:dockerhub/library/hello-world", []string{"latest", "linux"}, tagListCacheInterval).Return(nil).Once()
+	tags, err = p.ctr.ProxyTags(ctx, art, p.remote)
+	p.Require().Nil(err)
+	p.Equal([]string{"latest", "linux"}, tags)
+
+	// failed to list from the remote server
+	c.On("Fetch", "taglist:dockerhub/library/hello-world", mock.Anything).Return(cache.ErrNotFound).Once()
+	p.remote.On("ListTags", "library/hello-world").Return(nil, errors.New("unreachable")).Once()
+	_, err = p.ctr.ProxyTags(ctx, art, p.remote)
+	p.NotNil(err)
+	c.AssertExpectations(p.T())
+}
+
 func TestProxyControllerTestSuite(t *testing.T) {
 	suite.Run(t, &proxyControllerTestSuite{})
 }
@@ -0,0 +1,235 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/controller/artifact"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/controller/quota"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/metric"
+	"github.com/goharbor/harbor/src/lib/q"
+	redislib "github.com/goharbor/harbor/src/lib/redis"
+	pkgArtifact "github.com/goharbor/harbor/src/pkg/artifact"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/quota/types"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// the count of the artifacts listed to evict each time
+	evictionBatchSize = 10
+	// the eviction lock expires in case the holder crashes
+	evictionLockExpiration = 5 * time.Minute
+	// how long to wait for the eviction lock held by others
+	evictionLockTimeout       = time.Minute
+	evictionLockRetryInterval = 100 * time.Millisecond
+)
+
+// releases the eviction lock only if it is still held by the caller
+var unlockScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// evictor evicts the least recently pulled artifacts from the proxy cache project to make room for the content
+// being cached when the storage usage nears the quota, so that caching doesn't fail because of the quota.
+// Only the artifacts are deleted, the blobs referenced by them are left to GC
+type evictor struct {
+	projectCtl  project.Controller
+	artifactCtl artifact.Controller
+	artifactMgr pkgArtifact.Manager
+	quotaCtl    quota.Controller
+	// the eviction of the same project is serialized across the core instances to avoid evicting too much
+	lock func(ctx context.Context, projectID int64) (unlock func(), err error)
+}
+
+func newEvictor() *evictor {
+	return &evictor{
+		projectCtl:  project.Ctl,
+		artifactCtl: artifact.Ctl,
+		artifactMgr: pkgArtifact.Mgr,
+		quotaCtl:    quota.Ctl,
+		lock:        lockEviction,
+	}
+}
+
+// ensureSpace evicts the artifacts of the project which the repository belongs to until the storage usage
+// after caching the content with the size doesn't exceed the eviction threshold
+func (e *evictor) ensureSpace(ctx context.Context, repository string, size int64) error {
+	projectName := strings.SplitN(repository, "/", 2)[0]
+	p, err := e.projectCtl.GetByName(ctx, projectName, project.Detail(false))
+	if err != nil {
+		return err
+	}
+	if !p.ProxyEvictionEnabled() {
+		return nil
+	}
+
+	unlock, err := e.lock(ctx, p.ProjectID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	referenceID := quota.ReferenceID(p.ProjectID)
+	// the artifacts failed to be evicted, they are skipped in the following rounds
+	skipped := map[int64]struct{}{}
+	for {
+		excess, err := e.excess(ctx, referenceID, p.ProxyEvictionThreshold(), size)
+		if err != nil {
+			return err
+		}
+		if excess <= 0 {
+			return nil
+		}
+		evicted, err := e.evict(ctx, p, repository, excess, skipped)
+		if err != nil {
+			return err
+		}
+		if evicted == 0 {
+			return errors.Errorf("no artifact can be evicted from project %s, %d bytes exceed the eviction threshold", p.Name, excess)
+		}
+		// the usage is calculated by the blobs which are shared between the artifacts,
+		// refresh it to check whether the room is enough
+		if err := e.quotaCtl.Refresh(ctx, quota.ProjectReference, referenceID, quota.IgnoreLimitation(true)); err != nil {
+			return err
+		}
+	}
+}
+
+// excess returns how many bytes the storage usage exceeds the threshold after caching the content with the size
+func (e *evictor) excess(ctx context.Context, referenceID string, threshold int, size int64) (int64, error) {
+	qt, err := e.quotaCtl.GetByRef(ctx, quota.ProjectReference, referenceID)
+	if err != nil {
+		return 0, err
+	}
+	hard, err := qt.GetHard()
+	if err != nil {
+		return 0, err
+	}
+	used, err := qt.GetUsed()
+	if err != nil {
+		return 0, err
+	}
+	limit, exist := hard[types.ResourceStorage]
+	if !exist || limit == types.UNLIMITED {
+		return 0, nil
+	}
+	return used[types.ResourceStorage] + size - limit*int64(threshold)/100, nil
+}
+
+// evict deletes the least recently pulled artifacts whose total size covers the excess, returns the count of evicted ones.
+// The artifacts failed to be deleted are recorded in the skipped
+func (e *evictor) evict(ctx context.Context, p *proModels.Project, repository string, excess int64, skipped map[int64]struct{}) (int, error) {
+	candidates, err := e.candidates(ctx, p, repository, excess, skipped)
+	if err != nil {
+		return 0, err
+	}
+	evicted := 0
+	for _, art := range candidates {
+		if err := e.artifactCtl.Delete(ctx, art.ID); err != nil {
+			log.Warningf("failed to evict the artifact %s@%s from the proxy cache, error: %v", art.RepositoryName, art.Digest, err)
+			skipped[art.ID] = struct{}{}
+			continue
+		}
+		log.Infof("the artifact %s@%s pulled at %v is evicted from the proxy cache", art.RepositoryName, art.Digest, art.PullTime)
+		metric.ProxyEvictedArtifactCnt.WithLabelValues(p.Name).Inc()
+		evicted++
+	}
+	return evicted, nil
+}
+
+// candidates walks through the artifacts of the project from the least recently pulled one until the total
+// size of the collected ones covers the excess. The artifacts of the repository being cached are kept as
+// the content being cached may reference them, and the ones referenced by the indexes are evicted along
+// with the indexes. Nothing is deleted during the walk, so the pages keep stable
+func (e *evictor) candidates(ctx context.Context, p *proModels.Project, repository string, excess int64,
+	skipped map[int64]struct{}) ([]*artifact.Artifact, error) {
+	var candidates []*artifact.Artifact
+	for page := int64(1); excess > 0; page++ {
+		query := q.New(q.KeyWords{"ProjectID": p.ProjectID})
+		query.Sorts = []*q.Sort{q.NewSort("pull_time", false), q.NewSort("push_time", false)}
+		query.PageNumber = page
+		query.PageSize = evictionBatchSize
+		arts, err := e.artifactCtl.List(ctx, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, art := range arts {
+			if excess <= 0 {
+				break
+			}
+			if _, exist := skipped[art.ID]; exist || art.RepositoryName == repository {
+				continue
+			}
+			parents, err := e.artifactMgr.ListReferences(ctx, q.New(q.KeyWords{"ChildID": art.ID}))
+			if err != nil {
+				return nil, err
+			}
+			if len(parents) > 0 {
+				continue
+			}
+			candidates = append(candidates, art)
+			excess -= art.Size
+		}
+		if len(arts) < evictionBatchSize {
+			break
+		}
+	}
+	return candidates, nil
+}
+
+// lockEviction acquires the eviction lock of the project in redis, waits if the lock is held by others
+func lockEviction(ctx context.Context, projectID int64) (func(), error) {
+	key := fmt.Sprintf("proxy:eviction:lock:%d", projectID)
+	token := utils.GenerateRandomString()
+	deadline := time.Now().Add(evictionLockTimeout)
+	for {
+		conn := redislib.DefaultPool().Get()
+		_, err := redis.String(conn.Do("SET", key, token, "NX", "PX", int64(evictionLockExpiration/time.Millisecond)))
+		conn.Close()
+		if err == nil {
+			return func() {
+				conn := redislib.DefaultPool().Get()
+				defer conn.Close()
+				if _, err := unlockScript.Do(conn, key, token); err != nil {
+					log.Warningf("failed to release the eviction lock of project %d, error: %v", projectID, err)
+				}
+			}, nil
+		}
+		// the lock is held by others
+		if err != redis.ErrNil {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("timeout to acquire the eviction lock of project %d", projectID)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(evictionLockRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,221 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/goharbor/harbor/src/controller/artifact"
+	"github.com/goharbor/harbor/src/controller/quota"
+	pkgArtifact "github.com/goharbor/harbor/src/pkg/artifact"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	quotaModels "github.com/goharbor/harbor/src/pkg/quota/models"
+	artifacttesting "github.com/goharbor/harbor/src/testing/controller/artifact"
+	projecttesting "github.com/goharbor/harbor/src/testing/controller/project"
+	quotatesting "github.com/goharbor/harbor/src/testing/controller/quota"
+	"github.com/goharbor/harbor/src/testing/mock"
+	arttesting "github.com/goharbor/harbor/src/testing/pkg/artifact"
+	"github.com/stretchr/testify/suite"
+)
+
+type evictorTestSuite struct {
+	suite.Suite
+	projectCtl  *projecttesting.Controller
+	artifactCtl *artifacttesting.Controller
+	artifactMgr *arttesting.Manager
+	quotaCtl    *quotatesting.Controller
+	evictor     *evictor
+	locked      int
+	unlocked    int
+}
+
+func (e *evictorTestSuite) SetupTest() {
+	e.projectCtl = &projecttesting.Controller{}
+	e.artifactCtl = &artifacttesting.Controller{}
+	e.artifactMgr = &arttesting.Manager{}
+	e.quotaCtl = &quotatesting.Controller{}
+	e.locked, e.unlocked = 0, 0
+	e.evictor = &evictor{
+		projectCtl:  e.projectCtl,
+		artifactCtl: e.artifactCtl,
+		artifactMgr: e.artifactMgr,
+		quotaCtl:    e.quotaCtl,
+		lock: func(ctx context.Context, projectID int64) (func(), error) {
+			e.locked++
+			return func() { e.unlocked++ }, nil
+		},
+	}
+}
+
+func (e *evictorTestSuite) mockProject(metadata map[string]string) {
+	e.projectCtl.On("GetByName", mock.Anything, "dockerhub", mock.Anything).Return(&proModels.Project{
+		ProjectID:  1,
+		Name:       "dockerhub",
+		RegistryID: 1,
+		Metadata:   metadata,
+	}, nil)
+}
+
+func (e *evictorTestSuite) mockQuota(hard, used string) {
+	e.quotaCtl.On("GetByRef", mock.Anything, quota.ProjectReference, "1").Return(&quotaModels.Quota{
+		Hard: hard,
+		Used: used,
+	}, nil).Once()
+}
+
+// the artifacts are in the repository other than the one being cached in the tests
+func newArtifact(id, size int64) *artifact.Artifact {
+	return &artifact.Artifact{
+		Artifact: pkgArtifact.Artifact{ID: id, RepositoryName: "dockerhub/library/nginx", Size: size},
+	}
+}
+
+// mockNotReferenced mocks the artifacts aren't referenced by any index
+func (e *evictorTestSuite) mockNotReferenced() {
+	e.artifactMgr.On("ListReferences", mock.Anything, mock.Anything).Return(nil, nil)
+}
+
+func (e *evictorTestSuite) TestNotEnabled() {
+	e.mockProject(nil)
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 100))
+	e.quotaCtl.AssertNotCalled(e.T(), "GetByRef", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (e *evictorTestSuite) TestUnderThreshold() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true"})
+	e.mockQuota(`{"storage":1000}`, `{"storage":500}`)
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 100))
+	e.artifactCtl.AssertNotCalled(e.T(), "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (e *evictorTestSuite) TestUnlimited() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true"})
+	e.mockQuota(`{"storage":-1}`, `{"storage":500}`)
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 100))
+	e.artifactCtl.AssertNotCalled(e.T(), "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (e *evictorTestSuite) TestEvict() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true", proModels.ProMetaProxyEvictionThreshold: "80"})
+	// 700 + 200 - 800 = 100 bytes exceed the threshold
+	e.mockQuota(`{"storage":1000}`, `{"storage":700}`)
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return([]*artifact.Artifact{
+		newArtifact(1, 60), newArtifact(2, 60), newArtifact(3, 60),
+	}, nil).Once()
+	e.mockNotReferenced()
+	e.artifactCtl.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(2)).Return(nil).Once()
+	e.quotaCtl.On("Refresh", mock.Anything, quota.ProjectReference, "1", mock.Anything).Return(nil).Once()
+	e.mockQuota(`{"storage":1000}`, `{"storage":580}`)
+
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 200))
+	e.artifactCtl.AssertExpectations(e.T())
+	e.quotaCtl.AssertExpectations(e.T())
+	e.artifactCtl.AssertNotCalled(e.T(), "Delete", mock.Anything, int64(3))
+	e.Equal(1, e.locked)
+	e.Equal(1, e.unlocked)
+}
+
+func (e *evictorTestSuite) TestSkipReferencedAndCachingRepository() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true", proModels.ProMetaProxyEvictionThreshold: "80"})
+	e.mockQuota(`{"storage":1000}`, `{"storage":700}`)
+	caching := newArtifact(1, 60)
+	caching.RepositoryName = "dockerhub/library/hello-world"
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return([]*artifact.Artifact{
+		caching, newArtifact(2, 60), newArtifact(3, 200),
+	}, nil).Once()
+	// the artifact 2 is referenced by an index
+	e.artifactMgr.On("ListReferences", mock.Anything, mock.Anything).Return([]*pkgArtifact.Reference{
+		{ParentID: 4, ChildID: 2},
+	}, nil).Once()
+	e.artifactMgr.On("ListReferences", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(3)).Return(nil).Once()
+	e.quotaCtl.On("Refresh", mock.Anything, quota.ProjectReference, "1", mock.Anything).Return(nil).Once()
+	e.mockQuota(`{"storage":1000}`, `{"storage":500}`)
+
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 200))
+	e.artifactCtl.AssertExpectations(e.T())
+	e.artifactCtl.AssertNotCalled(e.T(), "Delete", mock.Anything, int64(1))
+	e.artifactCtl.AssertNotCalled(e.T(), "Delete", mock.Anything, int64(2))
+}
+
+func (e *evictorTestSuite) TestSkipFailed() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true", proModels.ProMetaProxyEvictionThreshold: "80"})
+	e.mockQuota(`{"storage":1000}`, `{"storage":700}`)
+	e.mockNotReferenced()
+	// failed to delete the artifact 1 in the first round
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return([]*artifact.Artifact{
+		newArtifact(1, 60), newArtifact(2, 60),
+	}, nil).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(1)).Return(fmt.Errorf("failed")).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(2)).Return(nil).Once()
+	e.quotaCtl.On("Refresh", mock.Anything, quota.ProjectReference, "1", mock.Anything).Return(nil).Twice()
+	e.mockQuota(`{"storage":1000}`, `{"storage":640}`)
+	// the artifact 1 is skipped in the second round
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return([]*artifact.Artifact{
+		newArtifact(1, 60), newArtifact(3, 60),
+	}, nil).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(3)).Return(nil).Once()
+	e.mockQuota(`{"storage":1000}`, `{"storage":580}`)
+
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 200))
+	e.artifactCtl.AssertExpectations(e.T())
+	e.quotaCtl.AssertExpectations(e.T())
+}
+
+func (e *evictorTestSuite) TestWalkPages() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true", proModels.ProMetaProxyEvictionThreshold: "80"})
+	e.mockQuota(`{"storage":1000}`, `{"storage":700}`)
+	e.mockNotReferenced()
+	// all the artifacts in the first page belong to the repository being cached
+	var page []*artifact.Artifact
+	for i := 1; i <= evictionBatchSize; i++ {
+		art := newArtifact(int64(i), 60)
+		art.RepositoryName = "dockerhub/library/hello-world"
+		page = append(page, art)
+	}
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return(page, nil).Once()
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return([]*artifact.Artifact{
+		newArtifact(11, 200),
+	}, nil).Once()
+	e.artifactCtl.On("Delete", mock.Anything, int64(11)).Return(nil).Once()
+	e.quotaCtl.On("Refresh", mock.Anything, quota.ProjectReference, "1", mock.Anything).Return(nil).Once()
+	e.mockQuota(`{"storage":1000}`, `{"storage":500}`)
+
+	e.Nil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 200))
+	e.artifactCtl.AssertExpectations(e.T())
+}
+
+func (e *evictorTestSuite) TestLockFailed() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true"})
+	e.evictor.lock = func(ctx context.Context, projectID int64) (func(), error) {
+		return nil, fmt.Errorf("timeout")
+	}
+	e.NotNil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 100))
+	e.quotaCtl.AssertNotCalled(e.T(), "GetByRef", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (e *evictorTestSuite) TestNothingToEvict() {
+	e.mockProject(map[string]string{proModels.ProMetaProxyEviction: "true"})
+	e.mockQuota(`{"storage":1000}`, `{"storage":1000}`)
+	e.artifactCtl.On("List", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	e.NotNil(e.evictor.ensureSpace(context.Background(), "dockerhub/library/hello-world", 100))
+}
+
+func TestEvictorTestSuite(t *testing.T) {
+	suite.Run(t, &evictorTestSuite{})
+}
@@ -24,14 +24,20 @@ import (
 	"github.com/goharbor/harbor/src/lib/config"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/orm"
+	pkgArtifact "github.com/goharbor/harbor/src/pkg/artifact"
 	"github.com/goharbor/harbor/src/pkg/notifier/event"
 	"github.com/goharbor/harbor/src/pkg/proxy/secret"
 	"github.com/goharbor/harbor/src/pkg/registry"
 	"io"
 )
 
-// TrimmedManifestlist - key prefix for trimmed manifest
-const TrimmedManifestlist = "trimmedmanifestlist:"
+const (
+	// TrimmedManifestlist - key prefix for trimmed manifest
+	TrimmedManifestlist = "trimmedmanifestlist:"
+	// UpstreamRegistryAttr - the key of the artifact extra attribute recording the upstream registry which served the artifact
+	UpstreamRegistryAttr = "proxy_upstream_registry_id"
+)
 
 // localInterface defines operations related to local repo under proxy mode
 type localInterface interface {
@@ -47,6 +53,8 @@ type localInterface interface {
 	CheckDependencies(ctx context.Context, repo string, man distribution.Manifest) []distribution.Descriptor
 	// DeleteManifest cleanup delete tag from local cache
 	DeleteManifest(repo, ref string)
+	// UpdateUpstream records the upstream registry which served the artifact
+	UpdateUpstream(ctx context.Context, art *artifact.Artifact, registryID int64) error
 }
 
 func (l *localHelper) GetManifest(ctx context.Context, art lib.ArtifactInfo) (*artifact.Artifact, error) {
@@ -65,7 +73,9 @@ func (l *localHelper) GetManifest(ctx context.Context, art lib.ArtifactInfo) (*a
 type localHelper struct {
 	registry    registry.Client
 	artifactCtl artifactController
+	artifactMgr pkgArtifact.Manager
 	cache       cache.Cache
+	evictor     *evictor
 }
 
 type artifactController interface {
@@ -74,7 +84,7 @@ type artifactController interface {
 
 // newLocalHelper create the localInterface
 func newLocalHelper() localInterface {
-	l := &localHelper{artifactCtl: artifact.Ctl}
+	l := &localHelper{artifactCtl: artifact.Ctl, artifactMgr: pkgArtifact.Mgr, evictor: newEvictor()}
 	l.init()
 	return l
 }
@@ -103,6 +113,12 @@ func (l *localHelper) PushBlob(localRepo string, desc distribution.Descriptor, b
 		return nil
 	}
 	defer inflightChecker.removeRequest(artName)
+	if l.evictor != nil {
+		// make room for the blob, the push may still succeed if the eviction fails
+		if err := l.evictor.ensureSpace(orm.Context(), localRepo, desc.Size); err != nil {
+			log.Warningf("failed to evict the artifacts for caching the blob %s, error: %v", artName, err)
+		}
+	}
 	err := l.registry.PushBlob(localRepo, ref, desc.Size, bReader)
 	return err
 }
@@ -133,6 +149,18 @@ func (l *localHelper) DeleteManifest(repo, ref string) {
 	}
 }
 
+func (l *localHelper) UpdateUpstream(ctx context.Context, art *artifact.Artifact, registryID int64) error {
+	if art.ExtraAttrs == nil {
+		art.ExtraAttrs = map[string]interface{}{}
+	}
+	// the numbers are float64 after unmarshalling from the database
+	if id, ok := art.ExtraAttrs[UpstreamRegistryAttr].(float64); ok && int64(id) == registryID {
+		return nil
+	}
+	art.ExtraAttrs[UpstreamRegistryAttr] = registryID
+	return l.artifactMgr.Update(ctx, &art.Artifact, "ExtraAttrs")
+}
+
 func (l *localHelper) CheckDependencies(ctx context.Context, repo string, man distribution.Manifest) []distribution.Descriptor {
 	descriptors := man.References()
 	waitDesc := make([]distribution.Descriptor, 0)
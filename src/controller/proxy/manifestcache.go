@@ -16,9 +16,12 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest"
 	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/lib"
 	libCache "github.com/goharbor/harbor/src/lib/cache"
@@ -42,7 +45,8 @@ func NewCacheHandlerRegistry(local localInterface) map[string]ManifestCacheHandl
 	registry := map[string]ManifestCacheHandler{
 		manifestlist.MediaTypeManifestList: manListHandler,
 		v1.MediaTypeImageIndex:             manListHandler,
-		schema2.MediaTypeManifest:          manHandler,
+		schema2.MediaTypeManifest:          &ArtifactCache{ManifestCache{local}},
+		v1.MediaTypeImageManifest:          &ArtifactCache{ManifestCache{local}},
 		defaultHandler:                     manHandler,
 	}
 	return registry
@@ -109,7 +113,8 @@ func (m *ManifestListCache) updateManifestList(ctx context.Context, repo string,
 				existMans = append(existMans, ma)
 			}
 		}
-		return manifestlist.FromDescriptors(existMans)
+		// keep the media type, the OCI image index shouldn't be converted to the manifest list
+		return manifestlist.FromDescriptorsWithMediaType(existMans, v.MediaType)
 	}
 	return nil, fmt.Errorf("current manifest list type is unknown, manifest type[%T], content [%+v]", manifest, manifest)
 }
@@ -205,3 +210,58 @@ func (m *ManifestCache) putBlobToLocal(remoteRepo string, localRepo string, desc
 	err = m.local.PushBlob(localRepo, desc, bReader)
 	return err
 }
+
+// ArtifactCache handles the OCI and docker image manifests. The manifests of the images are handled by ManifestCache,
+// and for the non-image artifacts such as Helm charts and WASM modules, the client may never pull some of
+// the blobs (e.g. the config), so the blobs missing in local are pushed directly without waiting
+type ArtifactCache struct {
+	ManifestCache
+}
+
+// CacheContent ...
+func (a *ArtifactCache) CacheContent(ctx context.Context, remoteRepo string, man distribution.Manifest, art lib.ArtifactInfo, r RemoteInterface) {
+	if isImageManifest(man) {
+		a.ManifestCache.CacheContent(ctx, remoteRepo, man, art, r)
+		return
+	}
+	for _, desc := range a.local.CheckDependencies(ctx, art.Repository, man) {
+		if err := a.putBlobToLocal(remoteRepo, art.Repository, desc, r); err != nil {
+			log.Errorf("Failed to push blob to local repo, error: %v", err)
+			return
+		}
+	}
+	if err := a.local.PushManifest(art.Repository, getReference(art), man); err != nil {
+		log.Errorf("failed to push manifest, tag: %v, error %v", art.Tag, err)
+	}
+}
+
+// isImageManifest checks whether the OCI or docker manifest is an image by the media type of the config
+func isImageManifest(man distribution.Manifest) bool {
+	var config distribution.Descriptor
+	switch m := man.(type) {
+	case *ocischema.DeserializedManifest:
+		config = m.Config
+	case *schema2.DeserializedManifest:
+		config = m.Config
+	default:
+		return true
+	}
+	switch config.MediaType {
+	case v1.MediaTypeImageConfig, schema2.MediaTypeImageConfig:
+		return true
+	}
+	return false
+}
+
+// manifestListContentType returns the media type of the cached manifest list or image index
+func manifestListContentType(content []byte) string {
+	v := manifest.Versioned{}
+	if err := json.Unmarshal(content, &v); err != nil {
+		return manifestlist.MediaTypeManifestList
+	}
+	// the media type is required by the manifest list but optional for the OCI image index
+	if len(v.MediaType) == 0 {
+		return v1.MediaTypeImageIndex
+	}
+	return v.MediaType
+}
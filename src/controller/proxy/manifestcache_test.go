@@ -20,12 +20,17 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest"
 	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/lib"
+	testproxy "github.com/goharbor/harbor/src/testing/controller/proxy"
 	"github.com/goharbor/harbor/src/testing/mock"
 	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -90,6 +95,9 @@ func (suite *CacheTestSuite) TestUpdateManifestList() {
 	newMan, err := suite.mHandler.updateManifestList(ctx, "library/hello-world", manList)
 	suite.Require().Nil(err)
 	suite.Assert().Equal(len(newMan.References()), 1)
+	ct, _, err := newMan.Payload()
+	suite.Require().Nil(err)
+	suite.Assert().Equal(manifestlist.MediaTypeManifestList, ct)
 }
 
 func (suite *CacheTestSuite) TestPushManifestList() {
@@ -152,3 +160,157 @@ func (suite *CacheTestSuite) TestPushManifestList() {
 func TestCacheTestSuite(t *testing.T) {
 	suite.Run(t, &CacheTestSuite{})
 }
+
+func newOCIManifest(t *testing.T, configMediaType string) distribution.Manifest {
+	man, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: manifest.Versioned{
+			SchemaVersion: 2,
+			MediaType:     v1.MediaTypeImageManifest,
+		},
+		Config: distribution.Descriptor{
+			MediaType: configMediaType,
+			Digest:    digest.FromString("config"),
+			Size:      6,
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: "application/vnd.cncf.helm.chart.content.v1.tar+gzip",
+				Digest:    digest.FromString("layer"),
+				Size:      5,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create the manifest: %v", err)
+	}
+	return man
+}
+
+func newSchema2Manifest(t *testing.T, configMediaType string) distribution.Manifest {
+	man, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: configMediaType,
+			Digest:    digest.FromString("config"),
+			Size:      6,
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: schema2.MediaTypeLayer,
+				Digest:    digest.FromString("layer"),
+				Size:      5,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create the manifest: %v", err)
+	}
+	return man
+}
+
+func TestIsImageManifest(t *testing.T) {
+	cases := []struct {
+		name string
+		in   distribution.Manifest
+		want bool
+	}{
+		{
+			name: `manifest list`,
+			in:   &manifestlist.DeserializedManifestList{},
+			want: true,
+		},
+		{
+			name: `OCI image`,
+			in:   newOCIManifest(t, v1.MediaTypeImageConfig),
+			want: true,
+		},
+		{
+			name: `OCI manifest with docker image config`,
+			in:   newOCIManifest(t, schema2.MediaTypeImageConfig),
+			want: true,
+		},
+		{
+			name: `docker image`,
+			in:   newSchema2Manifest(t, schema2.MediaTypeImageConfig),
+			want: true,
+		},
+		{
+			name: `docker manifest with non-image config`,
+			in:   newSchema2Manifest(t, "application/vnd.cncf.helm.config.v1+json"),
+			want: false,
+		},
+		{
+			name: `helm chart`,
+			in:   newOCIManifest(t, "application/vnd.cncf.helm.config.v1+json"),
+			want: false,
+		},
+		{
+			name: `wasm module`,
+			in:   newOCIManifest(t, "application/vnd.wasm.config.v1+json"),
+			want: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isImageManifest(tt.in); got != tt.want {
+				t.Errorf(`isImageManifest() = %v; want "%v"`, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestListContentType(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: `manifest list`,
+			in:   `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.list.v2+json","manifests":[]}`,
+			want: manifestlist.MediaTypeManifestList,
+		},
+		{
+			name: `image index`,
+			in:   `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[]}`,
+			want: v1.MediaTypeImageIndex,
+		},
+		{
+			name: `image index without media type`,
+			in:   `{"schemaVersion":2,"manifests":[]}`,
+			want: v1.MediaTypeImageIndex,
+		},
+		{
+			name: `invalid`,
+			in:   `invalid`,
+			want: manifestlist.MediaTypeManifestList,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifestListContentType([]byte(tt.in)); got != tt.want {
+				t.Errorf(`manifestListContentType() = %v; want "%v"`, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArtifactCacheContent(t *testing.T) {
+	ctx := context.Background()
+	local := &localInterfaceMock{}
+	remote := &testproxy.RemoteInterface{}
+	handler := &ArtifactCache{ManifestCache{local}}
+	man := newOCIManifest(t, "application/vnd.cncf.helm.config.v1+json")
+	art := lib.ArtifactInfo{Repository: "dockerhub/bitnami/nginx", Tag: "9.5.0"}
+	config := man.References()[0]
+
+	// the config isn't pulled by the client, push it without waiting
+	local.On("CheckDependencies", ctx, art.Repository, man).Return([]distribution.Descriptor{config})
+	remote.On("BlobReader", "bitnami/nginx", string(config.Digest)).Return(int64(6), ioutil.NopCloser(strings.NewReader("config")), nil)
+	local.On("PushBlob", art.Repository, config, mock.Anything).Return(nil)
+	local.On("PushManifest", art.Repository, "9.5.0", man).Return(nil)
+
+	handler.CacheContent(ctx, "bitnami/nginx", man, art, remote)
+	local.AssertExpectations(t)
+	remote.AssertExpectations(t)
+}
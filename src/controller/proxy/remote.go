@@ -18,13 +18,23 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/docker/distribution"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 )
 
+// the period that an upstream registry is skipped after it failed to serve the request
+const upstreamCoolDown = 60 * time.Second
+
+// upstreamHealth tracks the health of the upstream registries of all proxy cache projects
+var upstreamHealth = newHealthTracker(upstreamCoolDown)
+
 // RemoteInterface defines operations related to remote repository under proxy
 type RemoteInterface interface {
 	// BlobReader create a reader for remote blob
@@ -33,20 +43,39 @@ type RemoteInterface interface {
 	Manifest(repo string, ref string) (distribution.Manifest, string, error)
 	// ManifestExist checks manifest exist, if exist, return digest
 	ManifestExist(repo string, ref string) (bool, *distribution.Descriptor, error)
+	// FetchArtifacts lists the artifacts matching the filters in the remote server
+	FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error)
+	// ListTags lists the tags of the repository in the remote server
+	ListTags(repo string) ([]string, error)
+	// Upstream returns the ID of the upstream registry which served the last request
+	Upstream() int64
 }
 
-// remoteHelper defines operations related to remote repository under proxy
+// upstream is one of the registries that the proxy cache project pulls from
+type upstream struct {
+	id       int64
+	name     string
+	registry adapter.ArtifactRegistry
+}
+
+// remoteHelper defines operations related to remote repository under proxy,
+// the requests are sent to the upstream registries in order and fail over to
+// the next one when the current one returns error or doesn't have the content
 type remoteHelper struct {
-	regID       int64
-	registry    adapter.ArtifactRegistry
+	regIDs      []int64
+	upstreams   []*upstream
 	registryMgr reg.Manager
+	health      *healthTracker
+	serving     int64
 }
 
-// NewRemoteHelper create a remote interface
-func NewRemoteHelper(ctx context.Context, regID int64) (RemoteInterface, error) {
+// NewRemoteHelper create a remote interface, the regIDs are the ordered IDs of the upstream registries
+func NewRemoteHelper(ctx context.Context, regIDs ...int64) (RemoteInterface, error) {
 	r := &remoteHelper{
-		regID:       regID,
-		registryMgr: reg.Mgr}
+		regIDs:      regIDs,
+		registryMgr: reg.Mgr,
+		health:      upstreamHealth,
+	}
 	if err := r.init(ctx); err != nil {
 		return nil, err
 	}
@@ -54,40 +83,192 @@ func NewRemoteHelper(ctx context.Context, regID int64) (RemoteInterface, error)
 }
 
 func (r *remoteHelper) init(ctx context.Context) error {
-
-	if r.registry != nil {
+	if len(r.upstreams) > 0 {
 		return nil
 	}
-	reg, err := r.registryMgr.Get(ctx, r.regID)
+	var lastErr error
+	for _, regID := range r.regIDs {
+		u, err := r.createUpstream(ctx, regID)
+		if err != nil {
+			log.Warningf("skip the upstream registry %d: %v", regID, err)
+			lastErr = err
+			continue
+		}
+		r.upstreams = append(r.upstreams, u)
+	}
+	if len(r.upstreams) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("no upstream registry is configured")
+		}
+		return lastErr
+	}
+	return nil
+}
+
+func (r *remoteHelper) createUpstream(ctx context.Context, regID int64) (*upstream, error) {
+	reg, err := r.registryMgr.Get(ctx, regID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if reg == nil {
-		return fmt.Errorf("failed to get registry, registryID: %v", r.regID)
+		return nil, fmt.Errorf("failed to get registry, registryID: %v", regID)
 	}
 	if reg.Status != model.Healthy {
-		return fmt.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
+		return nil, fmt.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
 	}
 	factory, err := adapter.GetFactory(reg.Type)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	adp, err := factory.Create(reg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	r.registry = adp.(adapter.ArtifactRegistry)
-	return nil
+	registry, ok := adp.(adapter.ArtifactRegistry)
+	if !ok {
+		return nil, fmt.Errorf("the adapter of registry %v doesn't implement the \"ArtifactRegistry\" interface", reg.Name)
+	}
+	return &upstream{id: reg.ID, name: reg.Name, registry: registry}, nil
+}
+
+// candidates returns the upstreams in the order to try, the healthy ones come first
+// and the ones failed recently are kept at the end as the last resort
+func (r *remoteHelper) candidates() []*upstream {
+	var healthy, unhealthy []*upstream
+	for _, u := range r.upstreams {
+		if r.health.isHealthy(u.id) {
+			healthy = append(healthy, u)
+		} else {
+			unhealthy = append(unhealthy, u)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// failover calls f with the upstreams one by one until one of them has the content,
+// the upstream returns error other than not found is marked as unhealthy
+func (r *remoteHelper) failover(f func(registry adapter.ArtifactRegistry) (bool, error)) error {
+	var lastErr error
+	for _, u := range r.candidates() {
+		found, err := f(u.registry)
+		if err != nil {
+			if !errors.IsNotFoundErr(err) {
+				log.Warningf("failed to request the upstream registry %v(%d), fail over to the next one: %v", u.name, u.id, err)
+				r.health.markUnhealthy(u.id)
+			}
+			lastErr = err
+			continue
+		}
+		r.health.markHealthy(u.id)
+		if !found {
+			lastErr = nil
+			continue
+		}
+		r.serving = u.id
+		return nil
+	}
+	return lastErr
 }
 
 func (r *remoteHelper) BlobReader(repo, dig string) (int64, io.ReadCloser, error) {
-	return r.registry.PullBlob(repo, dig)
+	var (
+		size int64
+		blob io.ReadCloser
+	)
+	err := r.failover(func(registry adapter.ArtifactRegistry) (bool, error) {
+		var err error
+		size, blob, err = registry.PullBlob(repo, dig)
+		return err == nil, err
+	})
+	return size, blob, err
 }
 
 func (r *remoteHelper) Manifest(repo string, ref string) (distribution.Manifest, string, error) {
-	return r.registry.PullManifest(repo, ref)
+	var (
+		man distribution.Manifest
+		dig string
+	)
+	err := r.failover(func(registry adapter.ArtifactRegistry) (bool, error) {
+		var err error
+		man, dig, err = registry.PullManifest(repo, ref)
+		return err == nil, err
+	})
+	return man, dig, err
 }
 
 func (r *remoteHelper) ManifestExist(repo string, ref string) (bool, *distribution.Descriptor, error) {
-	return r.registry.ManifestExist(repo, ref)
+	var (
+		exist bool
+		desc  *distribution.Descriptor
+	)
+	err := r.failover(func(registry adapter.ArtifactRegistry) (bool, error) {
+		var err error
+		exist, desc, err = registry.ManifestExist(repo, ref)
+		return exist, err
+	})
+	return exist, desc, err
+}
+
+func (r *remoteHelper) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	var resources []*model.Resource
+	err := r.failover(func(registry adapter.ArtifactRegistry) (bool, error) {
+		var err error
+		resources, err = registry.FetchArtifacts(filters)
+		return err == nil, err
+	})
+	return resources, err
+}
+
+func (r *remoteHelper) ListTags(repo string) ([]string, error) {
+	var tags []string
+	err := r.failover(func(registry adapter.ArtifactRegistry) (bool, error) {
+		// listing the artifacts is too expensive to be done for every tag list request,
+		// so the upstream registries which don't support the tag list API are skipped
+		tr, ok := registry.(adapter.TagRegistry)
+		if !ok {
+			return false, errors.New(nil).WithCode(errors.NotFoundCode).
+				WithMessage("the upstream registry doesn't support listing the tags of %s", repo)
+		}
+		var err error
+		tags, err = tr.ListTags(repo)
+		return err == nil, err
+	})
+	return tags, err
+}
+
+func (r *remoteHelper) Upstream() int64 {
+	return r.serving
+}
+
+// healthTracker records the upstream registries failed to serve the requests recently
+type healthTracker struct {
+	sync.RWMutex
+	coolDown  time.Duration
+	unhealthy map[int64]time.Time
+}
+
+func newHealthTracker(coolDown time.Duration) *healthTracker {
+	return &healthTracker{
+		coolDown:  coolDown,
+		unhealthy: map[int64]time.Time{},
+	}
+}
+
+func (h *healthTracker) markUnhealthy(id int64) {
+	h.Lock()
+	defer h.Unlock()
+	h.unhealthy[id] = time.Now()
+}
+
+func (h *healthTracker) markHealthy(id int64) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.unhealthy, id)
+}
+
+func (h *healthTracker) isHealthy(id int64) bool {
+	h.RLock()
+	defer h.RUnlock()
+	failedAt, exist := h.unhealthy[id]
+	return !exist || time.Since(failedAt) > h.coolDown
 }
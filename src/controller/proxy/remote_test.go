@@ -0,0 +1,172 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeUpstream only implements the methods used by the remote helper
+type fakeUpstream struct {
+	adapter.ArtifactRegistry
+	err      error
+	notFound bool
+	calls    int
+}
+
+func (f *fakeUpstream) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	f.calls++
+	if f.err != nil {
+		return false, nil, f.err
+	}
+	if f.notFound {
+		return false, nil, nil
+	}
+	return true, &distribution.Descriptor{Digest: digest.Digest("sha256:1234")}, nil
+}
+
+func (f *fakeUpstream) PullBlob(repository, digest string) (int64, io.ReadCloser, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, nil, f.err
+	}
+	if f.notFound {
+		return 0, nil, errors.NotFoundError(nil)
+	}
+	return 4, ioutil.NopCloser(strings.NewReader("blob")), nil
+}
+
+// fakeTagUpstream supports listing the tags via the tag list API
+type fakeTagUpstream struct {
+	fakeUpstream
+}
+
+func (f *fakeTagUpstream) ListTags(repository string) ([]string, error) {
+	return []string{"v1"}, nil
+}
+
+type remoteHelperTestSuite struct {
+	suite.Suite
+	primary  *fakeUpstream
+	fallback *fakeUpstream
+	remote   *remoteHelper
+}
+
+func (r *remoteHelperTestSuite) SetupTest() {
+	r.primary = &fakeUpstream{}
+	r.fallback = &fakeUpstream{}
+	r.remote = &remoteHelper{
+		upstreams: []*upstream{
+			{id: 1, name: "mirror", registry: r.primary},
+			{id: 2, name: "docker hub", registry: r.fallback},
+		},
+		health: newHealthTracker(time.Minute),
+	}
+}
+
+func (r *remoteHelperTestSuite) TestServedByPrimary() {
+	exist, desc, err := r.remote.ManifestExist("library/hello-world", "latest")
+	r.Require().Nil(err)
+	r.True(exist)
+	r.Equal(digest.Digest("sha256:1234"), desc.Digest)
+	r.Equal(int64(1), r.remote.Upstream())
+	r.Equal(0, r.fallback.calls)
+}
+
+func (r *remoteHelperTestSuite) TestFailoverOnError() {
+	r.primary.err = fmt.Errorf("429 too many requests")
+	size, blob, err := r.remote.BlobReader("library/hello-world", "sha256:1234")
+	r.Require().Nil(err)
+	defer blob.Close()
+	r.Equal(int64(4), size)
+	r.Equal(int64(2), r.remote.Upstream())
+	r.False(r.remote.health.isHealthy(1))
+
+	// the unhealthy upstream is tried after the healthy ones
+	r.primary.err = nil
+	_, blob2, err := r.remote.BlobReader("library/hello-world", "sha256:1234")
+	r.Require().Nil(err)
+	defer blob2.Close()
+	r.Equal(int64(2), r.remote.Upstream())
+	r.Equal(1, r.primary.calls)
+}
+
+func (r *remoteHelperTestSuite) TestFailoverOnNotFound() {
+	r.primary.notFound = true
+	exist, _, err := r.remote.ManifestExist("library/hello-world", "latest")
+	r.Require().Nil(err)
+	r.True(exist)
+	r.Equal(int64(2), r.remote.Upstream())
+	// not found doesn't make the upstream unhealthy
+	r.True(r.remote.health.isHealthy(1))
+
+	// not found in all upstreams
+	r.fallback.notFound = true
+	_, _, err = r.remote.BlobReader("library/hello-world", "sha256:1234")
+	r.Require().NotNil(err)
+	r.True(errors.IsNotFoundErr(err))
+}
+
+func (r *remoteHelperTestSuite) TestAllFailed() {
+	r.primary.err = fmt.Errorf("error")
+	r.fallback.err = fmt.Errorf("error")
+	_, _, err := r.remote.ManifestExist("library/hello-world", "latest")
+	r.Require().NotNil(err)
+}
+
+func (r *remoteHelperTestSuite) TestListTags() {
+	// the upstream registries which don't support the tag list API are skipped
+	_, err := r.remote.ListTags("library/hello-world")
+	r.Require().NotNil(err)
+	r.True(errors.IsNotFoundErr(err))
+	r.Equal(0, r.primary.calls)
+	r.Equal(0, r.fallback.calls)
+	r.True(r.remote.health.isHealthy(1))
+	r.True(r.remote.health.isHealthy(2))
+
+	// served by the upstream registry supporting the tag list API
+	r.remote.upstreams[1].registry = &fakeTagUpstream{}
+	tags, err := r.remote.ListTags("library/hello-world")
+	r.Require().Nil(err)
+	r.Equal([]string{"v1"}, tags)
+	r.Equal(int64(2), r.remote.Upstream())
+}
+
+func TestRemoteHelperTestSuite(t *testing.T) {
+	suite.Run(t, &remoteHelperTestSuite{})
+}
+
+func TestHealthTracker(t *testing.T) {
+	h := newHealthTracker(time.Millisecond)
+	h.markUnhealthy(1)
+	if h.isHealthy(1) {
+		t.Error("the upstream should be unhealthy")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if !h.isHealthy(1) {
+		t.Error("the upstream should be healthy after the cool down period")
+	}
+}
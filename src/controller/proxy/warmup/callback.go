@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/warmup"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+const (
+	progressTotal  = "total"
+	progressCached = "cached"
+	progressFailed = "failed"
+)
+
+func init() {
+	if err := scheduler.RegisterCallbackFunc(SchedulerCallback, warmUpCallback); err != nil {
+		log.Fatalf("failed to register the proxy cache warm-up callback, %v", err)
+	}
+	if err := task.RegisterCheckInProcessor(VendorType, warmUpTaskCheckInProcessor); err != nil {
+		log.Fatalf("failed to register the checkin processor for the proxy cache warm-up job, %v", err)
+	}
+}
+
+func warmUpCallback(ctx context.Context, p string) error {
+	policy := &Policy{}
+	if err := json.Unmarshal([]byte(p), policy); err != nil {
+		return fmt.Errorf("failed to unmarshal the param: %v", err)
+	}
+	_, err := Ctl.Start(ctx, *policy, task.ExecutionTriggerSchedule)
+	return err
+}
+
+// warmUpTaskCheckInProcessor records the progress checked in by the warm-up job
+// into the extra attributes of the task
+func warmUpTaskCheckInProcessor(ctx context.Context, t *task.Task, sc *job.StatusChange) error {
+	if sc.CheckIn == "" {
+		return nil
+	}
+	progress := &warmup.Progress{}
+	if err := json.Unmarshal([]byte(sc.CheckIn), progress); err != nil {
+		log.G(ctx).Errorf("failed to resolve checkin of warm-up task %d: %v", t.ID, err)
+		return err
+	}
+
+	if t.ExtraAttrs == nil {
+		t.ExtraAttrs = map[string]interface{}{}
+	}
+	t.ExtraAttrs[progressTotal] = progress.Total
+	t.ExtraAttrs[progressCached] = progress.Cached
+	t.ExtraAttrs[progressFailed] = progress.Failed
+	if err := task.Mgr.UpdateExtraAttrs(ctx, t.ID, t.ExtraAttrs); err != nil {
+		log.G(ctx).Errorf("failed to update the extra attributes of warm-up task %d: %v", t.ID, err)
+		return err
+	}
+	return nil
+}
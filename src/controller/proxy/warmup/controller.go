@@ -0,0 +1,293 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/warmup"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/pkg/task"
+	cronlib "github.com/robfig/cron"
+)
+
+func init() {
+	// keep only the latest created 50 warm-up execution records
+	task.SetExecutionSweeperCount(VendorType, 50)
+}
+
+var (
+	// Ctl is a global proxy cache warm-up controller instance
+	Ctl = NewController()
+)
+
+const (
+	// SchedulerCallback ...
+	SchedulerCallback = "PROXY_WARM_UP"
+	// VendorType ...
+	VendorType = "PROXY_WARM_UP"
+)
+
+// Controller manages the warm-up jobs of the proxy cache projects
+type Controller interface {
+	// Start starts a warm-up execution for the proxy cache project specified in the policy,
+	// one task is created for each pattern
+	Start(ctx context.Context, policy Policy, trigger string) (int64, error)
+	// Stop stops the warm-up execution
+	Stop(ctx context.Context, id int64) error
+
+	// ExecutionCount returns the total count of executions of the project according to the query
+	ExecutionCount(ctx context.Context, projectID int64, query *q.Query) (count int64, err error)
+	// ListExecutions lists the executions of the project according to the query
+	ListExecutions(ctx context.Context, projectID int64, query *q.Query) (executions []*Execution, err error)
+	// GetExecution gets the specific execution
+	GetExecution(ctx context.Context, executionID int64) (execution *Execution, err error)
+
+	// GetTask gets the specific task
+	GetTask(ctx context.Context, id int64) (*Task, error)
+	// ListTasks lists the tasks according to the query
+	ListTasks(ctx context.Context, query *q.Query) (tasks []*Task, err error)
+	// GetTaskLog gets log of the specific task
+	GetTaskLog(ctx context.Context, id int64) ([]byte, error)
+
+	// GetSchedule gets the warm-up schedule of the project
+	GetSchedule(ctx context.Context, projectID int64) (*scheduler.Schedule, error)
+	// CreateSchedule creates the warm-up schedule with cron type & string for the project specified in the policy,
+	// the existing schedule of the project is replaced only when the policy and cron are valid
+	CreateSchedule(ctx context.Context, cronType, cron string, policy Policy) (int64, error)
+	// DeleteSchedule removes the warm-up schedule of the project
+	DeleteSchedule(ctx context.Context, projectID int64) error
+}
+
+// NewController creates an instance of the default warm-up controller
+func NewController() Controller {
+	return &controller{
+		projectCtl:   project.Ctl,
+		taskMgr:      task.NewManager(),
+		exeMgr:       task.NewExecutionManager(),
+		schedulerMgr: scheduler.New(),
+	}
+}
+
+type controller struct {
+	projectCtl   project.Controller
+	taskMgr      task.Manager
+	exeMgr       task.ExecutionManager
+	schedulerMgr scheduler.Scheduler
+}
+
+func (c *controller) validate(ctx context.Context, policy Policy) error {
+	if len(policy.Patterns) == 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("at least one pattern is required")
+	}
+	for _, pattern := range policy.Patterns {
+		if repository, _ := warmup.ParsePattern(pattern); len(repository) == 0 {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the repository part of the pattern %s is empty", pattern)
+		}
+	}
+	p, err := c.projectCtl.Get(ctx, policy.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !p.IsProxy() {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the project %s isn't a proxy cache project", p.Name)
+	}
+	return nil
+}
+
+func (c *controller) Start(ctx context.Context, policy Policy, trigger string) (int64, error) {
+	if err := c.validate(ctx, policy); err != nil {
+		return -1, err
+	}
+
+	execID, err := c.exeMgr.Create(ctx, VendorType, policy.ProjectID, trigger)
+	if err != nil {
+		return -1, err
+	}
+	for _, pattern := range policy.Patterns {
+		_, err = c.taskMgr.Create(ctx, execID, &task.Job{
+			Name: job.ProxyWarmUp,
+			Metadata: &job.Metadata{
+				JobKind: job.KindGeneric,
+			},
+			Parameters: map[string]interface{}{
+				warmup.ProjectID: policy.ProjectID,
+				warmup.Pattern:   pattern,
+			},
+		}, map[string]interface{}{
+			warmup.Pattern: pattern,
+		})
+		if err != nil {
+			// mark the execution as error, the tasks created are kept running
+			if e := c.exeMgr.MarkError(ctx, execID, err.Error()); e != nil {
+				return -1, e
+			}
+			return -1, err
+		}
+	}
+	return execID, nil
+}
+
+func (c *controller) Stop(ctx context.Context, id int64) error {
+	return c.exeMgr.Stop(ctx, id)
+}
+
+func (c *controller) ExecutionCount(ctx context.Context, projectID int64, query *q.Query) (int64, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+	query.Keywords["VendorID"] = projectID
+	return c.exeMgr.Count(ctx, query)
+}
+
+func (c *controller) ListExecutions(ctx context.Context, projectID int64, query *q.Query) ([]*Execution, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+	query.Keywords["VendorID"] = projectID
+
+	execs, err := c.exeMgr.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var executions []*Execution
+	for _, exec := range execs {
+		executions = append(executions, convertExecution(exec))
+	}
+	return executions, nil
+}
+
+func (c *controller) GetExecution(ctx context.Context, id int64) (*Execution, error) {
+	execs, err := c.exeMgr.List(ctx, &q.Query{
+		Keywords: map[string]interface{}{
+			"ID":         id,
+			"VendorType": VendorType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(execs) == 0 {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("warm-up execution %d not found", id)
+	}
+	return convertExecution(execs[0]), nil
+}
+
+func (c *controller) GetTask(ctx context.Context, id int64) (*Task, error) {
+	tasks, err := c.taskMgr.List(ctx, &q.Query{
+		Keywords: map[string]interface{}{
+			"ID":         id,
+			"VendorType": VendorType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("warm-up task %d not found", id)
+	}
+	return convertTask(tasks[0]), nil
+}
+
+func (c *controller) ListTasks(ctx context.Context, query *q.Query) ([]*Task, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+	tks, err := c.taskMgr.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []*Task
+	for _, tk := range tks {
+		tasks = append(tasks, convertTask(tk))
+	}
+	return tasks, nil
+}
+
+func (c *controller) GetTaskLog(ctx context.Context, id int64) ([]byte, error) {
+	_, err := c.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.taskMgr.GetLog(ctx, id)
+}
+
+func (c *controller) GetSchedule(ctx context.Context, projectID int64) (*scheduler.Schedule, error) {
+	sch, err := c.schedulerMgr.ListSchedules(ctx, q.New(q.KeyWords{"VendorType": VendorType, "VendorID": projectID}))
+	if err != nil {
+		return nil, err
+	}
+	if len(sch) == 0 || sch[0] == nil {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("no warm-up schedule is found for project %d", projectID)
+	}
+	return sch[0], nil
+}
+
+func (c *controller) CreateSchedule(ctx context.Context, cronType, cron string, policy Policy) (int64, error) {
+	if err := c.validate(ctx, policy); err != nil {
+		return 0, err
+	}
+	if _, err := cronlib.Parse(cron); err != nil {
+		return 0, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid cron %s: %v", cron, err)
+	}
+	if err := c.schedulerMgr.UnScheduleByVendor(ctx, VendorType, policy.ProjectID); err != nil {
+		return 0, err
+	}
+	return c.schedulerMgr.Schedule(ctx, VendorType, policy.ProjectID, cronType, cron, SchedulerCallback, policy, nil)
+}
+
+func (c *controller) DeleteSchedule(ctx context.Context, projectID int64) error {
+	return c.schedulerMgr.UnScheduleByVendor(ctx, VendorType, projectID)
+}
+
+func convertExecution(exec *task.Execution) *Execution {
+	return &Execution{
+		ID:            exec.ID,
+		ProjectID:     exec.VendorID,
+		Status:        exec.Status,
+		StatusMessage: exec.StatusMessage,
+		Trigger:       exec.Trigger,
+		ExtraAttrs:    exec.ExtraAttrs,
+		StartTime:     exec.StartTime,
+		EndTime:       exec.EndTime,
+	}
+}
+
+func convertTask(task *task.Task) *Task {
+	return &Task{
+		ID:            task.ID,
+		ExecutionID:   task.ExecutionID,
+		Status:        task.Status,
+		StatusMessage: task.StatusMessage,
+		RunCount:      task.RunCount,
+		Pattern:       task.GetStringFromExtraAttrs(warmup.Pattern),
+		Total:         int(task.GetNumFromExtraAttrs(progressTotal)),
+		Cached:        int(task.GetNumFromExtraAttrs(progressCached)),
+		Failed:        int(task.GetNumFromExtraAttrs(progressFailed)),
+		JobID:         task.JobID,
+		CreationTime:  task.CreationTime,
+		StartTime:     task.StartTime,
+		UpdateTime:    task.UpdateTime,
+		EndTime:       task.EndTime,
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/pkg/task"
+	projecttesting "github.com/goharbor/harbor/src/testing/controller/project"
+	"github.com/goharbor/harbor/src/testing/mock"
+	schedulertesting "github.com/goharbor/harbor/src/testing/pkg/scheduler"
+	tasktesting "github.com/goharbor/harbor/src/testing/pkg/task"
+	"github.com/stretchr/testify/suite"
+)
+
+type warmUpCtrTestSuite struct {
+	suite.Suite
+	projectCtl *projecttesting.Controller
+	scheduler  *schedulertesting.Scheduler
+	execMgr    *tasktesting.ExecutionManager
+	taskMgr    *tasktesting.Manager
+	ctl        *controller
+}
+
+func (w *warmUpCtrTestSuite) SetupTest() {
+	w.projectCtl = &projecttesting.Controller{}
+	w.execMgr = &tasktesting.ExecutionManager{}
+	w.taskMgr = &tasktesting.Manager{}
+	w.scheduler = &schedulertesting.Scheduler{}
+	w.ctl = &controller{
+		projectCtl:   w.projectCtl,
+		taskMgr:      w.taskMgr,
+		exeMgr:       w.execMgr,
+		schedulerMgr: w.scheduler,
+	}
+}
+
+func (w *warmUpCtrTestSuite) TestStart() {
+	// no pattern
+	_, err := w.ctl.Start(nil, Policy{ProjectID: 1}, task.ExecutionTriggerManual)
+	w.NotNil(err)
+
+	// invalid pattern
+	_, err = w.ctl.Start(nil, Policy{ProjectID: 1, Patterns: []string{":latest"}}, task.ExecutionTriggerManual)
+	w.NotNil(err)
+
+	// not a proxy cache project
+	w.projectCtl.On("Get", mock.Anything, int64(1)).Return(&models.Project{ProjectID: 1, Name: "library"}, nil)
+	_, err = w.ctl.Start(nil, Policy{ProjectID: 1, Patterns: []string{"library/nginx"}}, task.ExecutionTriggerManual)
+	w.NotNil(err)
+
+	w.projectCtl.On("Get", mock.Anything, int64(2)).Return(&models.Project{ProjectID: 2, Name: "dockerhub", RegistryID: 1}, nil)
+	w.execMgr.On("Create", mock.Anything, VendorType, int64(2), task.ExecutionTriggerManual).Return(int64(1), nil)
+	w.taskMgr.On("Create", mock.Anything, int64(1), mock.Anything, map[string]interface{}{"pattern": "library/nginx"}).Return(int64(1), nil)
+	w.taskMgr.On("Create", mock.Anything, int64(1), mock.Anything, map[string]interface{}{"pattern": "library/redis:6*"}).Return(int64(2), nil)
+
+	id, err := w.ctl.Start(nil, Policy{
+		ProjectID: 2,
+		Patterns:  []string{"library/nginx", "library/redis:6*"},
+	}, task.ExecutionTriggerManual)
+	w.Nil(err)
+	w.Equal(int64(1), id)
+	w.execMgr.AssertExpectations(w.T())
+	w.taskMgr.AssertExpectations(w.T())
+}
+
+func (w *warmUpCtrTestSuite) TestStop() {
+	w.execMgr.On("Stop", mock.Anything, mock.Anything).Return(nil)
+	w.Nil(w.ctl.Stop(nil, 1))
+}
+
+func (w *warmUpCtrTestSuite) TestGetTaskLog() {
+	w.taskMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Task{
+		{
+			ID:          1,
+			ExecutionID: 1,
+			Status:      job.SuccessStatus.String(),
+		},
+	}, nil)
+	w.taskMgr.On("GetLog", mock.Anything, mock.Anything).Return([]byte("hello world"), nil)
+
+	log, err := w.ctl.GetTaskLog(nil, 1)
+	w.Nil(err)
+	w.Equal([]byte("hello world"), log)
+}
+
+func (w *warmUpCtrTestSuite) TestExecutionCount() {
+	w.execMgr.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
+	count, err := w.ctl.ExecutionCount(nil, 1, q.New(q.KeyWords{}))
+	w.Nil(err)
+	w.Equal(int64(1), count)
+}
+
+func (w *warmUpCtrTestSuite) TestGetExecution() {
+	// not found
+	w.execMgr.On("List", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	_, err := w.ctl.GetExecution(nil, int64(1))
+	w.NotNil(err)
+
+	w.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{
+			ID:         1,
+			Trigger:    "Manual",
+			VendorType: VendorType,
+			VendorID:   2,
+		},
+	}, nil)
+	exec, err := w.ctl.GetExecution(nil, int64(1))
+	w.Nil(err)
+	w.Equal("Manual", exec.Trigger)
+	w.Equal(int64(2), exec.ProjectID)
+}
+
+func (w *warmUpCtrTestSuite) TestListExecutions() {
+	w.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{
+			ID:      1,
+			Trigger: "Schedule",
+		},
+	}, nil)
+
+	execs, err := w.ctl.ListExecutions(nil, 1, q.New(q.KeyWords{}))
+	w.Nil(err)
+	w.Require().Len(execs, 1)
+	w.Equal("Schedule", execs[0].Trigger)
+}
+
+func (w *warmUpCtrTestSuite) TestListTasks() {
+	w.taskMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Task{
+		{
+			ID:          1,
+			ExecutionID: 1,
+			Status:      job.RunningStatus.String(),
+			ExtraAttrs: map[string]interface{}{
+				"pattern": "library/nginx",
+				"total":   float64(10),
+				"cached":  float64(8),
+				"failed":  float64(1),
+			},
+		},
+	}, nil)
+	tasks, err := w.ctl.ListTasks(nil, q.New(q.KeyWords{}))
+	w.Require().Nil(err)
+	w.Require().Len(tasks, 1)
+	w.Equal(int64(1), tasks[0].ID)
+	w.Equal("library/nginx", tasks[0].Pattern)
+	w.Equal(10, tasks[0].Total)
+	w.Equal(8, tasks[0].Cached)
+	w.Equal(1, tasks[0].Failed)
+	w.taskMgr.AssertExpectations(w.T())
+}
+
+func (w *warmUpCtrTestSuite) TestGetSchedule() {
+	// not found
+	w.scheduler.On("ListSchedules", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	_, err := w.ctl.GetSchedule(nil, 1)
+	w.NotNil(err)
+
+	w.scheduler.On("ListSchedules", mock.Anything, mock.Anything).Return([]*scheduler.Schedule{
+		{
+			ID:         1,
+			VendorType: VendorType,
+			VendorID:   1,
+		},
+	}, nil)
+	sche, err := w.ctl.GetSchedule(nil, 1)
+	w.Nil(err)
+	w.Equal(VendorType, sche.VendorType)
+}
+
+func (w *warmUpCtrTestSuite) TestCreateSchedule() {
+	w.projectCtl.On("Get", mock.Anything, int64(2)).Return(&models.Project{ProjectID: 2, Name: "dockerhub", RegistryID: 1}, nil)
+	w.scheduler.On("UnScheduleByVendor", mock.Anything, VendorType, int64(2)).Return(nil)
+	w.scheduler.On("Schedule", mock.Anything, VendorType, int64(2), "Daily", "0 0 0 * * *",
+		SchedulerCallback, mock.Anything, mock.Anything).Return(int64(1), nil)
+
+	id, err := w.ctl.CreateSchedule(nil, "Daily", "0 0 0 * * *", Policy{ProjectID: 2, Patterns: []string{"library/nginx"}})
+	w.Nil(err)
+	w.Equal(int64(1), id)
+	w.scheduler.AssertCalled(w.T(), "UnScheduleByVendor", mock.Anything, VendorType, int64(2))
+}
+
+func (w *warmUpCtrTestSuite) TestCreateScheduleInvalid() {
+	w.projectCtl.On("Get", mock.Anything, int64(2)).Return(&models.Project{ProjectID: 2, Name: "dockerhub", RegistryID: 1}, nil)
+
+	// the existing schedule is kept when the request is invalid
+	_, err := w.ctl.CreateSchedule(nil, "Custom", "invalid", Policy{ProjectID: 2, Patterns: []string{"library/nginx"}})
+	w.True(errors.IsErr(err, errors.BadRequestCode))
+	_, err = w.ctl.CreateSchedule(nil, "Daily", "0 0 0 * * *", Policy{ProjectID: 2})
+	w.True(errors.IsErr(err, errors.BadRequestCode))
+	w.scheduler.AssertNotCalled(w.T(), "UnScheduleByVendor", mock.Anything, VendorType, int64(2))
+}
+
+func (w *warmUpCtrTestSuite) TestDeleteSchedule() {
+	w.scheduler.On("UnScheduleByVendor", mock.Anything, VendorType, int64(2)).Return(nil)
+	w.Nil(w.ctl.DeleteSchedule(nil, 2))
+}
+
+func TestControllerTestSuite(t *testing.T) {
+	suite.Run(t, &warmUpCtrTestSuite{})
+}
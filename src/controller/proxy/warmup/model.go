@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"time"
+)
+
+// Policy defines what to warm up for the proxy cache project
+type Policy struct {
+	ProjectID int64 `json:"project_id"`
+	// Patterns in "repository:tag" format, e.g. "library/nginx:1.*",
+	// all tags are matched if the tag part is omitted
+	Patterns []string `json:"patterns"`
+	Trigger  *Trigger `json:"trigger"`
+}
+
+type TriggerType string
+
+type Trigger struct {
+	Type     TriggerType      `json:"type"`
+	Settings *TriggerSettings `json:"trigger_settings"`
+}
+
+type TriggerSettings struct {
+	Cron string `json:"cron"`
+}
+
+type Execution struct {
+	ID            int64
+	ProjectID     int64
+	Status        string
+	StatusMessage string
+	Trigger       string
+	ExtraAttrs    map[string]interface{}
+	StartTime     time.Time
+	EndTime       time.Time
+}
+
+type Task struct {
+	ID            int64
+	ExecutionID   int64
+	Status        string
+	StatusMessage string
+	RunCount      int32
+	Pattern       string
+	Total         int
+	Cached        int
+	Failed        int
+	JobID         string
+	CreationTime  time.Time
+	StartTime     time.Time
+	UpdateTime    time.Time
+	EndTime       time.Time
+}
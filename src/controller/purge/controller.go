@@ -0,0 +1,250 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package purge
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/purge"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+func init() {
+	// keep only the latest created 50 purge audit log execution records
+	task.SetExecutionSweeperCount(VendorType, 50)
+}
+
+var (
+	// Ctl is a global purge audit log controller instance
+	Ctl = NewController()
+)
+
+const (
+	// SchedulerCallback ...
+	SchedulerCallback = "PURGE_AUDIT"
+	// VendorType ...
+	VendorType = "PURGE_AUDIT"
+)
+
+// Controller manages the purge audit log jobs
+type Controller interface {
+	// Start start a manual purge audit log job
+	Start(ctx context.Context, policy JobPolicy, trigger string) (int64, error)
+	// Stop stop a purge audit log job
+	Stop(ctx context.Context, id int64) error
+
+	// ExecutionCount returns the total count of executions according to the query
+	ExecutionCount(ctx context.Context, query *q.Query) (count int64, err error)
+	// ListExecutions lists the executions according to the query
+	ListExecutions(ctx context.Context, query *q.Query) (executions []*Execution, err error)
+	// GetExecution gets the specific execution
+	GetExecution(ctx context.Context, executionID int64) (execution *Execution, err error)
+
+	// GetTask gets the specific task
+	GetTask(ctx context.Context, id int64) (*Task, error)
+	// ListTasks lists the tasks according to the query
+	ListTasks(ctx context.Context, query *q.Query) (tasks []*Task, err error)
+	// GetTaskLog gets log of the specific task
+	GetTaskLog(ctx context.Context, id int64) ([]byte, error)
+
+	// GetSchedule get the current purge audit log schedule
+	GetSchedule(ctx context.Context) (*scheduler.Schedule, error)
+	// CreateSchedule create the purge audit log schedule with cron type & string
+	CreateSchedule(ctx context.Context, cronType, cron string, policy JobPolicy) (int64, error)
+	// DeleteSchedule remove the purge audit log schedule
+	DeleteSchedule(ctx context.Context) error
+}
+
+// NewController creates an instance of the default purge audit log controller
+func NewController() Controller {
+	return &controller{
+		taskMgr:      task.NewManager(),
+		exeMgr:       task.NewExecutionManager(),
+		schedulerMgr: scheduler.New(),
+	}
+}
+
+type controller struct {
+	taskMgr      task.Manager
+	exeMgr       task.ExecutionManager
+	schedulerMgr scheduler.Scheduler
+}
+
+func (c *controller) Start(ctx context.Context, policy JobPolicy, trigger string) (int64, error) {
+	if err := policy.Validate(); err != nil {
+		return -1, err
+	}
+	para := make(map[string]interface{})
+	para[purge.RetentionHour] = policy.RetentionHour
+	para[purge.IncludeOperations] = policy.IncludeOperations
+	para[purge.DryRun] = policy.DryRun
+
+	execID, err := c.exeMgr.Create(ctx, VendorType, -1, trigger, para)
+	if err != nil {
+		return -1, err
+	}
+	_, err = c.taskMgr.Create(ctx, execID, &task.Job{
+		Name: job.PurgeAudit,
+		Metadata: &job.Metadata{
+			JobKind: job.KindGeneric,
+		},
+		Parameters: para,
+	})
+	if err != nil {
+		return -1, err
+	}
+	return execID, nil
+}
+
+func (c *controller) Stop(ctx context.Context, id int64) error {
+	return c.exeMgr.Stop(ctx, id)
+}
+
+func (c *controller) ExecutionCount(ctx context.Context, query *q.Query) (int64, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+	return c.exeMgr.Count(ctx, query)
+}
+
+func (c *controller) ListExecutions(ctx context.Context, query *q.Query) ([]*Execution, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+
+	execs, err := c.exeMgr.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var executions []*Execution
+	for _, exec := range execs {
+		executions = append(executions, convertExecution(exec))
+	}
+	return executions, nil
+}
+
+func (c *controller) GetExecution(ctx context.Context, id int64) (*Execution, error) {
+	execs, err := c.exeMgr.List(ctx, &q.Query{
+		Keywords: map[string]interface{}{
+			"ID":         id,
+			"VendorType": VendorType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(execs) == 0 {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("purge audit log execution %d not found", id)
+	}
+	return convertExecution(execs[0]), nil
+}
+
+func (c *controller) GetTask(ctx context.Context, id int64) (*Task, error) {
+	tasks, err := c.taskMgr.List(ctx, &q.Query{
+		Keywords: map[string]interface{}{
+			"ID":         id,
+			"VendorType": VendorType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("purge audit log task %d not found", id)
+	}
+	return convertTask(tasks[0]), nil
+}
+
+func (c *controller) ListTasks(ctx context.Context, query *q.Query) ([]*Task, error) {
+	query = q.MustClone(query)
+	query.Keywords["VendorType"] = VendorType
+	tks, err := c.taskMgr.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []*Task
+	for _, tk := range tks {
+		tasks = append(tasks, convertTask(tk))
+	}
+	return tasks, nil
+}
+
+func (c *controller) GetTaskLog(ctx context.Context, id int64) ([]byte, error) {
+	_, err := c.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.taskMgr.GetLog(ctx, id)
+}
+
+func (c *controller) GetSchedule(ctx context.Context) (*scheduler.Schedule, error) {
+	sch, err := c.schedulerMgr.ListSchedules(ctx, q.New(q.KeyWords{"VendorType": VendorType}))
+	if err != nil {
+		return nil, err
+	}
+	if len(sch) == 0 || sch[0] == nil {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).WithMessage("no purge audit log schedule is found")
+	}
+	return sch[0], nil
+}
+
+func (c *controller) CreateSchedule(ctx context.Context, cronType, cron string, policy JobPolicy) (int64, error) {
+	if err := policy.Validate(); err != nil {
+		return 0, err
+	}
+	extras := make(map[string]interface{})
+	extras[purge.RetentionHour] = policy.RetentionHour
+	extras[purge.IncludeOperations] = policy.IncludeOperations
+	extras[purge.DryRun] = policy.DryRun
+	return c.schedulerMgr.Schedule(ctx, VendorType, -1, cronType, cron, SchedulerCallback, policy, extras)
+}
+
+func (c *controller) DeleteSchedule(ctx context.Context) error {
+	return c.schedulerMgr.UnScheduleByVendor(ctx, VendorType, -1)
+}
+
+func convertExecution(exec *task.Execution) *Execution {
+	return &Execution{
+		ID:            exec.ID,
+		Status:        exec.Status,
+		StatusMessage: exec.StatusMessage,
+		Trigger:       exec.Trigger,
+		ExtraAttrs:    exec.ExtraAttrs,
+		StartTime:     exec.StartTime,
+		EndTime:       exec.EndTime,
+	}
+}
+
+func convertTask(task *task.Task) *Task {
+	return &Task{
+		ID:                task.ID,
+		ExecutionID:       task.ExecutionID,
+		Status:            task.Status,
+		StatusMessage:     task.StatusMessage,
+		RunCount:          task.RunCount,
+		RetentionHour:     int(task.GetNumFromExtraAttrs(purge.RetentionHour)),
+		IncludeOperations: task.GetStringFromExtraAttrs(purge.IncludeOperations),
+		DryRun:            task.GetBoolFromExtraAttrs(purge.DryRun),
+		JobID:             task.JobID,
+		CreationTime:      task.CreationTime,
+		StartTime:         task.StartTime,
+		UpdateTime:        task.UpdateTime,
+		EndTime:           task.EndTime,
+	}
+}
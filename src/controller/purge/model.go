@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package purge
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/job/impl/purge"
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// JobPolicy defines the purge audit log job policy
+type JobPolicy struct {
+	Trigger           *Trigger `json:"trigger"`
+	DryRun            bool     `json:"dryrun"`
+	RetentionHour     int      `json:"retention_hour"`
+	IncludeOperations string   `json:"include_operations"`
+}
+
+// Validate checks the retention hour of the policy is in range (0, purge.MaxRetentionHour]
+func (p JobPolicy) Validate() error {
+	if p.RetentionHour <= 0 || p.RetentionHour > purge.MaxRetentionHour {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the retention hour should be in range (0, %d], got %d", purge.MaxRetentionHour, p.RetentionHour)
+	}
+	return nil
+}
+
+// TriggerType represents the type of trigger.
+type TriggerType string
+
+// Trigger holds info for a trigger
+type Trigger struct {
+	Type     TriggerType      `json:"type"`
+	Settings *TriggerSettings `json:"trigger_settings"`
+}
+
+// TriggerSettings is the setting about the trigger
+type TriggerSettings struct {
+	Cron string `json:"cron"`
+}
+
+// Execution model for purge audit log
+type Execution struct {
+	ID            int64
+	Status        string
+	StatusMessage string
+	Trigger       string
+	ExtraAttrs    map[string]interface{}
+	StartTime     time.Time
+	EndTime       time.Time
+}
+
+// Task model for purge audit log
+type Task struct {
+	ID                int64
+	ExecutionID       int64
+	Status            string
+	StatusMessage     string
+	RunCount          int32
+	RetentionHour     int
+	IncludeOperations string
+	DryRun            bool
+	JobID             string
+	CreationTime      time.Time
+	StartTime         time.Time
+	UpdateTime        time.Time
+	EndTime           time.Time
+}
@@ -17,6 +17,8 @@ package registry
 import (
 	"context"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/goharbor/harbor/src/lib"
@@ -24,6 +26,8 @@ import (
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/project"
+	"github.com/goharbor/harbor/src/pkg/project/metadata"
+	"github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/reg"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/replication"
@@ -62,16 +66,18 @@ type Controller interface {
 // NewController creates an instance of the registry controller
 func NewController() Controller {
 	return &controller{
-		regMgr: reg.Mgr,
-		repMgr: replication.Mgr,
-		proMgr: project.Mgr,
+		regMgr:  reg.Mgr,
+		repMgr:  replication.Mgr,
+		proMgr:  project.Mgr,
+		metaMgr: metadata.Mgr,
 	}
 }
 
 type controller struct {
-	regMgr reg.Manager
-	repMgr replication.Manager
-	proMgr project.Manager
+	regMgr  reg.Manager
+	repMgr  replication.Manager
+	proMgr  project.Manager
+	metaMgr metadata.Manager
 }
 
 func (c *controller) Create(ctx context.Context, registry *model.Registry) (int64, error) {
@@ -88,7 +94,11 @@ func (c *controller) validate(ctx context.Context, registry *model.Registry) err
 	if len(registry.Name) > 64 {
 		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the max length of name is 64")
 	}
-	url, err := lib.ValidateHTTPURL(registry.URL)
+	validateURL := lib.ValidateHTTPURL
+	if registry.Type == model.RegistryTypeOCILayout {
+		validateURL = lib.ValidateFileURL
+	}
+	url, err := validateURL(registry.URL)
 	if err != nil {
 		return err
 	}
@@ -161,6 +171,19 @@ func (c *controller) Delete(ctx context.Context, id int64) error {
 	if count > 0 {
 		return errors.New(nil).WithCode(errors.PreconditionCode).WithMessage("the registry %d is referenced by proxy cache project, cannot delete it", id)
 	}
+	// referenced by proxy cache project as fallback upstream registry
+	metas, err := c.metaMgr.List(ctx, models.ProMetaProxyUpstreamRegistries, "")
+	if err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		for _, str := range strings.Split(meta.Value, ",") {
+			upstream, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+			if err == nil && upstream == id {
+				return errors.New(nil).WithCode(errors.PreconditionCode).WithMessage("the registry %d is referenced by proxy cache project, cannot delete it", id)
+			}
+		}
+	}
 
 	return c.regMgr.Delete(ctx, id)
 }
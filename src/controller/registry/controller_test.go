@@ -19,7 +19,9 @@ import (
 	"github.com/goharbor/harbor/src/testing/mock"
 	"testing"
 
+	"github.com/goharbor/harbor/src/pkg/project/metadata/models"
 	testingproject "github.com/goharbor/harbor/src/testing/pkg/project"
+	testingmeta "github.com/goharbor/harbor/src/testing/pkg/project/metadata"
 	testingreg "github.com/goharbor/harbor/src/testing/pkg/reg"
 	testingadapter "github.com/goharbor/harbor/src/testing/pkg/reg/adapter"
 	testingrep "github.com/goharbor/harbor/src/testing/pkg/replication"
@@ -32,6 +34,7 @@ type registryTestSuite struct {
 	repMgr  *testingrep.Manager
 	regMgr  *testingreg.Manager
 	proMgr  *testingproject.Manager
+	metaMgr *testingmeta.Manager
 	adapter *testingadapter.Adapter
 }
 
@@ -39,11 +42,13 @@ func (r *registryTestSuite) SetupTest() {
 	r.repMgr = &testingrep.Manager{}
 	r.regMgr = &testingreg.Manager{}
 	r.proMgr = &testingproject.Manager{}
+	r.metaMgr = &testingmeta.Manager{}
 	r.adapter = &testingadapter.Adapter{}
 	r.ctl = &controller{
-		repMgr: r.repMgr,
-		regMgr: r.regMgr,
-		proMgr: r.proMgr,
+		repMgr:  r.repMgr,
+		regMgr:  r.regMgr,
+		proMgr:  r.proMgr,
+		metaMgr: r.metaMgr,
 	}
 }
 
@@ -163,14 +168,30 @@ func (r *registryTestSuite) TestDelete() {
 
 	r.SetupTest()
 
+	// referenced by proxy cache project as fallback upstream registry
+	mock.OnAnything(r.repMgr, "Count").Return(int64(0), nil)
+	mock.OnAnything(r.proMgr, "Count").Return(int64(0), nil)
+	r.metaMgr.On("List", mock.Anything, "proxy_upstream_registries", "").Return([]*models.ProjectMetadata{
+		{ProjectID: 1, Name: "proxy_upstream_registries", Value: "2, 11"},
+	}, nil)
+	err = r.ctl.Delete(nil, 11)
+	r.NotNil(err)
+	r.metaMgr.AssertExpectations(r.T())
+
+	r.SetupTest()
+
 	// pass
 	mock.OnAnything(r.repMgr, "Count").Return(int64(0), nil)
 	mock.OnAnything(r.proMgr, "Count").Return(int64(0), nil)
+	mock.OnAnything(r.metaMgr, "List").Return([]*models.ProjectMetadata{
+		{ProjectID: 1, Name: "proxy_upstream_registries", Value: "2,11"},
+	}, nil)
 	mock.OnAnything(r.regMgr, "Delete").Return(nil)
 	err = r.ctl.Delete(nil, 1)
 	r.Nil(err)
 	r.repMgr.AssertExpectations(r.T())
 	r.proMgr.AssertExpectations(r.T())
+	r.metaMgr.AssertExpectations(r.T())
 }
 
 func TestRegistryTestSuite(t *testing.T) {
@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+// verifyTaskCheckInProcessor records the result checked in by the replication task
+// running in verify mode into the extra attributes of the task
+func verifyTaskCheckInProcessor(ctx context.Context, t *task.Task, sc *job.StatusChange) error {
+	if sc.CheckIn == "" {
+		return nil
+	}
+	result := struct {
+		Missing    []string `json:"missing"`
+		Mismatched []string `json:"mismatched"`
+	}{}
+	if err := json.Unmarshal([]byte(sc.CheckIn), &result); err != nil {
+		log.G(ctx).Errorf("failed to resolve checkin of replication task %d: %v", t.ID, err)
+		return err
+	}
+
+	if t.ExtraAttrs == nil {
+		t.ExtraAttrs = map[string]interface{}{}
+	}
+	t.ExtraAttrs["missing"] = result.Missing
+	t.ExtraAttrs["mismatched"] = result.Mismatched
+	if err := task.Mgr.UpdateExtraAttrs(ctx, t.ID, t.ExtraAttrs); err != nil {
+		log.G(ctx).Errorf("failed to update the extra attributes of replication task %d: %v", t.ID, err)
+		return err
+	}
+	return nil
+}
+
+// getStringsFromExtraAttrs returns the string slice value specified by key
+func getStringsFromExtraAttrs(t *task.Task, key string) []string {
+	values, ok := t.ExtraAttrs[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
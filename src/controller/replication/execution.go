@@ -28,6 +28,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/lib/retry"
+	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/reg"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/replication"
@@ -38,8 +39,20 @@ import (
 func init() {
 	// keep only the latest created 50 replication execution records
 	task.SetExecutionSweeperCount(job.Replication, 50)
+
+	if err := task.RegisterCheckInProcessor(job.Replication, verifyTaskCheckInProcessor); err != nil {
+		log.Fatalf("failed to register the checkin processor for the replication job, error %v", err)
+	}
 }
 
+const (
+	// ExecutionModeCopy copies the resources from the source registry to the destination registry
+	ExecutionModeCopy = "copy"
+	// ExecutionModeVerify only compares the resources in the source registry with the ones in
+	// the destination registry without copying anything
+	ExecutionModeVerify = "verify"
+)
+
 // Ctl is a global replication controller instance
 var Ctl = NewController()
 
@@ -59,6 +72,9 @@ type Controller interface {
 	DeletePolicy(ctx context.Context, id int64) (err error)
 	// Start the replication according to the policy
 	Start(ctx context.Context, policy *replicationmodel.Policy, resource *model.Resource, trigger string) (executionID int64, err error)
+	// Verify compares the resources matched by the policy in the source registry with the ones
+	// in the destination registry, the missing and mismatched artifacts are recorded in the tasks
+	Verify(ctx context.Context, policy *replicationmodel.Policy) (executionID int64, err error)
 	// Stop the replication specified by the execution ID
 	Stop(ctx context.Context, executionID int64) (err error)
 	// ExecutionCount returns the total count of executions according to the query
@@ -84,6 +100,7 @@ func NewController() Controller {
 		execMgr:    task.ExecMgr,
 		taskMgr:    task.Mgr,
 		regMgr:     reg.Mgr,
+		proMgr:     project.Mgr,
 		scheduler:  scheduler.Sched,
 		flowCtl:    flow.NewController(),
 		ormCreator: orm.Crt,
@@ -96,6 +113,7 @@ type controller struct {
 	execMgr    task.ExecutionManager
 	taskMgr    task.Manager
 	regMgr     reg.Manager
+	proMgr     project.Manager
 	scheduler  scheduler.Scheduler
 	flowCtl    flow.Controller
 	ormCreator orm.Creator
@@ -103,13 +121,29 @@ type controller struct {
 }
 
 func (c *controller) Start(ctx context.Context, policy *replicationmodel.Policy, resource *model.Resource, trigger string) (int64, error) {
+	return c.run(ctx, policy, trigger, ExecutionModeCopy, func(ctx context.Context, id int64) error {
+		return c.flowCtl.Start(ctx, id, policy, resource)
+	})
+}
+
+func (c *controller) Verify(ctx context.Context, policy *replicationmodel.Policy) (int64, error) {
+	return c.run(ctx, policy, task.ExecutionTriggerManual, ExecutionModeVerify, func(ctx context.Context, id int64) error {
+		return c.flowCtl.Verify(ctx, id, policy)
+	})
+}
+
+// run creates the execution record and runs the flow specified by "f" in background
+func (c *controller) run(ctx context.Context, policy *replicationmodel.Policy, trigger, mode string,
+	f func(ctx context.Context, executionID int64) error) (int64, error) {
 	logger := log.GetLogger(ctx)
 	if !policy.Enabled {
 		return 0, errors.New(nil).WithCode(errors.PreconditionCode).
 			WithMessage("the policy %d is disabled", policy.ID)
 	}
 	// create an execution record
-	id, err := c.execMgr.Create(ctx, job.Replication, policy.ID, trigger)
+	id, err := c.execMgr.Create(ctx, job.Replication, policy.ID, trigger, map[string]interface{}{
+		"mode": mode,
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -141,7 +175,7 @@ func (c *controller) Start(ctx context.Context, policy *replicationmodel.Policy,
 			return
 		}
 
-		err := c.flowCtl.Start(ctx, id, policy, resource)
+		err := f(ctx, id)
 		if err == nil {
 			// no err, return directly
 			return
@@ -262,6 +296,10 @@ func (c *controller) GetTaskLog(ctx context.Context, id int64) ([]byte, error) {
 }
 
 func convertExecution(exec *task.Execution) *Execution {
+	mode := ExecutionModeCopy
+	if m, ok := exec.ExtraAttrs["mode"].(string); ok && len(m) > 0 {
+		mode = m
+	}
 	return &Execution{
 		ID:            exec.ID,
 		PolicyID:      exec.VendorID,
@@ -269,6 +307,7 @@ func convertExecution(exec *task.Execution) *Execution {
 		StatusMessage: exec.StatusMessage,
 		Metrics:       exec.Metrics,
 		Trigger:       exec.Trigger,
+		Mode:          mode,
 		StartTime:     exec.StartTime,
 		EndTime:       exec.EndTime,
 	}
@@ -285,6 +324,8 @@ func convertTask(task *task.Task) *Task {
 		SourceResource:      task.GetStringFromExtraAttrs("source_resource"),
 		DestinationResource: task.GetStringFromExtraAttrs("destination_resource"),
 		Operation:           task.GetStringFromExtraAttrs("operation"),
+		MissingArtifacts:    getStringsFromExtraAttrs(task, "missing"),
+		MismatchedArtifacts: getStringsFromExtraAttrs(task, "mismatched"),
 		JobID:               task.JobID,
 		CreationTime:        task.CreationTime,
 		StartTime:           task.StartTime,
@@ -27,6 +27,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/task/dao"
 	"github.com/goharbor/harbor/src/testing/lib/orm"
 	"github.com/goharbor/harbor/src/testing/mock"
+	testingproject "github.com/goharbor/harbor/src/testing/pkg/project"
 	testingreg "github.com/goharbor/harbor/src/testing/pkg/reg"
 	testingrep "github.com/goharbor/harbor/src/testing/pkg/replication"
 	testingscheduler "github.com/goharbor/harbor/src/testing/pkg/scheduler"
@@ -39,6 +40,7 @@ type replicationTestSuite struct {
 	ctl        *controller
 	repMgr     *testingrep.Manager
 	regMgr     *testingreg.Manager
+	proMgr     *testingproject.Manager
 	execMgr    *testingTask.ExecutionManager
 	taskMgr    *testingTask.Manager
 	scheduler  *testingscheduler.Scheduler
@@ -49,6 +51,7 @@ type replicationTestSuite struct {
 func (r *replicationTestSuite) SetupTest() {
 	r.repMgr = &testingrep.Manager{}
 	r.regMgr = &testingreg.Manager{}
+	r.proMgr = &testingproject.Manager{}
 	r.execMgr = &testingTask.ExecutionManager{}
 	r.taskMgr = &testingTask.Manager{}
 	r.scheduler = &testingscheduler.Scheduler{}
@@ -57,6 +60,7 @@ func (r *replicationTestSuite) SetupTest() {
 	r.ctl = &controller{
 		repMgr:     r.repMgr,
 		regMgr:     r.regMgr,
+		proMgr:     r.proMgr,
 		scheduler:  r.scheduler,
 		execMgr:    r.execMgr,
 		taskMgr:    r.taskMgr,
@@ -72,7 +76,7 @@ func (r *replicationTestSuite) TestStart() {
 	r.Require().NotNil(err)
 
 	// got error when running the replication flow
-	r.execMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
+	r.execMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
 	r.execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{}, nil)
 	r.execMgr.On("StopAndWait", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	r.execMgr.On("MarkError", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -90,7 +94,7 @@ func (r *replicationTestSuite) TestStart() {
 	r.SetupTest()
 
 	// got no error when running the replication flow
-	r.execMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
+	r.execMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
 	r.execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{}, nil)
 	r.flowCtl.On("Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	r.ormCreator.On("Create").Return(nil)
@@ -103,6 +107,25 @@ func (r *replicationTestSuite) TestStart() {
 	r.ormCreator.AssertExpectations(r.T())
 }
 
+func (r *replicationTestSuite) TestVerify() {
+	// policy is disabled
+	_, err := r.ctl.Verify(context.Background(), &repctlmodel.Policy{Enabled: false})
+	r.Require().NotNil(err)
+
+	r.execMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, task.ExecutionTriggerManual,
+		map[string]interface{}{"mode": ExecutionModeVerify}).Return(int64(1), nil)
+	r.execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{}, nil)
+	r.flowCtl.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	r.ormCreator.On("Create").Return(nil)
+	id, err := r.ctl.Verify(context.Background(), &repctlmodel.Policy{Enabled: true})
+	r.Require().Nil(err)
+	r.Equal(int64(1), id)
+	time.Sleep(1 * time.Second) // wait the functions called in the goroutine
+	r.execMgr.AssertExpectations(r.T())
+	r.flowCtl.AssertExpectations(r.T())
+	r.ormCreator.AssertExpectations(r.T())
+}
+
 func (r *replicationTestSuite) TestStop() {
 	r.execMgr.On("Stop", mock.Anything, mock.Anything).Return(nil)
 	err := r.ctl.Stop(nil, 1)
@@ -162,6 +185,7 @@ func (r *replicationTestSuite) TestGetExecution() {
 	r.Require().Nil(err)
 	r.Equal(int64(1), execution.ID)
 	r.Equal(int64(1), execution.PolicyID)
+	r.Equal(ExecutionModeCopy, execution.Mode)
 	r.execMgr.AssertExpectations(r.T())
 }
 
@@ -209,7 +233,8 @@ func (r *replicationTestSuite) TestGetTask() {
 				"resource_type":        "artifact",
 				"source_resource":      "library/hello-world",
 				"destination_resource": "library/hello-world",
-				"operation":            "copy",
+				"operation":            "verify",
+				"missing":              []interface{}{"library/hello-world:latest"},
 			},
 		},
 	}, nil)
@@ -220,7 +245,9 @@ func (r *replicationTestSuite) TestGetTask() {
 	r.Equal("artifact", task.ResourceType)
 	r.Equal("library/hello-world", task.SourceResource)
 	r.Equal("library/hello-world", task.DestinationResource)
-	r.Equal("copy", task.Operation)
+	r.Equal("verify", task.Operation)
+	r.Equal([]string{"library/hello-world:latest"}, task.MissingArtifacts)
+	r.Len(task.MismatchedArtifacts, 0)
 	r.taskMgr.AssertExpectations(r.T())
 }
 
@@ -29,6 +29,8 @@ type Flow interface {
 // Controller controls the replication flow
 type Controller interface {
 	Start(ctx context.Context, executionID int64, policy *repctlmodel.Policy, resource *model.Resource) (err error)
+	// Verify compares the resources matched by the policy in the source and destination registries
+	Verify(ctx context.Context, executionID int64, policy *repctlmodel.Policy) (err error)
 }
 
 // NewController returns an instance of the default flow controller
@@ -50,3 +52,7 @@ func (c *controller) Start(ctx context.Context, executionID int64, policy *repct
 	}
 	return NewCopyFlow(executionID, policy, resources...).Run(ctx)
 }
+
+func (c *controller) Verify(ctx context.Context, executionID int64, policy *repctlmodel.Policy) error {
+	return NewVerifyFlow(executionID, policy).Run(ctx)
+}
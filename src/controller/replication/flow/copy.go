@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 
 	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -91,7 +92,7 @@ func (c *copyFlow) Run(ctx context.Context) error {
 		return err
 	}
 
-	return c.createTasks(ctx, srcResources, dstResources, c.policy.Speed)
+	return c.createTasks(ctx, srcResources, dstResources, c.policy.Speed, c.policy.BandwidthWindows, c.policy.CopyByChunk)
 }
 
 func (c *copyFlow) isExecutionStopped(ctx context.Context) (bool, error) {
@@ -102,7 +103,12 @@ func (c *copyFlow) isExecutionStopped(ctx context.Context) (bool, error) {
 	return execution.Status == job.StoppedStatus.String(), nil
 }
 
-func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources []*model.Resource, speed int32) error {
+func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources []*model.Resource, speed int32,
+	windows []*transfer.BandwidthWindow, copyByChunk bool) error {
+	bandwidthWindows, err := json.Marshal(windows)
+	if err != nil {
+		return err
+	}
 	for i, resource := range srcResources {
 		src, err := json.Marshal(resource)
 		if err != nil {
@@ -119,9 +125,11 @@ func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources [
 				JobKind: job.KindGeneric,
 			},
 			Parameters: map[string]interface{}{
-				"src_resource": string(src),
-				"dst_resource": string(dest),
-				"speed":        speed,
+				"src_resource":      string(src),
+				"dst_resource":      string(dest),
+				"speed":             speed,
+				"bandwidth_windows": string(bandwidthWindows),
+				"copy_by_chunk":     copyByChunk,
 			},
 		}
 
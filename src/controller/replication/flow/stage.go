@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/log"
 	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -122,12 +123,30 @@ func assembleSourceResources(resources []*model.Resource,
 	return resources
 }
 
-// assemble the destination resources by filling the metadata, registry and override properties
+// assemble the destination resources by filling the metadata, registry and override properties,
+// the repository and tags are rewritten by the rewrite rules of the policy
 func assembleDestinationResources(resources []*model.Resource,
 	policy *repctlmodel.Policy, dstRepoComponentPathType string) ([]*model.Resource, error) {
+	// compile the rewrite rules once for all the resources
+	rewriter, err := policy.Rewriter()
+	if err != nil {
+		return nil, err
+	}
 	var result []*model.Resource
 	for _, resource := range resources {
-		name, err := replaceNamespace(resource.Metadata.Repository.Name, policy.DestNamespace, policy.DestNamespaceReplaceCount, dstRepoComponentPathType)
+		repository, err := rewriteRepository(resource.Metadata.Repository.Name, rewriter)
+		if err != nil {
+			return nil, err
+		}
+		name, err := replaceNamespace(repository, policy.DestNamespace, policy.DestNamespaceReplaceCount, dstRepoComponentPathType)
+		if err != nil {
+			return nil, err
+		}
+		artifacts, err := rewriteArtifactTags(resource.Metadata.Artifacts, rewriter)
+		if err != nil {
+			return nil, err
+		}
+		vtags, err := rewriteTags(resource.Metadata.Vtags, rewriter, map[string]string{})
 		if err != nil {
 			return nil, err
 		}
@@ -138,14 +157,15 @@ func assembleDestinationResources(resources []*model.Resource,
 			Deleted:      resource.Deleted,
 			IsDeleteTag:  resource.IsDeleteTag,
 			Override:     policy.Override,
+			SyncMetadata: policy.SyncMetadata,
 		}
 		res.Metadata = &model.ResourceMetadata{
 			Repository: &model.Repository{
 				Name:     name,
 				Metadata: resource.Metadata.Repository.Metadata,
 			},
-			Vtags:     resource.Metadata.Vtags,
-			Artifacts: resource.Metadata.Artifacts,
+			Vtags:     vtags,
+			Artifacts: artifacts,
 		}
 		result = append(result, res)
 	}
@@ -153,6 +173,57 @@ func assembleDestinationResources(resources []*model.Resource,
 	return result, nil
 }
 
+func rewriteRepository(repository string, rewriter *repctlmodel.Rewriter) (string, error) {
+	name := rewriter.Rewrite(repctlmodel.RewriteRuleTypeRepository, repository)
+	if !lib.RepositoryNameRe.MatchString(name) {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the repository %q is rewritten to an invalid name %q", repository, name)
+	}
+	return name, nil
+}
+
+// rewrite the tags of artifacts, the transfer copies the tags of source and destination
+// artifacts by the order, so the order of tags must be kept
+func rewriteArtifactTags(artifacts []*model.Artifact, rewriter *repctlmodel.Rewriter) ([]*model.Artifact, error) {
+	var result []*model.Artifact
+	// destination tag -> source tag
+	rewritten := map[string]string{}
+	for _, artifact := range artifacts {
+		// copy a new artifact here to avoid changing the source one
+		art := *artifact
+		tags, err := rewriteTags(artifact.Tags, rewriter, rewritten)
+		if err != nil {
+			return nil, err
+		}
+		art.Tags = tags
+		result = append(result, &art)
+	}
+	return result, nil
+}
+
+// rewrite the tags and keep the order, the rewritten map records the destination tag to the source
+// tag to detect the tags rewritten to the same one
+func rewriteTags(tags []string, rewriter *repctlmodel.Rewriter, rewritten map[string]string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t := rewriter.Rewrite(repctlmodel.RewriteRuleTypeTag, tag)
+		if !lib.TagNameRe.MatchString(t) {
+			return nil, errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the tag %q is rewritten to an invalid tag %q", tag, t)
+		}
+		if src, exist := rewritten[t]; exist {
+			return nil, errors.New(nil).WithCode(errors.ConflictCode).
+				WithMessage("both the tags %q and %q are rewritten to %q", src, tag, t)
+		}
+		rewritten[t] = tag
+		result = append(result, t)
+	}
+	return result, nil
+}
+
 // do the prepare work for pushing/uploading the resources: create the namespace or repository
 func prepareForPush(adapter adp.Adapter, resources []*model.Resource) error {
 	if err := adapter.PrepareForPush(resources); err != nil {
@@ -119,6 +119,89 @@ func (s *stageTestSuite) TestAssembleDestinationResources() {
 	s.Equal("latest", res[0].Metadata.Vtags[0])
 }
 
+func (s *stageTestSuite) TestAssembleDestinationResourcesWithRewriteRules() {
+	resources := []*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Artifacts: []*model.Artifact{
+					{
+						Digest: "sha256:1",
+						Tags:   []string{"v1.0", "latest"},
+					},
+					{
+						Digest: "sha256:2",
+					},
+				},
+				// the adapters listing the tags only
+				Vtags: []string{"v2.0", "dev"},
+			},
+		},
+	}
+	policy := &repctlmodel.Policy{
+		DestRegistry: &model.Registry{},
+		RewriteRules: []*repctlmodel.RewriteRule{
+			{
+				Type:        repctlmodel.RewriteRuleTypeRepository,
+				Match:       "library/(.*)",
+				Replacement: "mirror/$1",
+			},
+			{
+				Type:        repctlmodel.RewriteRuleTypeTag,
+				Match:       "v(.*)",
+				Replacement: "release-$1",
+			},
+		},
+	}
+	res, err := assembleDestinationResources(resources, policy, "")
+	s.Require().Nil(err)
+	s.Require().Len(res, 1)
+	s.Equal("mirror/hello-world", res[0].Metadata.Repository.Name)
+	s.Require().Len(res[0].Metadata.Artifacts, 2)
+	s.Equal([]string{"release-1.0", "latest"}, res[0].Metadata.Artifacts[0].Tags)
+	s.Empty(res[0].Metadata.Artifacts[1].Tags)
+	s.Equal([]string{"release-2.0", "dev"}, res[0].Metadata.Vtags)
+	// the source resources keep unchanged
+	s.Equal([]string{"v1.0", "latest"}, resources[0].Metadata.Artifacts[0].Tags)
+	s.Equal([]string{"v2.0", "dev"}, resources[0].Metadata.Vtags)
+
+	// the tags are rewritten to the same one
+	policy.RewriteRules = []*repctlmodel.RewriteRule{
+		{
+			Type:        repctlmodel.RewriteRuleTypeTag,
+			Match:       ".*",
+			Replacement: "stable",
+		},
+	}
+	_, err = assembleDestinationResources(resources, policy, "")
+	s.NotNil(err)
+
+	// the tag is rewritten to an invalid one
+	policy.RewriteRules = []*repctlmodel.RewriteRule{
+		{
+			Type:        repctlmodel.RewriteRuleTypeTag,
+			Match:       "v(.*)",
+			Replacement: "-$1",
+		},
+	}
+	_, err = assembleDestinationResources(resources, policy, "")
+	s.NotNil(err)
+
+	// invalid rewrite rule
+	policy.RewriteRules = []*repctlmodel.RewriteRule{
+		{
+			Type:        repctlmodel.RewriteRuleTypeTag,
+			Match:       "(",
+			Replacement: "a",
+		},
+	}
+	_, err = assembleDestinationResources(resources, policy, "")
+	s.NotNil(err)
+}
+
 func (s *stageTestSuite) TestReplaceNamespace() {
 	// empty namespace
 	var (
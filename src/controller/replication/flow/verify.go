@@ -0,0 +1,119 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"context"
+	"encoding/json"
+
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+type verifyFlow struct {
+	executionID  int64
+	policy       *repctlmodel.Policy
+	executionMgr task.ExecutionManager
+	taskMgr      task.Manager
+}
+
+// NewVerifyFlow returns an instance of the verify flow which compares the resources in
+// the source registry with the ones in the destination registry without copying anything
+func NewVerifyFlow(executionID int64, policy *repctlmodel.Policy) Flow {
+	return &verifyFlow{
+		executionMgr: task.ExecMgr,
+		taskMgr:      task.Mgr,
+		executionID:  executionID,
+		policy:       policy,
+	}
+}
+
+func (v *verifyFlow) Run(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+	srcAdapter, dstAdapter, err := initialize(v.policy)
+	if err != nil {
+		return err
+	}
+	srcResources, err := fetchResources(srcAdapter, v.policy)
+	if err != nil {
+		return err
+	}
+
+	execution, err := v.executionMgr.Get(ctx, v.executionID)
+	if err != nil {
+		return err
+	}
+	if execution.Status == job.StoppedStatus.String() {
+		logger.Debugf("the execution %d is stopped, stop the flow", v.executionID)
+		return nil
+	}
+
+	if len(srcResources) == 0 {
+		// no candidates, mark the execution as done directly
+		if err := v.executionMgr.MarkDone(ctx, v.executionID, "no resources need to be verified"); err != nil {
+			logger.Errorf("failed to mark done for the execution %d: %v", v.executionID, err)
+		}
+		return nil
+	}
+
+	srcResources = assembleSourceResources(srcResources, v.policy)
+	info, err := dstAdapter.Info()
+	if err != nil {
+		return err
+	}
+	dstResources, err := assembleDestinationResources(srcResources, v.policy, info.SupportedRepositoryPathComponentType)
+	if err != nil {
+		return err
+	}
+
+	return v.createTasks(ctx, srcResources, dstResources)
+}
+
+func (v *verifyFlow) createTasks(ctx context.Context, srcResources, dstResources []*model.Resource) error {
+	for i, resource := range srcResources {
+		src, err := json.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		dest, err := json.Marshal(dstResources[i])
+		if err != nil {
+			return err
+		}
+
+		job := &task.Job{
+			Name: job.Replication,
+			Metadata: &job.Metadata{
+				JobKind: job.KindGeneric,
+			},
+			Parameters: map[string]interface{}{
+				"src_resource": string(src),
+				"dst_resource": string(dest),
+				"verify":       true,
+			},
+		}
+
+		if _, err = v.taskMgr.Create(ctx, v.executionID, job, map[string]interface{}{
+			"operation":            "verify",
+			"resource_type":        string(resource.Type),
+			"source_resource":      getResourceName(resource),
+			"destination_resource": getResourceName(dstResources[i])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
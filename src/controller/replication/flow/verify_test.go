@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"context"
+	"testing"
+
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/task"
+	testingTask "github.com/goharbor/harbor/src/testing/pkg/task"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type verifyFlowTestSuite struct {
+	suite.Suite
+}
+
+func (v *verifyFlowTestSuite) TestRun() {
+	adp := &mockAdapter{}
+	factory := &mockFactory{}
+	factory.On("AdapterPattern").Return(nil)
+	factory.On("Create", mock.Anything).Return(adp, nil)
+	adapter.RegisterFactory("TEST_FOR_VERIFY_FLOW", factory)
+
+	adp.On("Info").Return(&model.RegistryInfo{
+		SupportedResourceTypes: []string{
+			model.ResourceTypeArtifact,
+		},
+	}, nil)
+	adp.On("FetchArtifacts", mock.Anything).Return([]*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"latest"},
+			},
+		},
+	}, nil)
+
+	execMgr := &testingTask.ExecutionManager{}
+	execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{
+		Status: job.RunningStatus.String(),
+	}, nil)
+
+	taskMgr := &testingTask.Manager{}
+	taskMgr.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(j *task.Job) bool {
+		return j.Parameters["verify"] == true
+	}), mock.MatchedBy(func(attrs map[string]interface{}) bool {
+		return attrs["operation"] == "verify"
+	})).Return(int64(1), nil)
+	policy := &repctlmodel.Policy{
+		SrcRegistry: &model.Registry{
+			Type: "TEST_FOR_VERIFY_FLOW",
+		},
+		DestRegistry: &model.Registry{
+			Type: "TEST_FOR_VERIFY_FLOW",
+		},
+	}
+	flow := &verifyFlow{
+		executionID:  1,
+		policy:       policy,
+		executionMgr: execMgr,
+		taskMgr:      taskMgr,
+	}
+	err := flow.Run(context.Background())
+	v.Require().Nil(err)
+	// no preparation is done on the destination registry
+	adp.AssertNotCalled(v.T(), "PrepareForPush", mock.Anything)
+	taskMgr.AssertExpectations(v.T())
+}
+
+func TestVerifyFlowTestSuite(t *testing.T) {
+	suite.Run(t, &verifyFlowTestSuite{})
+}
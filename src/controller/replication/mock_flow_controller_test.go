@@ -30,3 +30,17 @@ func (_m *flowController) Start(ctx context.Context, executionID int64, policy *
 
 	return r0
 }
+
+// Verify provides a mock function with given fields: ctx, executionID, policy
+func (_m *flowController) Verify(ctx context.Context, executionID int64, policy *model.Policy) error {
+	ret := _m.Called(ctx, executionID, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Policy) error); ok {
+		r0 = rf(ctx, executionID, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -28,6 +28,7 @@ type Execution struct {
 	StatusMessage string
 	Metrics       *dao.Metrics
 	Trigger       string
+	Mode          string
 	StartTime     time.Time
 	EndTime       time.Time
 }
@@ -43,6 +44,8 @@ type Task struct {
 	SourceResource      string
 	DestinationResource string
 	Operation           string
+	MissingArtifacts    []string
+	MismatchedArtifacts []string
 	JobID               string
 	CreationTime        time.Time
 	StartTime           time.Time
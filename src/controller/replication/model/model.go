@@ -17,9 +17,11 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
@@ -39,6 +41,7 @@ type Policy struct {
 	DestNamespace             string          `json:"dest_namespace"`
 	DestNamespaceReplaceCount int8            `json:"dest_namespace_replace_count"`
 	Filters                   []*model.Filter `json:"filters"`
+	RewriteRules              []*RewriteRule  `json:"rewrite_rules"`
 	Trigger                   *model.Trigger  `json:"trigger"`
 	ReplicateDeletion         bool            `json:"deletion"`
 	Override                  bool            `json:"override"`
@@ -46,6 +49,13 @@ type Policy struct {
 	CreationTime              time.Time       `json:"creation_time"`
 	UpdateTime                time.Time       `json:"update_time"`
 	Speed                     int32           `json:"speed"`
+
+	// the bandwidth windows of the time of day which override the "Speed" during the windows
+	BandwidthWindows []*transfer.BandwidthWindow `json:"bandwidth_windows"`
+	// copy the blobs by chunks so that the interrupted copy can be resumed
+	CopyByChunk bool `json:"copy_by_chunk"`
+	// sync the labels of artifacts and the metadata of projects, only for Harbor to Harbor
+	SyncMetadata bool `json:"sync_metadata"`
 }
 
 // IsScheduledTrigger returns true when the policy is scheduled trigger and enabled
@@ -86,6 +96,20 @@ func (p *Policy) Validate() error {
 		}
 	}
 
+	// valid the rewrite rules
+	for _, rule := range p.RewriteRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// valid the bandwidth windows
+	for _, window := range p.BandwidthWindows {
+		if err := window.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// valid the destination namespace
 	if len(p.DestNamespace) > 0 {
 		if !lib.RepositoryNameRe.MatchString(p.DestNamespace) {
@@ -132,6 +156,8 @@ func (p *Policy) From(policy *replicationmodel.Policy) error {
 	p.CreationTime = policy.CreationTime
 	p.UpdateTime = policy.UpdateTime
 	p.Speed = policy.Speed
+	p.CopyByChunk = policy.CopyByChunk
+	p.SyncMetadata = policy.SyncMetadata
 
 	if policy.SrcRegistryID > 0 {
 		p.SrcRegistry = &model.Registry{
@@ -151,6 +177,20 @@ func (p *Policy) From(policy *replicationmodel.Policy) error {
 	}
 	p.Filters = filters
 
+	// parse RewriteRules
+	if len(policy.RewriteRules) > 0 {
+		if err = json.Unmarshal([]byte(policy.RewriteRules), &p.RewriteRules); err != nil {
+			return err
+		}
+	}
+
+	// parse BandwidthWindows
+	if len(policy.BandwidthWindows) > 0 {
+		if err = json.Unmarshal([]byte(policy.BandwidthWindows), &p.BandwidthWindows); err != nil {
+			return err
+		}
+	}
+
 	// parse Trigger
 	trigger, err := parseTrigger(policy.Trigger)
 	if err != nil {
@@ -176,6 +216,8 @@ func (p *Policy) To() (*replicationmodel.Policy, error) {
 		CreationTime:              p.CreationTime,
 		UpdateTime:                p.UpdateTime,
 		Speed:                     p.Speed,
+		CopyByChunk:               p.CopyByChunk,
+		SyncMetadata:              p.SyncMetadata,
 	}
 	if p.SrcRegistry != nil {
 		policy.SrcRegistryID = p.SrcRegistry.ID
@@ -200,9 +242,101 @@ func (p *Policy) To() (*replicationmodel.Policy, error) {
 		policy.Filters = string(filters)
 	}
 
+	if len(p.RewriteRules) > 0 {
+		rules, err := json.Marshal(p.RewriteRules)
+		if err != nil {
+			return nil, err
+		}
+		policy.RewriteRules = string(rules)
+	}
+
+	if len(p.BandwidthWindows) > 0 {
+		windows, err := json.Marshal(p.BandwidthWindows)
+		if err != nil {
+			return nil, err
+		}
+		policy.BandwidthWindows = string(windows)
+	}
+
 	return policy, nil
 }
 
+// Rewriter returns the rewriter with the rewrite rules of the policy compiled
+func (p *Policy) Rewriter() (*Rewriter, error) {
+	rewriter := &Rewriter{}
+	for _, rule := range p.RewriteRules {
+		re, err := rule.regexp()
+		if err != nil {
+			return nil, err
+		}
+		rewriter.rules = append(rewriter.rules, &compiledRewriteRule{
+			RewriteRule: rule,
+			re:          re,
+		})
+	}
+	return rewriter, nil
+}
+
+// Rewriter rewrites the repository paths and tags with the compiled rewrite rules
+type Rewriter struct {
+	rules []*compiledRewriteRule
+}
+
+type compiledRewriteRule struct {
+	*RewriteRule
+	re *regexp.Regexp
+}
+
+// Rewrite rewrites the value with the first rule of the specified type that matches the whole value,
+// the value is returned as it is if no rule matches
+func (r *Rewriter) Rewrite(ruleType, value string) string {
+	for _, rule := range r.rules {
+		if rule.Type != ruleType {
+			continue
+		}
+		if rule.re.MatchString(value) {
+			return rule.re.ReplaceAllString(value, rule.Replacement)
+		}
+	}
+	return value
+}
+
+// const definition
+const (
+	RewriteRuleTypeRepository = "repository"
+	RewriteRuleTypeTag        = "tag"
+)
+
+// RewriteRule rewrites the repository path or tag of the destination artifacts, e.g. the rule
+// with match "v(.*)" and replacement "release-$1" rewrites the tag "v1.0" to "release-1.0"
+type RewriteRule struct {
+	Type        string `json:"type"`
+	Match       string `json:"match"`
+	Replacement string `json:"replacement"`
+}
+
+// Validate the rewrite rule
+func (r *RewriteRule) Validate() error {
+	if r.Type != RewriteRuleTypeRepository && r.Type != RewriteRuleTypeTag {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid rewrite rule type: %s", r.Type)
+	}
+	if len(r.Match) == 0 || len(r.Replacement) == 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the match and replacement of rewrite rule cannot be empty")
+	}
+	if _, err := r.regexp(); err != nil {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid match of rewrite rule %s: %v", r.Match, err)
+	}
+	return nil
+}
+
+// the match must cover the whole value
+func (r *RewriteRule) regexp() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + r.Match + ")$")
+}
+
 type filter struct {
 	Type       string      `json:"type"`
 	Value      interface{} `json:"value"`
@@ -285,6 +419,18 @@ func parseFilters(str string) ([]*model.Filter, error) {
 			}
 			filter.Value = labels
 		}
+		// the value of signature filter submitted by the portal is string
+		if filter.Type == model.FilterTypeSignature {
+			if signed, ok := filter.Value.(string); ok {
+				filter.Value = signed == "true"
+			}
+		}
+		// the number is unmarshalled as float64 and the value submitted by the portal is string
+		if filter.Type == model.FilterTypePushTime {
+			if days, err := model.ParsePushTimeDays(filter.Value); err == nil {
+				filter.Value = days
+			}
+		}
 		filters = append(filters, filter)
 	}
 	return filters, nil
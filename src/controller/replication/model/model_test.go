@@ -17,9 +17,11 @@ package model
 import (
 	"testing"
 
+	"github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsScheduledTrigger(t *testing.T) {
@@ -125,6 +127,65 @@ func TestValidate(t *testing.T) {
 	err = policy.Validate()
 	assert.True(errors.IsErr(err, errors.BadRequestCode))
 
+	// invalid push time filter
+	policy = &Policy{
+		Name: "policy01",
+		SrcRegistry: &model.Registry{
+			ID: 0,
+		},
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+		Filters: []*model.Filter{
+			{
+				Type:  model.FilterTypePushTime,
+				Value: 1.5,
+			},
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// invalid rewrite rule
+	policy = &Policy{
+		Name: "policy01",
+		SrcRegistry: &model.Registry{
+			ID: 0,
+		},
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+		RewriteRules: []*RewriteRule{
+			{
+				Type:        RewriteRuleTypeTag,
+				Match:       "v(.*",
+				Replacement: "release-$1",
+			},
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// invalid bandwidth window
+	policy = &Policy{
+		Name: "policy01",
+		SrcRegistry: &model.Registry{
+			ID: 0,
+		},
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+		BandwidthWindows: []*transfer.BandwidthWindow{
+			{
+				Start: "08:00",
+				End:   "24:00",
+				Speed: 1024,
+			},
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
 	// invalid trigger
 	policy = &Policy{
 		Name: "policy01",
@@ -226,4 +287,83 @@ func TestValidate(t *testing.T) {
 	}
 	err = policy.Validate()
 	assert.Nil(err)
+
+	// the value of signature filter submitted by the portal is string
+	policy.Filters = append(policy.Filters, &model.Filter{
+		Type:  model.FilterTypeSignature,
+		Value: "true",
+	})
+	err = policy.Validate()
+	assert.Nil(err)
+
+	// invalid signature filter
+	policy.Filters[2].Value = "yes"
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// the value of push time filter submitted by the portal is string
+	policy.Filters[2] = &model.Filter{
+		Type:       model.FilterTypePushTime,
+		Value:      "7",
+		Decoration: model.Excludes,
+	}
+	err = policy.Validate()
+	assert.Nil(err)
+
+	// invalid push time filter
+	policy.Filters[2].Value = "seven"
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// unknown severity can't be the threshold of vulnerability filter
+	policy.Filters[2] = &model.Filter{
+		Type:  model.FilterTypeVulnerability,
+		Value: "Unknown",
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+}
+
+func TestParseFilters(t *testing.T) {
+	filters, err := parseFilters(`[{"type":"signature","value":"true"},{"type":"vulnerability","value":"High"},` +
+		`{"type":"push_time","value":"7"},{"type":"push_time","value":30}]`)
+	require.Nil(t, err)
+	require.Len(t, filters, 4)
+	assert.Equal(t, true, filters[0].Value)
+	assert.Equal(t, "High", filters[1].Value)
+	assert.Equal(t, 7, filters[2].Value)
+	assert.Equal(t, 30, filters[3].Value)
+}
+
+func TestRewrite(t *testing.T) {
+	policy := &Policy{
+		RewriteRules: []*RewriteRule{
+			{
+				Type:        RewriteRuleTypeTag,
+				Match:       "v(.*)",
+				Replacement: "release-$1",
+			},
+			{
+				Type:        RewriteRuleTypeTag,
+				Match:       "dev",
+				Replacement: "snapshot",
+			},
+		},
+	}
+	rewriter, err := policy.Rewriter()
+	require.Nil(t, err)
+	assert.Equal(t, "release-1.0", rewriter.Rewrite(RewriteRuleTypeTag, "v1.0"))
+	// the whole value must be matched
+	assert.Equal(t, "dev1", rewriter.Rewrite(RewriteRuleTypeTag, "dev1"))
+	assert.Equal(t, "snapshot", rewriter.Rewrite(RewriteRuleTypeTag, "dev"))
+	assert.Equal(t, "library/v1", rewriter.Rewrite(RewriteRuleTypeRepository, "library/v1"))
+
+	// invalid rule
+	policy.RewriteRules = append(policy.RewriteRules, &RewriteRule{
+		Type:        RewriteRuleTypeTag,
+		Match:       "(",
+		Replacement: "a",
+	})
+	_, err = policy.Rewriter()
+	assert.NotNil(t, err)
 }
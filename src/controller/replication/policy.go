@@ -17,11 +17,19 @@ package replication
 import (
 	"context"
 	"strconv"
+	"strings"
 
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/controller/replication/model"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	regmodel "github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
 	pkgmodel "github.com/goharbor/harbor/src/pkg/replication/model"
 	"github.com/goharbor/harbor/src/pkg/scheduler"
 	"github.com/goharbor/harbor/src/pkg/task"
@@ -123,6 +131,7 @@ func (c *controller) CreatePolicy(ctx context.Context, policy *model.Policy) (in
 			return 0, err
 		}
 	}
+	c.fireEvent(ctx, event.TopicCreatePolicy, id, policy)
 	return id, nil
 }
 
@@ -150,6 +159,7 @@ func (c *controller) UpdatePolicy(ctx context.Context, policy *model.Policy, pro
 			return err
 		}
 	}
+	c.fireEvent(ctx, event.TopicUpdatePolicy, policy.ID, policy)
 	return nil
 }
 
@@ -171,6 +181,15 @@ func (c *controller) validatePolicy(ctx context.Context, policy *model.Policy) e
 }
 
 func (c *controller) DeletePolicy(ctx context.Context, id int64) error {
+	// get the policy before deleting for the event
+	p, err := c.repMgr.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	policy := &model.Policy{}
+	if err = policy.From(p); err != nil {
+		return err
+	}
 	// delete the executions
 	if err := c.execMgr.DeleteByVendor(ctx, job.Replication, id); err != nil {
 		return err
@@ -180,5 +199,78 @@ func (c *controller) DeletePolicy(ctx context.Context, id int64) error {
 		return err
 	}
 	// delete the policy
-	return c.repMgr.Delete(ctx, id)
+	if err = c.repMgr.Delete(ctx, id); err != nil {
+		return err
+	}
+	c.fireEvent(ctx, event.TopicDeletePolicy, id, policy)
+	return nil
+}
+
+// fireEvent adds the replication policy events into the context, an event is published to each local
+// project that the policy replicates from or to, and the event is published to the system level if the
+// policy isn't related with the specific projects, e.g. the policy replicating all the repositories
+func (c *controller) fireEvent(ctx context.Context, topic string, id int64, policy *model.Policy) {
+	projectIDs := c.relatedProjects(ctx, id, policy)
+	if len(projectIDs) == 0 {
+		projectIDs = []int64{policy_model.SystemLevelProjectID}
+	}
+	for _, projectID := range projectIDs {
+		notification.AddEvent(ctx, &metadata.PolicyMetaData{
+			Topic:      topic,
+			PolicyType: event.PolicyTypeReplication,
+			PolicyID:   id,
+			PolicyName: policy.Name,
+			ProjectID:  projectID,
+			Operator:   operator.FromContext(ctx),
+		})
+	}
+}
+
+// relatedProjects returns the IDs of the local projects that the policy replicates from(push-based)
+// or to(pull-based), nil is returned if the policy covers all the projects
+func (c *controller) relatedProjects(ctx context.Context, id int64, policy *model.Policy) []int64 {
+	pattern := relatedProjectPattern(policy)
+	// "**" matches the repositories of any project
+	if len(pattern) == 0 || strings.Contains(pattern, "**") {
+		return nil
+	}
+	if !strings.ContainsAny(pattern, "*?[{") {
+		p, err := c.proMgr.Get(ctx, pattern)
+		if err != nil {
+			log.Debugf("failed to get the project %s related with the replication policy %d: %v", pattern, id, err)
+			return nil
+		}
+		return []int64{p.ProjectID}
+	}
+	projects, err := c.proMgr.List(ctx, nil)
+	if err != nil {
+		log.Errorf("failed to list the projects related with the replication policy %d: %v", id, err)
+		return nil
+	}
+	var projectIDs []int64
+	for _, p := range projects {
+		if match, err := util.Match(pattern, p.Name); err == nil && match {
+			projectIDs = append(projectIDs, p.ProjectID)
+		}
+	}
+	return projectIDs
+}
+
+// relatedProjectPattern returns the name pattern of the local projects that the policy replicates
+// from(push-based) or to(pull-based), empty string is returned if the policy has no name filter
+func relatedProjectPattern(policy *model.Policy) string {
+	// pull-based policy with the destination namespace specified
+	if policy.SrcRegistry != nil && policy.SrcRegistry.ID > 0 && len(policy.DestNamespace) > 0 {
+		return strings.SplitN(policy.DestNamespace, "/", 2)[0]
+	}
+	for _, filter := range policy.Filters {
+		if filter.Type != regmodel.FilterTypeName {
+			continue
+		}
+		if value, ok := filter.Value.(string); ok {
+			return strings.SplitN(value, "/", 2)[0]
+		}
+		break
+	}
+	return ""
 }
@@ -15,7 +15,10 @@
 package replication
 
 import (
+	"context"
+
 	repmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	replicationmodel "github.com/goharbor/harbor/src/pkg/replication/model"
 	"github.com/goharbor/harbor/src/testing/mock"
@@ -68,11 +71,16 @@ func (r *replicationTestSuite) TestCreatePolicy() {
 		ID: 1,
 	}, nil)
 	mock.OnAnything(r.scheduler, "Schedule").Return(int64(1), nil)
-	id, err := r.ctl.CreatePolicy(nil, &repmodel.Policy{
+	mock.OnAnything(r.proMgr, "Get").Return(&proModels.Project{
+		ProjectID: 1,
+		Name:      "library",
+	}, nil)
+	id, err := r.ctl.CreatePolicy(context.TODO(), &repmodel.Policy{
 		Name: "rule",
 		SrcRegistry: &model.Registry{
 			ID: 1,
 		},
+		DestNamespace: "library",
 		Trigger: &model.Trigger{
 			Type: model.TriggerTypeScheduled,
 			Settings: &model.TriggerSettings{
@@ -86,6 +94,7 @@ func (r *replicationTestSuite) TestCreatePolicy() {
 	r.repMgr.AssertExpectations(r.T())
 	r.regMgr.AssertExpectations(r.T())
 	r.scheduler.AssertExpectations(r.T())
+	r.proMgr.AssertExpectations(r.T())
 }
 
 func (r *replicationTestSuite) TestUpdatePolicy() {
@@ -95,7 +104,7 @@ func (r *replicationTestSuite) TestUpdatePolicy() {
 	mock.OnAnything(r.scheduler, "UnScheduleByVendor").Return(nil)
 	mock.OnAnything(r.scheduler, "Schedule").Return(int64(1), nil)
 	mock.OnAnything(r.repMgr, "Update").Return(nil)
-	err := r.ctl.UpdatePolicy(nil, &repmodel.Policy{
+	err := r.ctl.UpdatePolicy(context.TODO(), &repmodel.Policy{
 		ID:   1,
 		Name: "rule",
 		SrcRegistry: &model.Registry{
@@ -118,10 +127,80 @@ func (r *replicationTestSuite) TestUpdatePolicy() {
 func (r *replicationTestSuite) TestDeletePolicy() {
 	mock.OnAnything(r.execMgr, "DeleteByVendor").Return(nil)
 	mock.OnAnything(r.scheduler, "UnScheduleByVendor").Return(nil)
+	mock.OnAnything(r.repMgr, "Get").Return(&replicationmodel.Policy{
+		ID:   1,
+		Name: "rule",
+	}, nil)
 	mock.OnAnything(r.repMgr, "Delete").Return(nil)
-	err := r.ctl.DeletePolicy(nil, 1)
+	err := r.ctl.DeletePolicy(context.TODO(), 1)
 	r.Require().Nil(err)
 	r.repMgr.AssertExpectations(r.T())
 	r.execMgr.AssertExpectations(r.T())
 	r.scheduler.AssertExpectations(r.T())
 }
+
+func (r *replicationTestSuite) TestRelatedProjectPattern() {
+	// pull-based policy with the destination namespace
+	r.Equal("library", relatedProjectPattern(&repmodel.Policy{
+		SrcRegistry:   &model.Registry{ID: 1},
+		DestNamespace: "library",
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeName, Value: "remote/**"},
+		},
+	}))
+	// push-based policy with the name filter
+	r.Equal("library", relatedProjectPattern(&repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeTag, Value: "v1"},
+			{Type: model.FilterTypeName, Value: "library/**"},
+		},
+	}))
+	r.Equal("lib*", relatedProjectPattern(&repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeName, Value: "lib*/hello-world"},
+		},
+	}))
+	// no name filter
+	r.Equal("", relatedProjectPattern(&repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+	}))
+}
+
+func (r *replicationTestSuite) TestRelatedProjects() {
+	ctl := r.ctl
+	// replicate all the repositories
+	r.Nil(ctl.relatedProjects(context.TODO(), 1, &repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+	}))
+	r.Nil(ctl.relatedProjects(context.TODO(), 1, &repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeName, Value: "**"},
+		},
+	}))
+
+	// the specific project
+	r.proMgr.On("Get", mock.Anything, "library").Return(&proModels.Project{ProjectID: 1, Name: "library"}, nil).Once()
+	r.Equal([]int64{1}, ctl.relatedProjects(context.TODO(), 1, &repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeName, Value: "library/**"},
+		},
+	}))
+
+	// the projects matched by the pattern
+	r.proMgr.On("List", mock.Anything, mock.Anything).Return([]*proModels.Project{
+		{ProjectID: 1, Name: "library"},
+		{ProjectID: 2, Name: "test"},
+		{ProjectID: 3, Name: "libs"},
+	}, nil).Once()
+	r.Equal([]int64{1, 3}, ctl.relatedProjects(context.TODO(), 1, &repmodel.Policy{
+		DestRegistry: &model.Registry{ID: 1},
+		Filters: []*model.Filter{
+			{Type: model.FilterTypeName, Value: "lib*/hello-world"},
+		},
+	}))
+	r.proMgr.AssertExpectations(r.T())
+}
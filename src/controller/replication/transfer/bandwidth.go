@@ -0,0 +1,176 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transfer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	// embeds the time zone database as the images may not contain it
+	_ "time/tzdata"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// Options of the transfer
+type Options struct {
+	// the network speed limit(kb/s) applies when the time is out of all the bandwidth
+	// windows, the value less than or equal to 0 means unlimited
+	Speed int32
+	// the bandwidth windows of the time of day
+	BandwidthWindows []*BandwidthWindow
+	// copy the blobs by chunks so that the interrupted copy can be resumed
+	CopyByChunk bool
+}
+
+// SpeedAt returns the network speed limit(kb/s) at the specified time, the speed of the
+// first window that contains the time wins and the value less than or equal to 0 means unlimited
+func (o *Options) SpeedAt(t time.Time) int32 {
+	if o == nil {
+		return 0
+	}
+	for _, window := range o.BandwidthWindows {
+		if window.Contains(t) {
+			return window.Speed
+		}
+	}
+	return o.Speed
+}
+
+// Limited returns whether the network speed is limited at any time
+func (o *Options) Limited() bool {
+	if o == nil {
+		return false
+	}
+	if o.Speed > 0 {
+		return true
+	}
+	for _, window := range o.BandwidthWindows {
+		if window.Speed > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the description of the speed limit, e.g. "1024 kb/s during 08:00-18:00 UTC, unlimited otherwise"
+func (o *Options) String() string {
+	if o == nil {
+		return speedString(0)
+	}
+	var windows []string
+	for _, window := range o.BandwidthWindows {
+		windows = append(windows, fmt.Sprintf("%s during %s-%s %s", speedString(window.Speed), window.Start, window.End, window.timezone()))
+	}
+	if len(windows) == 0 {
+		return speedString(o.Speed)
+	}
+	return fmt.Sprintf("%s, %s otherwise", strings.Join(windows, ", "), speedString(o.Speed))
+}
+
+func speedString(speed int32) string {
+	if speed <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d kb/s", speed)
+}
+
+// BandwidthWindow limits the network speed during the time of day between
+// "Start"(inclusive) and "End"(exclusive) in format "HH:MM" of the time zone "Timezone",
+// the window crosses midnight if the "End" is earlier than the "Start", e.g. "22:00" to "06:00"
+type BandwidthWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// the IANA time zone name, e.g. "America/New_York", empty means UTC
+	Timezone string `json:"timezone,omitempty"`
+	// the network speed limit(kb/s), the value less than or equal to 0 means unlimited
+	Speed int32 `json:"speed"`
+
+	// the window is checked on every read of the transfer, so the start, end and
+	// location are parsed only once and cached
+	once  sync.Once
+	start int
+	end   int
+	loc   *time.Location
+	err   error
+}
+
+func (b *BandwidthWindow) timezone() string {
+	if len(b.Timezone) == 0 {
+		return "UTC"
+	}
+	return b.Timezone
+}
+
+func (b *BandwidthWindow) location() (*time.Location, error) {
+	loc, err := time.LoadLocation(b.timezone())
+	if err != nil {
+		return nil, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid time zone of bandwidth window: %s", b.Timezone)
+	}
+	return loc, nil
+}
+
+// parse the start, end and time zone of the window once and cache the results
+func (b *BandwidthWindow) parse() error {
+	b.once.Do(func() {
+		if b.start, b.err = parseTimeOfDay(b.Start); b.err != nil {
+			return
+		}
+		if b.end, b.err = parseTimeOfDay(b.End); b.err != nil {
+			return
+		}
+		b.loc, b.err = b.location()
+	})
+	return b.err
+}
+
+// Validate the bandwidth window
+func (b *BandwidthWindow) Validate() error {
+	if err := b.parse(); err != nil {
+		return err
+	}
+	if b.start == b.end {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the start and end of bandwidth window cannot be same: %s", b.Start)
+	}
+	return nil
+}
+
+// Contains returns whether the time of day of the specified time in the time zone of the window is in the window
+func (b *BandwidthWindow) Contains(t time.Time) bool {
+	if err := b.parse(); err != nil {
+		return false
+	}
+	t = t.In(b.loc)
+	minute := t.Hour()*60 + t.Minute()
+	if b.start <= b.end {
+		return minute >= b.start && minute < b.end
+	}
+	// crosses midnight
+	return minute >= b.start || minute < b.end
+}
+
+// parseTimeOfDay parses the time of day in format "HH:MM" into minutes since midnight
+func parseTimeOfDay(s string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(s, "%02d:%02d", &hour, &minute); err != nil || len(s) != 5 ||
+		hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid time of day of bandwidth window, it must be in format HH:MM: %s", s)
+	}
+	return hour*60 + minute, nil
+}
@@ -0,0 +1,134 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transfer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidthWindowValidate(t *testing.T) {
+	window := &BandwidthWindow{Start: "08:00", End: "18:00"}
+	assert.Nil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "22:00", End: "06:00"}
+	assert.Nil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "8:00", End: "18:00"}
+	assert.NotNil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "08:00", End: "24:00"}
+	assert.NotNil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "08:00", End: "08:60"}
+	assert.NotNil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "08:00", End: "08:00"}
+	assert.NotNil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "08:00", End: "18:00", Timezone: "Asia/Shanghai"}
+	assert.Nil(t, window.Validate())
+
+	window = &BandwidthWindow{Start: "08:00", End: "18:00", Timezone: "Mars/Olympus"}
+	assert.NotNil(t, window.Validate())
+}
+
+func TestBandwidthWindowContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	window := &BandwidthWindow{Start: "08:00", End: "18:00"}
+	assert.False(t, window.Contains(at(7, 59)))
+	assert.True(t, window.Contains(at(8, 0)))
+	assert.True(t, window.Contains(at(17, 59)))
+	assert.False(t, window.Contains(at(18, 0)))
+
+	// crosses midnight
+	window = &BandwidthWindow{Start: "22:00", End: "06:00"}
+	assert.True(t, window.Contains(at(23, 0)))
+	assert.True(t, window.Contains(at(0, 0)))
+	assert.False(t, window.Contains(at(6, 0)))
+	assert.False(t, window.Contains(at(12, 0)))
+
+	// the time is evaluated in the time zone of the window, 08:00-18:00 in UTC+8 is 00:00-10:00 in UTC
+	window = &BandwidthWindow{Start: "08:00", End: "18:00", Timezone: "Asia/Shanghai"}
+	assert.True(t, window.Contains(at(0, 0)))
+	assert.True(t, window.Contains(at(9, 59)))
+	assert.False(t, window.Contains(at(10, 0)))
+	assert.False(t, window.Contains(at(23, 59)))
+}
+
+func TestOptions(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	// nil options
+	var opts *Options
+	assert.False(t, opts.Limited())
+	assert.Equal(t, int32(0), opts.SpeedAt(at(9, 0)))
+	assert.Equal(t, "unlimited", opts.String())
+
+	// static speed
+	opts = &Options{Speed: 100}
+	assert.True(t, opts.Limited())
+	assert.Equal(t, int32(100), opts.SpeedAt(at(9, 0)))
+	assert.Equal(t, "100 kb/s", opts.String())
+
+	// limited during business hours and unlimited otherwise
+	opts = &Options{
+		Speed: -1,
+		BandwidthWindows: []*BandwidthWindow{
+			{Start: "08:00", End: "18:00", Speed: 1024},
+			{Start: "07:00", End: "20:00", Speed: 2048},
+		},
+	}
+	assert.True(t, opts.Limited())
+	assert.Equal(t, int32(1024), opts.SpeedAt(at(9, 0)))
+	assert.Equal(t, int32(2048), opts.SpeedAt(at(19, 0)))
+	assert.Equal(t, int32(-1), opts.SpeedAt(at(21, 0)))
+	assert.Equal(t, "1024 kb/s during 08:00-18:00 UTC, 2048 kb/s during 07:00-20:00 UTC, unlimited otherwise", opts.String())
+
+	// unlimited windows only
+	opts = &Options{
+		BandwidthWindows: []*BandwidthWindow{
+			{Start: "08:00", End: "18:00"},
+		},
+	}
+	assert.False(t, opts.Limited())
+}
+
+func TestScheduledReader(t *testing.T) {
+	data := bytes.Repeat([]byte{'a'}, 1024)
+	now := time.Now()
+	start := now.Add(-time.Hour).Format("15:04")
+	end := now.Add(time.Hour).Format("15:04")
+	r := NewScheduledReader(ioutil.NopCloser(bytes.NewReader(data)), &Options{
+		BandwidthWindows: []*BandwidthWindow{
+			{Start: start, End: end, Speed: 1024},
+		},
+	})
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	require.Nil(t, err)
+	assert.Equal(t, data, content)
+	assert.Equal(t, int32(1024), r.(*reader).speed)
+}
@@ -16,6 +16,7 @@ package chart
 
 import (
 	"errors"
+	"fmt"
 
 	trans "github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/lib/log"
@@ -49,7 +50,7 @@ type transfer struct {
 	dst       adapter.ChartRegistry
 }
 
-func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, speed int32) error {
+func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, opts *trans.Options) error {
 	// initialize
 	if err := t.initialize(src, dst); err != nil {
 		return err
@@ -78,7 +79,30 @@ func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, speed int3
 		version: dst.Metadata.Artifacts[0].Tags[0],
 	}
 	// copy the chart from source registry to the destination
-	return t.copy(srcChart, dstChart, dst.Override, speed)
+	return t.copy(srcChart, dstChart, dst.Override, opts)
+}
+
+// Verify only checks the existence of the chart on the destination registry as
+// the chart registries don't expose the digests of the charts
+func (t *transfer) Verify(src *model.Resource, dst *model.Resource) (*trans.VerifyResult, error) {
+	if err := t.initialize(src, dst); err != nil {
+		return nil, err
+	}
+
+	result := &trans.VerifyResult{}
+	name, version := dst.Metadata.Repository.Name, dst.Metadata.Artifacts[0].Tags[0]
+	exist, err := t.dst.ChartExist(name, version)
+	if err != nil {
+		t.logger.Errorf("failed to check the existence of chart %s:%s on the destination registry: %v", name, version, err)
+		return nil, err
+	}
+	if !exist {
+		t.logger.Warningf("the chart %s:%s is missing on the destination registry", name, version)
+		result.Missing = append(result.Missing, fmt.Sprintf("%s:%s", name, version))
+		return result, nil
+	}
+	t.logger.Infof("the chart %s:%s exists on the destination registry", name, version)
+	return result, nil
 }
 
 func (t *transfer) initialize(src, dst *model.Resource) error {
@@ -129,7 +153,7 @@ func (t *transfer) shouldStop() bool {
 	return isStopped
 }
 
-func (t *transfer) copy(src, dst *chart, override bool, speed int32) error {
+func (t *transfer) copy(src, dst *chart, override bool, opts *trans.Options) error {
 	if t.shouldStop() {
 		return nil
 	}
@@ -160,9 +184,9 @@ func (t *transfer) copy(src, dst *chart, override bool, speed int32) error {
 		t.logger.Errorf("failed to download the chart %s:%s: %v", src.name, src.version, err)
 		return err
 	}
-	if speed > 0 {
-		t.logger.Infof("limit network speed at %d kb/s", speed)
-		chart = trans.NewReader(chart, speed)
+	if opts.Limited() {
+		t.logger.Infof("limit network speed at %s", opts)
+		chart = trans.NewScheduledReader(chart, opts)
 	}
 	defer chart.Close()
 
@@ -15,7 +15,9 @@
 package image
 
 import (
-	"errors"
+	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"strconv"
@@ -28,6 +30,7 @@ import (
 	"github.com/docker/distribution/manifest/schema2"
 	common_http "github.com/goharbor/harbor/src/common/http"
 	trans "github.com/goharbor/harbor/src/controller/replication/transfer"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -36,6 +39,7 @@ import (
 
 var (
 	retry      int
+	chunkSize  int64
 	errStopped = errors.New("stopped")
 )
 
@@ -44,6 +48,10 @@ func init() {
 	if retry <= 0 {
 		retry = 5
 	}
+	chunkSize, _ = strconv.ParseInt(os.Getenv("COPY_BLOB_CHUNK_SIZE"), 10, 64)
+	if chunkSize <= 0 {
+		chunkSize = 10 * 1024 * 1024
+	}
 	if err := trans.RegisterFactory(model.ResourceTypeImage, factory); err != nil {
 		log.Errorf("failed to register transfer factory: %v", err)
 	}
@@ -57,6 +65,14 @@ type repository struct {
 	tags       []string
 }
 
+// blobUpload records the upload session of the blob copied by chunks, so that
+// the retries can resume the copy from the last committed byte
+type blobUpload struct {
+	location string
+	// the offset of the next byte to copy
+	offset int64
+}
+
 func factory(logger trans.Logger, stopFunc trans.StopFunc) (trans.Transfer, error) {
 	return &transfer{
 		logger:    logger,
@@ -70,9 +86,11 @@ type transfer struct {
 	src       adapter.ArtifactRegistry
 	dst       adapter.ArtifactRegistry
 	speed     int32
+	// the bytes of blobs copied by the task
+	copied int64
 }
 
-func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, speed int32) error {
+func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, opts *trans.Options) error {
 	// initialize
 	if err := t.initialize(src, dst); err != nil {
 		return err
@@ -89,7 +107,120 @@ func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, speed int3
 	}
 
 	// copy the repository from source registry to the destination
-	return t.copy(t.convert(src), t.convert(dst), dst.Override, speed)
+	if err := t.copy(t.convert(src), t.convert(dst), dst.Override, opts); err != nil {
+		return err
+	}
+
+	// sync the labels of the artifacts, the artifacts are copied already, so failing to sync
+	// the labels doesn't fail the task
+	if dst.SyncMetadata {
+		t.syncLabels(dst)
+	}
+	return nil
+}
+
+func (t *transfer) Verify(src *model.Resource, dst *model.Resource) (*trans.VerifyResult, error) {
+	if err := t.initialize(src, dst); err != nil {
+		return nil, err
+	}
+	return t.verify(t.convert(src), t.convert(dst))
+}
+
+func (t *transfer) verify(srcRepo, dstRepo *repository) (*trans.VerifyResult, error) {
+	t.logger.Infof("verifying %s:[%s](source registry) against %s:[%s](destination registry)...",
+		srcRepo.repository, strings.Join(srcRepo.tags, ","), dstRepo.repository, strings.Join(dstRepo.tags, ","))
+	result := &trans.VerifyResult{}
+	for i := range srcRepo.tags {
+		if t.shouldStop() {
+			return result, nil
+		}
+		srcDigest, err := t.digestOf(t.src, srcRepo.repository, srcRepo.tags[i])
+		if err != nil {
+			t.logger.Errorf("failed to get the digest of artifact %s:%s on the source registry: %v",
+				srcRepo.repository, srcRepo.tags[i], err)
+			return nil, err
+		}
+		exist, dstDigest, err := t.exist(dstRepo.repository, dstRepo.tags[i])
+		if err != nil {
+			return nil, err
+		}
+		name := formatReference(dstRepo.repository, dstRepo.tags[i])
+		if !exist {
+			t.logger.Warningf("the artifact %s is missing on the destination registry", name)
+			result.Missing = append(result.Missing, name)
+			continue
+		}
+		// some registries don't return the digest when checking the existence, pull the manifest to get it
+		if len(dstDigest) == 0 {
+			if dstDigest, err = t.digestOf(t.dst, dstRepo.repository, dstRepo.tags[i]); err != nil {
+				t.logger.Errorf("failed to get the digest of artifact %s on the destination registry: %v", name, err)
+				return nil, err
+			}
+		}
+		if srcDigest != dstDigest {
+			t.logger.Warningf("the digest of artifact %s on the destination registry is %s, but %s on the source registry",
+				name, dstDigest, srcDigest)
+			result.Mismatched = append(result.Mismatched, name)
+			continue
+		}
+		t.logger.Infof("the artifact %s is consistent with the source registry", name)
+	}
+	t.logger.Infof("verify completed, %d artifacts missing, %d artifacts mismatched",
+		len(result.Missing), len(result.Mismatched))
+	return result, nil
+}
+
+// digestOf returns the digest of the artifact specified by the reference in the registry
+func (t *transfer) digestOf(registry adapter.ArtifactRegistry, repository, reference string) (string, error) {
+	_, desc, err := registry.ManifestExist(repository, reference)
+	if err != nil {
+		return "", err
+	}
+	if desc != nil && len(desc.Digest) > 0 {
+		return string(desc.Digest), nil
+	}
+	_, digest, err := registry.PullManifest(repository, reference)
+	if err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+func formatReference(repository, reference string) string {
+	if strings.Contains(reference, ":") {
+		return repository + "@" + reference
+	}
+	return repository + ":" + reference
+}
+
+func (t *transfer) syncLabels(resource *model.Resource) {
+	if t.shouldStop() {
+		return
+	}
+	registry, ok := t.dst.(adapter.LabelRegistry)
+	if !ok {
+		t.logger.Warning("the destination registry doesn't support syncing labels, skip")
+		return
+	}
+	repository := resource.Metadata.Repository.Name
+	for _, artifact := range resource.Metadata.Artifacts {
+		if len(artifact.LabelDetails) == 0 {
+			continue
+		}
+		reference := artifact.Digest
+		if len(artifact.Tags) > 0 {
+			reference = artifact.Tags[0]
+		}
+		if len(reference) == 0 {
+			continue
+		}
+		if err := registry.AddLabels(repository, reference, artifact.LabelDetails); err != nil {
+			t.logger.Warningf("failed to add the labels %v to the artifact %s:%s, skip: %v",
+				artifact.Labels, repository, reference, err)
+			continue
+		}
+		t.logger.Infof("the labels %v added to the artifact %s:%s", artifact.Labels, repository, reference)
+	}
 }
 
 func (t *transfer) convert(resource *model.Resource) *repository {
@@ -162,18 +293,25 @@ func (t *transfer) shouldStop() bool {
 	return isStopped
 }
 
-func (t *transfer) copy(src *repository, dst *repository, override bool, speed int32) error {
+func (t *transfer) copy(src *repository, dst *repository, override bool, opts *trans.Options) error {
 	srcRepo := src.repository
 	dstRepo := dst.repository
 	t.logger.Infof("copying %s:[%s](source registry) to %s:[%s](destination registry)...",
 		srcRepo, strings.Join(src.tags, ","), dstRepo, strings.Join(dst.tags, ","))
-	if speed > 0 {
-		t.logger.Infof("limit network speed at %d kb/s", speed)
+	if opts.Limited() {
+		t.logger.Infof("limit network speed at %s", opts)
+	}
+	if opts != nil && opts.CopyByChunk {
+		if _, _, ok := t.chunkedRegistries(); ok {
+			t.logger.Infof("copy the blobs by chunks of %d bytes", chunkSize)
+		} else {
+			t.logger.Warningf("the source or destination registry doesn't support copying blobs by chunks, copy the blobs as a whole")
+		}
 	}
 
 	var err error
 	for i := range src.tags {
-		if e := t.copyArtifact(srcRepo, src.tags[i], dstRepo, dst.tags[i], override, speed); e != nil {
+		if e := t.copyArtifact(srcRepo, src.tags[i], dstRepo, dst.tags[i], override, opts); e != nil {
 			if e == errStopped {
 				return nil
 			}
@@ -187,12 +325,12 @@ func (t *transfer) copy(src *repository, dst *repository, override bool, speed i
 		return err
 	}
 
-	t.logger.Infof("copy %s:[%s](source registry) to %s:[%s](destination registry) completed",
-		srcRepo, strings.Join(src.tags, ","), dstRepo, strings.Join(dst.tags, ","))
+	t.logger.Infof("copy %s:[%s](source registry) to %s:[%s](destination registry) completed, %d bytes of blobs copied",
+		srcRepo, strings.Join(src.tags, ","), dstRepo, strings.Join(dst.tags, ","), t.copied)
 	return nil
 }
 
-func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, override bool, speed int32) error {
+func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, override bool, opts *trans.Options) error {
 	t.logger.Infof("copying %s:%s(source registry) to %s:%s(destination registry)...",
 		srcRepo, srcRef, dstRepo, dstRef)
 	// pull the manifest from the source registry
@@ -226,7 +364,7 @@ func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, overrid
 
 	// copy contents between the source and destination registries
 	for _, content := range manifest.References() {
-		if err = t.copyContent(content, srcRepo, dstRepo, speed); err != nil {
+		if err = t.copyContent(content, srcRepo, dstRepo, opts); err != nil {
 			return err
 		}
 	}
@@ -242,7 +380,7 @@ func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, overrid
 }
 
 // copy the content from source registry to destination according to its media type
-func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo string, speed int32) error {
+func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo string, opts *trans.Options) error {
 	digest := content.Digest.String()
 	switch content.MediaType {
 	// when the media type of pulled manifest is index,
@@ -251,7 +389,7 @@ func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo
 		v1.MediaTypeImageManifest, schema2.MediaTypeManifest,
 		schema1.MediaTypeSignedManifest, schema1.MediaTypeManifest:
 		// as using digest as the reference, so set the override to true directly
-		return t.copyArtifact(srcRepo, digest, dstRepo, digest, true, speed)
+		return t.copyArtifact(srcRepo, digest, dstRepo, digest, true, opts)
 	// handle foreign layer
 	case schema2.MediaTypeForeignLayer:
 		t.logger.Infof("the layer %s is a foreign layer, skip", digest)
@@ -260,15 +398,17 @@ func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo
 	// the media type of the layer or config can be "application/octet-stream",
 	// schema1.MediaTypeManifestLayer, schema2.MediaTypeLayer, schema2.MediaTypeImageConfig
 	default:
-		return t.copyBlobWithRetry(srcRepo, dstRepo, digest, content.Size, speed)
+		return t.copyBlobWithRetry(srcRepo, dstRepo, digest, content.Size, opts)
 	}
 }
 
-func (t *transfer) copyBlobWithRetry(srcRepo, dstRepo, digest string, sizeFromDescriptor int64, speed int32) error {
+func (t *transfer) copyBlobWithRetry(srcRepo, dstRepo, digest string, sizeFromDescriptor int64, opts *trans.Options) error {
 	var err error
+	// shared by the retries to resume the copy by chunks
+	upload := &blobUpload{}
 	for i, backoff := 1, 2*time.Second; i <= retry; i, backoff = i+1, backoff*2 {
 		t.logger.Infof("copying the blob %s(the %dth running)...", digest, i)
-		if err = t.copyBlob(srcRepo, dstRepo, digest, sizeFromDescriptor, speed); err == nil {
+		if err = t.copyBlob(srcRepo, dstRepo, digest, sizeFromDescriptor, opts, upload); err == nil {
 			t.logger.Infof("copy the blob %s completed", digest)
 			return nil
 		}
@@ -283,7 +423,7 @@ func (t *transfer) copyBlobWithRetry(srcRepo, dstRepo, digest string, sizeFromDe
 
 // copy the layer or artifact config from the source registry to destination
 // the size parameter is taken from manifests.
-func (t *transfer) copyBlob(srcRepo, dstRepo, digest string, sizeFromDescriptor int64, speed int32) error {
+func (t *transfer) copyBlob(srcRepo, dstRepo, digest string, sizeFromDescriptor int64, opts *trans.Options, upload *blobUpload) error {
 	if t.shouldStop() {
 		return errStopped
 	}
@@ -297,6 +437,13 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string, sizeFromDescriptor
 		return nil
 	}
 
+	// the size from manifests is required to split the blob into chunks
+	if opts != nil && opts.CopyByChunk && sizeFromDescriptor > 0 {
+		if src, dst, ok := t.chunkedRegistries(); ok {
+			return t.copyBlobByChunk(src, dst, srcRepo, dstRepo, digest, sizeFromDescriptor, opts, upload)
+		}
+	}
+
 	mount, repository, err := t.dst.CanBeMount(digest)
 	if err != nil {
 		t.logger.Errorf("failed to check whether the blob %s can be mounted on the destination registry: %v", digest, err)
@@ -316,8 +463,8 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string, sizeFromDescriptor
 		t.logger.Errorf("failed to pulling the blob %s: %v", digest, err)
 		return err
 	}
-	if speed > 0 {
-		data = trans.NewReader(data, speed)
+	if opts.Limited() {
+		data = trans.NewScheduledReader(data, opts)
 	}
 	defer data.Close()
 	// get size 0 from PullBlob, use size from distribution.Descriptor instead.
@@ -332,9 +479,85 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string, sizeFromDescriptor
 		t.logger.Errorf("failed to pushing the blob %s, size %d: %v", digest, size, err)
 		return err
 	}
+	t.copied += size
 	return nil
 }
 
+// chunkedRegistries returns the source and destination registries if both of them support copying blobs by chunks
+func (t *transfer) chunkedRegistries() (adapter.ChunkedArtifactRegistry, adapter.ChunkedArtifactRegistry, bool) {
+	src, ok := t.src.(adapter.ChunkedArtifactRegistry)
+	if !ok {
+		return nil, nil, false
+	}
+	dst, ok := t.dst.(adapter.ChunkedArtifactRegistry)
+	if !ok {
+		return nil, nil, false
+	}
+	return src, dst, true
+}
+
+// copy the blob by chunks, the copy is resumed from the location and offset returned by the last
+// successful push of the previous running if they are recorded. The location carries the state of
+// the upload session which must match the committed bytes, so the registry rejects the resumed push
+// if the interrupted chunk committed some bytes, the copy is restarted from the beginning then
+func (t *transfer) copyBlobByChunk(src, dst adapter.ChunkedArtifactRegistry, srcRepo, dstRepo, digest string,
+	size int64, opts *trans.Options, upload *blobUpload) error {
+	resumed := len(upload.location) > 0
+	if resumed {
+		t.logger.Infof("resume copying the blob %s from the offset %d", digest, upload.offset)
+	}
+
+	for {
+		if t.shouldStop() {
+			return errStopped
+		}
+		start := upload.offset
+		end := start + chunkSize - 1
+		if end > size-1 {
+			end = size - 1
+		}
+		// the empty chunk completes the upload whose bytes are all committed
+		var data io.ReadCloser = ioutil.NopCloser(bytes.NewReader(nil))
+		if end >= start {
+			var err error
+			_, data, err = src.PullBlobChunk(srcRepo, digest, size, start, end)
+			if err != nil {
+				t.logger.Errorf("failed to pulling the chunk %d-%d of blob %s: %v", start, end, digest, err)
+				return err
+			}
+			if opts.Limited() {
+				data = trans.NewScheduledReader(data, opts)
+			}
+		}
+		location, endRange, err := dst.PushBlobChunk(dstRepo, digest, size, data, start, end, upload.location)
+		data.Close()
+		// record the upload session of the last successful push even the push fails to resume
+		// the copy in the next running
+		upload.location = location
+		if endRange >= 0 {
+			upload.offset = endRange + 1
+		}
+		if err != nil {
+			var e *errors.Error
+			// the registry rejects the state of the resumed upload session
+			if resumed && errors.As(err, &e) {
+				t.logger.Warningf("cannot resume the upload session of the blob %s, restart from the beginning: %v", digest, err)
+				resumed = false
+				upload.location, upload.offset = "", 0
+				continue
+			}
+			t.logger.Errorf("failed to pushing the chunk %d-%d of blob %s: %v", start, end, digest, err)
+			return err
+		}
+		resumed = false
+		t.copied += upload.offset - start
+		t.logger.Infof("%d/%d bytes of the blob %s copied", upload.offset, size, digest)
+		if end == size-1 {
+			return nil
+		}
+	}
+}
+
 func (t *transfer) pullManifest(repository, reference string) (
 	distribution.Manifest, string, error) {
 	if t.shouldStop() {
@@ -16,8 +16,15 @@ package image
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/docker/distribution"
@@ -25,6 +32,7 @@ import (
 	trans "github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/registry"
 	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,6 +111,138 @@ func (f *fakeRegistry) MountBlob(srcRepository, digest, dstRepository string) er
 	return nil
 }
 
+// fakeChunkedRegistry supports copying blobs by chunks, the chunks are pulled from the "blob"
+// and pushed by the registry client
+type fakeChunkedRegistry struct {
+	fakeRegistry
+	blob   []byte
+	client registry.Client
+}
+
+func (f *fakeChunkedRegistry) PullBlobChunk(repository, digest string, blobSize, start, end int64) (int64, io.ReadCloser, error) {
+	return end - start + 1, ioutil.NopCloser(bytes.NewReader(f.blob[start : end+1])), nil
+}
+func (f *fakeChunkedRegistry) PushBlobChunk(repository, digest string, blobSize int64, chunk io.Reader, start, end int64, location string) (string, int64, error) {
+	return f.client.PushBlobChunk(repository, digest, blobSize, chunk, start, end, location)
+}
+
+// uploadState is the state of the upload session carried by the "_state" parameter of the location
+type uploadState struct {
+	UUID   string `json:"uuid"`
+	Offset int    `json:"offset"`
+}
+
+// fakeUploadServer mocks the blob upload API of the distribution(v2.7.1): every non-POST request of
+// the upload session must carry the "_state" returned by the last response, the session is canceled
+// if the offset of the state doesn't match the committed bytes
+type fakeUploadServer struct {
+	*httptest.Server
+	sessions map[string][]byte
+	blobs    map[string][]byte
+	created  int
+	patches  int
+	// the PATCH request whose sequence number is "interruptAt" is interrupted after
+	// committing "interruptAfter" bytes, 0 means no interruption
+	interruptAt    int
+	interruptAfter int
+}
+
+func newFakeUploadServer() *fakeUploadServer {
+	f := &fakeUploadServer{
+		sessions: map[string][]byte{},
+		blobs:    map[string][]byte{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeUploadServer) location(w http.ResponseWriter, uuid string) {
+	state, _ := json.Marshal(&uploadState{UUID: uuid, Offset: len(f.sessions[uuid])})
+	w.Header().Set("Location", fmt.Sprintf("/v2/destination/blobs/uploads/%s?_state=%s", uuid,
+		base64.URLEncoding.EncodeToString(state)))
+	w.Header().Set("Range", fmt.Sprintf("0-%d", len(f.sessions[uuid])-1))
+}
+
+func (f *fakeUploadServer) serve(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/v2/destination/blobs/uploads/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method == http.MethodPost {
+		f.created++
+		uuid := fmt.Sprintf("uuid-%d", f.created)
+		f.sessions[uuid] = []byte{}
+		f.location(w, uuid)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, prefix)
+	data, err := base64.URLEncoding.DecodeString(r.URL.Query().Get("_state"))
+	state := &uploadState{}
+	if err != nil || len(data) == 0 || json.Unmarshal(data, state) != nil || state.UUID != uuid {
+		http.Error(w, "BLOB_UPLOAD_INVALID", http.StatusBadRequest)
+		return
+	}
+	uploaded, exist := f.sessions[uuid]
+	if !exist {
+		http.Error(w, "BLOB_UPLOAD_UNKNOWN", http.StatusNotFound)
+		return
+	}
+	if len(uploaded) != state.Offset {
+		delete(f.sessions, uuid)
+		http.Error(w, "BLOB_UPLOAD_INVALID: upload resumed at wrong offset", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Content-Range"), "%d-%d", &start, &end); err != nil || start != len(uploaded) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		f.patches++
+		if f.patches == f.interruptAt {
+			chunk := make([]byte, f.interruptAfter)
+			n, _ := io.ReadFull(r.Body, chunk)
+			f.sessions[uuid] = append(uploaded, chunk[:n]...)
+			// reset the connection
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		chunk, _ := ioutil.ReadAll(r.Body)
+		f.sessions[uuid] = append(uploaded, chunk...)
+		f.location(w, uuid)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		f.blobs[r.URL.Query().Get("digest")] = uploaded
+		delete(f.sessions, uuid)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// fakeLabelRegistry records the labels added to the artifacts
+type fakeLabelRegistry struct {
+	fakeRegistry
+	labels map[string][]string
+	failed map[string]bool
+}
+
+func (f *fakeLabelRegistry) AddLabels(repository, reference string, labels []*model.Label) error {
+	if f.failed[repository+":"+reference] {
+		return errors.New("failed to add labels")
+	}
+	for _, label := range labels {
+		f.labels[repository+":"+reference] = append(f.labels[repository+":"+reference], label.Name)
+	}
+	return nil
+}
+
 func TestFactory(t *testing.T) {
 	tr, err := factory(nil, nil)
 	require.Nil(t, err)
@@ -144,8 +284,187 @@ func TestCopy(t *testing.T) {
 		repository: "destination",
 		tags:       []string{"b1", "b2"},
 	}
-	err := tr.copy(src, dst, true, 0)
+	err := tr.copy(src, dst, true, nil)
+	require.Nil(t, err)
+}
+
+func TestCopyBlobByChunk(t *testing.T) {
+	chunkSize = 4
+	defer func() { chunkSize = 10 * 1024 * 1024 }()
+
+	data := []byte("0123456789")
+	server := newFakeUploadServer()
+	defer server.Close()
+	src := &fakeChunkedRegistry{blob: data}
+	dst := &fakeChunkedRegistry{client: registry.NewClient(server.URL, "", "", true)}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+		src:       src,
+		dst:       dst,
+	}
+	opts := &trans.Options{CopyByChunk: true}
+
+	// the blob is copied by chunks in one upload session
+	upload := &blobUpload{}
+	err := tr.copyBlob("source", "destination", "sha256:1", int64(len(data)), opts, upload)
+	require.Nil(t, err)
+	assert.Equal(t, data, server.blobs["sha256:1"])
+	assert.Equal(t, 1, server.created)
+	assert.Equal(t, int64(len(data)), tr.copied)
+
+	// the push of the second chunk is interrupted before any byte is committed
+	upload = &blobUpload{}
+	tr.copied = 0
+	server.interruptAt, server.interruptAfter = server.patches+2, 0
+	err = tr.copyBlob("source", "destination", "sha256:2", int64(len(data)), opts, upload)
+	require.NotNil(t, err)
+	// the location returned by the last successful push is kept
+	assert.Contains(t, upload.location, "uuid-2")
+	assert.Equal(t, int64(4), upload.offset)
+
+	// the upload session is resumed from the last successful push
+	err = tr.copyBlob("source", "destination", "sha256:2", int64(len(data)), opts, upload)
+	require.Nil(t, err)
+	assert.Equal(t, data, server.blobs["sha256:2"])
+	assert.Equal(t, 2, server.created)
+	assert.Equal(t, int64(len(data)), tr.copied)
+
+	// the push of the second chunk is interrupted after half of the chunk is committed
+	upload = &blobUpload{}
+	server.interruptAt, server.interruptAfter = server.patches+2, 2
+	err = tr.copyBlob("source", "destination", "sha256:3", int64(len(data)), opts, upload)
+	require.NotNil(t, err)
+	assert.Contains(t, upload.location, "uuid-3")
+	assert.Equal(t, int64(4), upload.offset)
+
+	// the state of the last successful push doesn't match the committed bytes, the registry
+	// cancels the upload session and the copy is restarted from the beginning
+	err = tr.copyBlob("source", "destination", "sha256:3", int64(len(data)), opts, upload)
+	require.Nil(t, err)
+	assert.Equal(t, data, server.blobs["sha256:3"])
+	assert.Equal(t, 4, server.created)
+	assert.Empty(t, server.sessions)
+}
+
+func TestSyncLabels(t *testing.T) {
+	registry := &fakeLabelRegistry{labels: map[string][]string{}}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+		dst:       registry,
+	}
+	tr.syncLabels(&model.Resource{
+		Metadata: &model.ResourceMetadata{
+			Repository: &model.Repository{
+				Name: "library/hello-world",
+			},
+			Artifacts: []*model.Artifact{
+				{
+					Digest: "sha256:1",
+					Tags:   []string{"v1"},
+					Labels: []string{"prod", "team-a"},
+					LabelDetails: []*model.Label{
+						{Name: "prod", Scope: "g"},
+						{Name: "team-a", Scope: "p"},
+					},
+				},
+				{
+					Digest: "sha256:2",
+					LabelDetails: []*model.Label{
+						{Name: "dev", Scope: "g"},
+					},
+				},
+				{
+					Digest: "sha256:3",
+					Tags:   []string{"v3"},
+				},
+			},
+		},
+	})
+	assert.Equal(t, map[string][]string{
+		"library/hello-world:v1":       {"prod", "team-a"},
+		"library/hello-world:sha256:2": {"dev"},
+	}, registry.labels)
+
+	// failing to add the labels to one artifact doesn't stop the others
+	registry = &fakeLabelRegistry{
+		labels: map[string][]string{},
+		failed: map[string]bool{"library/hello-world:v1": true},
+	}
+	tr.dst = registry
+	tr.syncLabels(&model.Resource{
+		Metadata: &model.ResourceMetadata{
+			Repository: &model.Repository{
+				Name: "library/hello-world",
+			},
+			Artifacts: []*model.Artifact{
+				{
+					Digest:       "sha256:1",
+					Tags:         []string{"v1"},
+					Labels:       []string{"prod"},
+					LabelDetails: []*model.Label{{Name: "prod", Scope: "g"}},
+				},
+				{
+					Digest:       "sha256:2",
+					Tags:         []string{"v2"},
+					Labels:       []string{"dev"},
+					LabelDetails: []*model.Label{{Name: "dev", Scope: "g"}},
+				},
+			},
+		},
+	})
+	assert.Equal(t, map[string][]string{
+		"library/hello-world:v2": {"dev"},
+	}, registry.labels)
+
+	// the destination registry doesn't support labels
+	tr.dst = &fakeRegistry{}
+	tr.syncLabels(&model.Resource{
+		Metadata: &model.ResourceMetadata{
+			Repository: &model.Repository{
+				Name: "library/hello-world",
+			},
+		},
+	})
+}
+
+type fakeMismatchedRegistry struct {
+	fakeRegistry
+}
+
+func (f *fakeMismatchedRegistry) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	return true, &distribution.Descriptor{Digest: digest.Digest("sha256:d6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7")}, nil
+}
+
+func TestVerify(t *testing.T) {
+	stopFunc := func() bool { return false }
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: stopFunc,
+		src:       &fakeRegistry{},
+		dst:       &fakeRegistry{},
+	}
+
+	src := &repository{
+		repository: "source",
+		tags:       []string{"a1", "a2"},
+	}
+	dst := &repository{
+		repository: "destination",
+		tags:       []string{"b1", "b2"},
+	}
+	result, err := tr.verify(src, dst)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"destination:b2"}, result.Missing)
+	assert.Len(t, result.Mismatched, 0)
+
+	// the digest on the destination registry is different
+	tr.dst = &fakeMismatchedRegistry{}
+	result, err = tr.verify(src, dst)
 	require.Nil(t, err)
+	assert.Len(t, result.Missing, 0)
+	assert.Equal(t, []string{"destination:b1", "destination:b2"}, result.Mismatched)
 }
 
 func TestDelete(t *testing.T) {
@@ -11,6 +11,9 @@ import (
 type reader struct {
 	reader  io.ReadCloser
 	limiter *rate.Limiter
+	opts    *Options
+	// the speed limit(kb/s) currently applied
+	speed int32
 }
 
 type RateOpts struct {
@@ -21,10 +24,23 @@ const KBRATE = 1024 / 8
 
 // NewReader returns a Reader that is rate limited
 func NewReader(r io.ReadCloser, kb int32) io.ReadCloser {
-	l := rate.NewLimiter(rate.Limit(kb*KBRATE), 1000*1024)
+	return NewScheduledReader(r, &Options{Speed: kb})
+}
+
+// NewScheduledReader returns a Reader that is rate limited by the speed of the options,
+// the speed is re-evaluated on every read so that the limit follows the bandwidth windows
+// during the long copies
+func NewScheduledReader(r io.ReadCloser, opts *Options) io.ReadCloser {
+	if opts != nil {
+		// parse the windows before reading rather than on the first read
+		for _, window := range opts.BandwidthWindows {
+			_ = window.parse()
+		}
+	}
 	return &reader{
 		reader:  r,
-		limiter: l,
+		limiter: rate.NewLimiter(rate.Inf, 1000*1024),
+		opts:    opts,
 	}
 }
 
@@ -34,6 +50,14 @@ func (r *reader) Read(buf []byte) (int, error) {
 		return n, err
 	}
 	now := time.Now()
+	if speed := r.opts.SpeedAt(now); speed != r.speed {
+		r.speed = speed
+		if speed > 0 {
+			r.limiter.SetLimitAt(now, rate.Limit(speed*KBRATE))
+		} else {
+			r.limiter.SetLimitAt(now, rate.Inf)
+		}
+	}
 	rv := r.limiter.ReserveN(now, n)
 	if !rv.OK() {
 		return 0, fmt.Errorf("exceeds limiter's burst")
@@ -34,7 +34,20 @@ type Factory func(Logger, StopFunc) (Transfer, error)
 // Transfer defines an interface used to transfer the source
 // resource to the destination
 type Transfer interface {
-	Transfer(src *model.Resource, dst *model.Resource, speed int32) error
+	Transfer(src *model.Resource, dst *model.Resource, opts *Options) error
+}
+
+// VerifyResult contains the artifacts that are missing on the destination
+// registry or whose digests are different with the ones on the source registry
+type VerifyResult struct {
+	Missing    []string `json:"missing"`
+	Mismatched []string `json:"mismatched"`
+}
+
+// Verifier defines an interface used to compare the source resource with the
+// destination one without copying anything. A Transfer can implement it optionally
+type Verifier interface {
+	Verify(src *model.Resource, dst *model.Resource) (*VerifyResult, error)
 }
 
 // Logger defines an interface for logging
@@ -19,9 +19,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification"
 	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/repository"
 	"github.com/goharbor/harbor/src/pkg/retention"
@@ -64,6 +68,10 @@ type Controller interface {
 	GetRetentionExecTaskLog(ctx context.Context, taskID int64) ([]byte, error)
 
 	GetRetentionExecTask(ctx context.Context, taskID int64) (*retention.Task, error)
+
+	ListRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) ([]*retention.DryRunCandidate, error)
+
+	GetTotalOfRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) (int64, error)
 }
 
 var (
@@ -119,6 +127,7 @@ func (r *defaultController) CreateRetention(ctx context.Context, p *policy.Metad
 		}
 	}
 
+	fireEvent(ctx, event.TopicCreatePolicy, id, p.Scope)
 	return id, nil
 }
 
@@ -179,6 +188,7 @@ func (r *defaultController) UpdateRetention(ctx context.Context, p *policy.Metad
 		}
 	}
 
+	fireEvent(ctx, event.TopicUpdatePolicy, p.ID, p.Scope)
 	return nil
 }
 
@@ -199,7 +209,28 @@ func (r *defaultController) DeleteRetention(ctx context.Context, id int64) error
 	if err != nil {
 		return err
 	}
-	return r.manager.DeletePolicy(ctx, id)
+	if err = r.manager.DeletePolicy(ctx, id); err != nil {
+		return err
+	}
+	fireEvent(ctx, event.TopicDeletePolicy, id, p.Scope)
+	return nil
+}
+
+// fireEvent adds the retention policy event into the context, the retention policy has no name
+// as a project has only one, so the name is generated from the ID
+func fireEvent(ctx context.Context, topic string, id int64, scope *policy.Scope) {
+	var projectID int64
+	if scope != nil && scope.Level == policy.ScopeLevelProject {
+		projectID = scope.Reference
+	}
+	notification.AddEvent(ctx, &metadata.PolicyMetaData{
+		Topic:      topic,
+		PolicyType: event.PolicyTypeRetention,
+		PolicyID:   id,
+		PolicyName: fmt.Sprintf("retention-policy-%d", id),
+		ProjectID:  projectID,
+		Operator:   operator.FromContext(ctx),
+	})
 }
 
 // deleteExecs delete executions
@@ -374,6 +405,16 @@ func (r *defaultController) GetRetentionExecTask(ctx context.Context, taskID int
 	return convertTask(t), nil
 }
 
+// ListRetentionExecCandidates List the candidates evaluated by the dry run Retention Execution
+func (r *defaultController) ListRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) ([]*retention.DryRunCandidate, error) {
+	return r.manager.ListDryRunCandidates(ctx, executionID, query)
+}
+
+// GetTotalOfRetentionExecCandidates Count the candidates evaluated by the dry run Retention Execution
+func (r *defaultController) GetTotalOfRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) (int64, error) {
+	return r.manager.CountDryRunCandidates(ctx, executionID, query)
+}
+
 // UpdateTaskInfo Update task info
 func (r *defaultController) UpdateTaskInfo(ctx context.Context, taskID int64, total int, retained int) error {
 	t, err := r.taskMgr.Get(ctx, taskID)
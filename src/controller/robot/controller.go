@@ -5,10 +5,14 @@ import (
 	"fmt"
 	rbac_project "github.com/goharbor/harbor/src/common/rbac/project"
 	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/lib/config"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification"
 	"github.com/goharbor/harbor/src/pkg/permission/types"
 	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/rbac"
@@ -117,6 +121,18 @@ func (d *controller) Create(ctx context.Context, r *Robot) (int64, string, error
 	if err := d.createPermission(ctx, r); err != nil {
 		return 0, "", err
 	}
+	// the invisible robots are created by the system internally, e.g. for the scan jobs
+	if r.Visible {
+		notification.AddEvent(ctx, &metadata.RobotMetaData{
+			Topic:     event.TopicCreateRobot,
+			RobotID:   robotID,
+			Name:      fmt.Sprintf("%s%s", config.RobotPrefix(ctx), name),
+			Level:     r.Level,
+			ProjectID: r.ProjectID,
+			ExpiresAt: expiresAt,
+			Operator:  operator.FromContext(ctx),
+		})
+	}
 	return robotID, pwd, nil
 }
 
@@ -0,0 +1,121 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/q"
+	n_event "github.com/goharbor/harbor/src/pkg/notifier/event"
+	robot "github.com/goharbor/harbor/src/pkg/robot"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+)
+
+const (
+	// ExpirationCheckCallback is the name of the scheduler callback which notifies the expired robots
+	ExpirationCheckCallback = "ROBOT_EXPIRATION_CHECK"
+	// ExpirationCheckVendorType is the vendor type of the expiration check schedule
+	ExpirationCheckVendorType = "ROBOT_EXPIRATION_CHECK"
+
+	expirationCheckCron     = "0 0 * * * *"
+	expirationCheckPageSize = 100
+)
+
+var (
+	robotMgr     = robot.Mgr
+	schedulerMgr = scheduler.Sched
+	// publishes the event directly as the scheduler callback isn't in the scope of the API request
+	publishEvent = n_event.BuildAndPublish
+)
+
+func init() {
+	if err := scheduler.RegisterCallbackFunc(ExpirationCheckCallback, expirationCheckCallback); err != nil {
+		log.Fatalf("failed to register the callback for the robot expiration check, error %v", err)
+	}
+}
+
+// ScheduleExpirationCheck creates the hourly schedule to notify the expired robots if it doesn't exist
+func ScheduleExpirationCheck(ctx context.Context) error {
+	schedules, err := schedulerMgr.ListSchedules(ctx, q.New(q.KeyWords{"VendorType": ExpirationCheckVendorType}))
+	if err != nil {
+		return err
+	}
+	if len(schedules) > 0 {
+		return nil
+	}
+	_, err = schedulerMgr.Schedule(ctx, ExpirationCheckVendorType, -1, "Hourly", expirationCheckCron, ExpirationCheckCallback, nil, nil)
+	// the schedule is created by another core instance
+	if errors.IsConflictErr(err) {
+		return nil
+	}
+	return err
+}
+
+func expirationCheckCallback(ctx context.Context, param string) error {
+	return notifyExpiredRobots(ctx, time.Now().Unix())
+}
+
+// notifyExpiredRobots fires the robot expired event for the visible robots which expired before the specified
+// time, the notified expiration is recorded in database so that each expiration is notified only once even
+// the check runs in several core instances
+func notifyExpiredRobots(ctx context.Context, now int64) error {
+	query := q.New(q.KeyWords{
+		"Visible":   true,
+		"ExpiresAt": &q.Range{Min: 1, Max: now},
+	})
+	query.Sorts = []*q.Sort{q.NewSort("ID", false)}
+	query.PageSize = expirationCheckPageSize
+	for query.PageNumber = 1; ; query.PageNumber++ {
+		robots, err := robotMgr.List(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, r := range robots {
+			if r.NotifiedExpiresAt == r.ExpiresAt {
+				continue
+			}
+			marked, err := robotMgr.MarkExpirationNotified(ctx, r.ID, r.ExpiresAt)
+			if err != nil {
+				log.Errorf("failed to mark the expiration of robot %d as notified: %v", r.ID, err)
+				continue
+			}
+			if !marked {
+				continue
+			}
+			level := LEVELPROJECT
+			if r.ProjectID == 0 {
+				level = LEVELSYSTEM
+			}
+			publishEvent(&metadata.RobotMetaData{
+				Topic:     event.TopicRobotExpired,
+				RobotID:   r.ID,
+				Name:      fmt.Sprintf("%s%s", config.RobotPrefix(ctx), r.Name),
+				Level:     level,
+				ProjectID: r.ProjectID,
+				ExpiresAt: r.ExpiresAt,
+			})
+		}
+		if len(robots) < expirationCheckPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	n_event "github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/goharbor/harbor/src/pkg/robot/model"
+	pkgscheduler "github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/testing/pkg/robot"
+	"github.com/goharbor/harbor/src/testing/pkg/scheduler"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type expirationTestSuite struct {
+	suite.Suite
+	robotMgr     *robot.Manager
+	schedulerMgr *scheduler.Scheduler
+	published    []*metadata.RobotMetaData
+}
+
+func (e *expirationTestSuite) SetupSuite() {
+	config.InitWithSettings(map[string]interface{}{
+		common.RobotNamePrefix: "robot$",
+	})
+}
+
+func (e *expirationTestSuite) SetupTest() {
+	e.robotMgr = &robot.Manager{}
+	e.schedulerMgr = &scheduler.Scheduler{}
+	e.published = nil
+	robotMgr = e.robotMgr
+	schedulerMgr = e.schedulerMgr
+	publishEvent = func(data ...n_event.Metadata) {
+		for _, m := range data {
+			e.published = append(e.published, m.(*metadata.RobotMetaData))
+		}
+	}
+}
+
+func (e *expirationTestSuite) TestScheduleExpirationCheck() {
+	// the schedule exists
+	e.schedulerMgr.On("ListSchedules", mock.Anything, mock.Anything).Return([]*pkgscheduler.Schedule{{ID: 1}}, nil).Once()
+	e.Nil(ScheduleExpirationCheck(context.TODO()))
+
+	// the schedule is created by another instance at the same time
+	e.schedulerMgr.On("ListSchedules", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	e.schedulerMgr.On("Schedule", mock.Anything, ExpirationCheckVendorType, int64(-1), "Hourly", expirationCheckCron,
+		ExpirationCheckCallback, nil, mock.Anything).Return(int64(0), errors.ConflictError(nil)).Once()
+	e.Nil(ScheduleExpirationCheck(context.TODO()))
+
+	e.schedulerMgr.On("ListSchedules", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	e.schedulerMgr.On("Schedule", mock.Anything, ExpirationCheckVendorType, int64(-1), "Hourly", expirationCheckCron,
+		ExpirationCheckCallback, nil, mock.Anything).Return(int64(1), nil).Once()
+	e.Nil(ScheduleExpirationCheck(context.TODO()))
+	e.schedulerMgr.AssertExpectations(e.T())
+}
+
+func (e *expirationTestSuite) TestNotifyExpiredRobots() {
+	e.robotMgr.On("List", mock.Anything, mock.Anything).Return([]*model.Robot{
+		// notified already
+		{ID: 1, Name: "notified", ExpiresAt: 100, NotifiedExpiresAt: 100},
+		// notified by another instance
+		{ID: 2, Name: "other", ExpiresAt: 100},
+		{ID: 3, Name: "library+expired", ProjectID: 1, ExpiresAt: 100},
+		// the system robot is refreshed and expires again
+		{ID: 4, Name: "refreshed", ExpiresAt: 200, NotifiedExpiresAt: 100},
+	}, nil).Once()
+	e.robotMgr.On("MarkExpirationNotified", mock.Anything, int64(2), int64(100)).Return(false, nil).Once()
+	e.robotMgr.On("MarkExpirationNotified", mock.Anything, int64(3), int64(100)).Return(true, nil).Once()
+	e.robotMgr.On("MarkExpirationNotified", mock.Anything, int64(4), int64(200)).Return(true, nil).Once()
+
+	e.Nil(notifyExpiredRobots(context.TODO(), 300))
+	e.robotMgr.AssertExpectations(e.T())
+	e.Require().Len(e.published, 2)
+	e.Equal(event.TopicRobotExpired, e.published[0].Topic)
+	e.Equal("robot$library+expired", e.published[0].Name)
+	e.Equal(LEVELPROJECT, e.published[0].Level)
+	e.Equal(int64(1), e.published[0].ProjectID)
+	e.Equal("robot$refreshed", e.published[1].Name)
+	e.Equal(LEVELSYSTEM, e.published[1].Level)
+	e.Equal(int64(200), e.published[1].ExpiresAt)
+}
+
+func (e *expirationTestSuite) TestNotifyExpiredRobotsInPages() {
+	robots := make([]*model.Robot, expirationCheckPageSize)
+	for i := range robots {
+		robots[i] = &model.Robot{ID: int64(i + 1), ExpiresAt: 100, NotifiedExpiresAt: 100}
+	}
+	e.robotMgr.On("List", mock.Anything, mock.MatchedBy(func(query *q.Query) bool {
+		return query.PageNumber == 1
+	})).Return(robots, nil).Once()
+	e.robotMgr.On("List", mock.Anything, mock.MatchedBy(func(query *q.Query) bool {
+		return query.PageNumber == 2
+	})).Return([]*model.Robot{}, nil).Once()
+
+	e.Nil(notifyExpiredRobots(context.TODO(), 300))
+	e.robotMgr.AssertExpectations(e.T())
+	e.Len(e.published, 0)
+}
+
+func TestExpirationTestSuite(t *testing.T) {
+	robotManager, scheduler, publish := robotMgr, schedulerMgr, publishEvent
+	defer func() {
+		robotMgr, schedulerMgr, publishEvent = robotManager, scheduler, publish
+	}()
+	suite.Run(t, &expirationTestSuite{})
+}
@@ -36,7 +36,9 @@ import (
 	configCtl "github.com/goharbor/harbor/src/controller/config"
 	_ "github.com/goharbor/harbor/src/controller/event/handler"
 	"github.com/goharbor/harbor/src/controller/health"
+	_ "github.com/goharbor/harbor/src/controller/proxy/warmup"
 	"github.com/goharbor/harbor/src/controller/registry"
+	"github.com/goharbor/harbor/src/controller/robot"
 	"github.com/goharbor/harbor/src/core/api"
 	_ "github.com/goharbor/harbor/src/core/auth/authproxy"
 	_ "github.com/goharbor/harbor/src/core/auth/db"
@@ -229,6 +231,8 @@ func main() {
 	go gracefulShutdown(closing, done, shutdownTracerProvider)
 	// Start health checker for registries
 	go registry.Ctl.StartRegularHealthCheck(orm.Context(), closing, done)
+	// the jobservice may not be ready when the core starts, so schedule it in background
+	go scheduleRobotExpirationCheck()
 
 	log.Info("initializing notification...")
 	notification.Init()
@@ -256,6 +260,22 @@ func main() {
 	beego.RunWithMiddleWares("", middlewares.MiddleWares()...)
 }
 
+func scheduleRobotExpirationCheck() {
+	for i := 1; ; i++ {
+		// the schedule record is rolled back if the job fails to be submitted
+		err := orm.WithTransaction(robot.ScheduleExpirationCheck)(orm.Context())
+		if err == nil {
+			return
+		}
+		if i >= 10 {
+			log.Errorf("failed to schedule the robot expiration check: %v", err)
+			return
+		}
+		log.Warningf("failed to schedule the robot expiration check, retry later: %v", err)
+		time.Sleep(30 * time.Second)
+	}
+}
+
 const (
 	trivyScanner = "Trivy"
 )
@@ -88,7 +88,21 @@ func (h *Handler) Prepare() {
 
 // HandleNotificationJob handles the hook of notification job
 func (h *Handler) HandleNotificationJob() {
+	// the job checks in the result of each delivery attempt
+	if len(h.checkIn) > 0 {
+		h.handleNotificationJobAttempt()
+		return
+	}
 	log.Debugf("received notification job status update event: job-%d, status-%s", h.id, h.status)
+	// the failed job is re-enqueued with a delay if the retry policy of the notification policy allows
+	if h.rawStatus == job.JobServiceStatusError && h.change.Metadata != nil {
+		retried, err := notification.HookManager.RetryHook(orm.Context(), h.id, h.change.Metadata.JobName, h.change.Metadata.Parameters)
+		if err != nil {
+			log.Errorf("failed to retry the notification job %d: %v", h.id, err)
+		} else if retried {
+			return
+		}
+	}
 	if err := notification.JobMgr.Update(orm.Context(), &model.Job{
 		ID:         h.id,
 		Status:     h.status,
@@ -99,3 +113,19 @@ func (h *Handler) HandleNotificationJob() {
 		return
 	}
 }
+
+// handleNotificationJobAttempt records the delivery attempt checked in by the notification job
+func (h *Handler) handleNotificationJobAttempt() {
+	attempt := &model.Attempt{}
+	if err := json.Unmarshal([]byte(h.checkIn), attempt); err != nil {
+		// Avoid job service from resending...
+		log.Errorf("failed to decode the delivery attempt of notification job %d: %v", h.id, err)
+		return
+	}
+	attempt.JobID = h.id
+	if _, err := notification.JobMgr.CreateAttempt(orm.Context(), attempt); err != nil {
+		log.Errorf("failed to record the delivery attempt of notification job %d: %v", h.id, err)
+		h.SendInternalServerError(err)
+		return
+	}
+}
@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils/email"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+const (
+	// the timeout(in seconds) to connect to the email server
+	emailTimeout = 60
+)
+
+// sendEmail is declared as variable for testing
+var sendEmail = email.Send
+
+// EmailJob implements the job interface, which send notification by email.
+type EmailJob struct {
+	logger logger.Interface
+	ctx    job.Context
+}
+
+// MaxFails returns that how many times this job can fail.
+func (ej *EmailJob) MaxFails() (result uint) {
+	// Default max fails count is 10, and its max retry interval is around 3h
+	// Large enough to ensure most situations can notify successfully
+	result = 10
+	if maxFails, exist := os.LookupEnv(maxFails); exist {
+		mf, err := strconv.ParseUint(maxFails, 10, 32)
+		if err != nil {
+			logger.Warningf("Fetch email job maxFails error: %s", err.Error())
+			return result
+		}
+		result = uint(mf)
+	}
+	return result
+}
+
+// MaxCurrency is implementation of same method in Interface.
+func (ej *EmailJob) MaxCurrency() uint {
+	return 1
+}
+
+// ShouldRetry ...
+func (ej *EmailJob) ShouldRetry() bool {
+	return true
+}
+
+// Validate implements the interface in job/Interface
+func (ej *EmailJob) Validate(params job.Parameters) error {
+	if params == nil {
+		// Params are required
+		return errors.New("missing parameter of email job")
+	}
+
+	for _, key := range []string{"subject", "payload", "address"} {
+		value, ok := params[key]
+		if !ok {
+			return errors.Errorf("missing job parameter '%s'", key)
+		}
+		if _, ok = value.(string); !ok {
+			return errors.Errorf("malformed job parameter '%s', expecting string but got %s", key, reflect.TypeOf(value).String())
+		}
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (ej *EmailJob) Run(ctx job.Context, params job.Parameters) error {
+	ej.logger = ctx.GetLogger()
+	ej.ctx = ctx
+
+	err := ej.execute(params)
+	if err != nil {
+		ej.logger.Error(err)
+	}
+	return err
+}
+
+// execute email job
+func (ej *EmailJob) execute(params map[string]interface{}) error {
+	subject := params["subject"].(string)
+	payload := params["payload"].(string)
+	address := params["address"].(string)
+
+	// the address may contain display names, e.g. "Admin <admin@example.com>", only the
+	// bare addresses are passed to the SMTP server
+	list, err := mail.ParseAddressList(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid recipients of email job: %s", address)
+	}
+	var recipients []string
+	for _, addr := range list {
+		recipients = append(recipients, addr.Address)
+	}
+	if len(recipients) == 0 {
+		return errors.Errorf("no recipient is specified for email job")
+	}
+
+	host := ej.getString(common.EmailHost)
+	if len(host) == 0 {
+		return errors.New("the email server is not configured")
+	}
+	addr := fmt.Sprintf("%s:%d", host, ej.getInt(common.EmailPort))
+	if err := sendEmail(addr, ej.getString(common.EmailIdentity), ej.getString(common.EmailUsername),
+		ej.getString(common.EmailPassword), emailTimeout, ej.getBool(common.EmailSSL), ej.getBool(common.EmailInsecure),
+		ej.getString(common.EmailFrom), recipients, subject, payload); err != nil {
+		return errors.Wrapf(err, "email job(recipients: %s) failed to send email via %s", address, addr)
+	}
+	return nil
+}
+
+// the email server settings are read from the configurations loaded into the job context
+func (ej *EmailJob) getString(key string) string {
+	if v, ok := ej.ctx.Get(key); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func (ej *EmailJob) getInt(key string) int {
+	v, ok := ej.ctx.Get(key)
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+func (ej *EmailJob) getBool(key string) bool {
+	if v, ok := ej.ctx.Get(key); ok {
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailJobMaxFails(t *testing.T) {
+	defer os.Unsetenv(maxFails)
+	ej := &EmailJob{}
+	// test default max fails
+	_ = os.Unsetenv(maxFails)
+	assert.Equal(t, uint(10), ej.MaxFails())
+
+	// test user defined max fails
+	_ = os.Setenv(maxFails, "15")
+	assert.Equal(t, uint(15), ej.MaxFails())
+
+	// test user defined wrong max fails
+	_ = os.Setenv(maxFails, "abc")
+	assert.Equal(t, uint(10), ej.MaxFails())
+}
+
+func TestEmailJobShouldRetry(t *testing.T) {
+	ej := &EmailJob{}
+	assert.True(t, ej.ShouldRetry())
+}
+
+func TestEmailJobValidate(t *testing.T) {
+	ej := &EmailJob{}
+	assert.NotNil(t, ej.Validate(nil))
+
+	assert.NotNil(t, ej.Validate(job.Parameters{
+		"address": "admin@example.com",
+		"payload": "email payload",
+	}))
+
+	assert.NotNil(t, ej.Validate(job.Parameters{
+		"subject": "email subject",
+		"address": 1,
+		"payload": "email payload",
+	}))
+
+	assert.Nil(t, ej.Validate(job.Parameters{
+		"subject": "email subject",
+		"address": "admin@example.com",
+		"payload": "email payload",
+	}))
+}
+
+func TestEmailJobRun(t *testing.T) {
+	send := sendEmail
+	defer func() {
+		sendEmail = send
+	}()
+
+	var (
+		sentAddr string
+		sentTo   []string
+		sentSubj string
+		sentMsg  string
+	)
+	sendEmail = func(addr, identity, username, password string, timeout int, tls, insecure bool,
+		from string, to []string, subject, message string) error {
+		sentAddr, sentTo, sentSubj, sentMsg = addr, to, subject, message
+		return nil
+	}
+
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("Get", common.EmailHost).Return("smtp.example.com", true)
+	ctx.On("Get", common.EmailPort).Return(25, true)
+	ctx.On("Get", mock.Anything).Return(nil, false)
+
+	ej := &EmailJob{}
+	params := map[string]interface{}{
+		"subject": "[Harbor] PUSH_ARTIFACT",
+		"payload": "<html></html>",
+		"address": "admin@example.com, dev@example.com",
+	}
+	assert.Nil(t, ej.Run(ctx, params))
+	assert.Equal(t, "smtp.example.com:25", sentAddr)
+	assert.Equal(t, []string{"admin@example.com", "dev@example.com"}, sentTo)
+	assert.Equal(t, "[Harbor] PUSH_ARTIFACT", sentSubj)
+	assert.Equal(t, "<html></html>", sentMsg)
+
+	// the display names are stripped from the recipients
+	params["address"] = "Admin <admin@example.com>, \"Dev Team\" <dev@example.com>"
+	assert.Nil(t, ej.Run(ctx, params))
+	assert.Equal(t, []string{"admin@example.com", "dev@example.com"}, sentTo)
+
+	// failed to send
+	sendEmail = func(addr, identity, username, password string, timeout int, tls, insecure bool,
+		from string, to []string, subject, message string) error {
+		return errors.New("failed to connect")
+	}
+	assert.NotNil(t, ej.Run(ctx, params))
+
+	// no recipients
+	params["address"] = " , "
+	assert.NotNil(t, ej.Run(ctx, params))
+}
+
+func TestEmailJobRunWithoutServer(t *testing.T) {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("Get", mock.Anything).Return(nil, false)
+
+	ej := &EmailJob{}
+	assert.NotNil(t, ej.Run(ctx, map[string]interface{}{
+		"subject": "[Harbor] PUSH_ARTIFACT",
+		"payload": "<html></html>",
+		"address": "admin@example.com",
+	}))
+}
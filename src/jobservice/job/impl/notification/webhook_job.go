@@ -2,23 +2,49 @@ package notification
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
+	notifier_model "github.com/goharbor/harbor/src/pkg/notifier/model"
 )
 
 // Max retry has the same meaning as max fails.
 const maxFails = "JOBSERVICE_WEBHOOK_JOB_MAX_RETRY"
 
+// the max length of the response body recorded for each delivery attempt
+const maxResponseSnippet = 1024
+
+// the manager to load the signing secret from the policy, declared as variable for testing
+var policyMgr = policy.Mgr
+
+// attempt is the result of a delivery attempt which is checked in to core
+type attempt struct {
+	StatusCode int    `json:"status_code"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 // WebhookJob implements the job interface, which send notification by http or https.
 type WebhookJob struct {
 	client *http.Client
 	logger logger.Interface
 	ctx    job.Context
+	// signingSecret signs the request body if it is set
+	signingSecret string
+	// retryByCore is set when the notification policy has the retry policy, the core
+	// re-enqueues the failed job with a delay then, the jobservice mustn't retry it again
+	retryByCore bool
 }
 
 // MaxFails returns that how many times this job can fail, get this value from ctx.
@@ -44,7 +70,7 @@ func (wj *WebhookJob) MaxCurrency() uint {
 
 // ShouldRetry ...
 func (wj *WebhookJob) ShouldRetry() bool {
-	return true
+	return !wj.retryByCore
 }
 
 // Validate implements the interface in job/Interface
@@ -58,14 +84,44 @@ func (wj *WebhookJob) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 
-	if err := wj.execute(ctx, params); err != nil {
+	retryPolicy, err := parseRetryPolicy(params)
+	if err != nil {
 		wj.logger.Error(err)
 		return err
 	}
+	wj.retryByCore = retryPolicy != nil
 
+	if err := wj.execute(ctx, params); err != nil {
+		wj.logger.Errorf("delivery attempt %v failed: %v", attemptOf(params), err)
+		return err
+	}
 	return nil
 }
 
+// attemptOf returns the attempt count carried by the re-enqueued job, the first job doesn't carry it
+func attemptOf(params map[string]interface{}) int64 {
+	if n, ok := toInt64(params["attempt"]); ok {
+		return n
+	}
+	return 1
+}
+
+// parseRetryPolicy returns nil if the retry policy isn't specified
+func parseRetryPolicy(params map[string]interface{}) (*policy_model.RetryPolicy, error) {
+	v, ok := params["retry_policy"].(string)
+	if !ok || len(v) == 0 {
+		return nil, nil
+	}
+	retryPolicy := &policy_model.RetryPolicy{}
+	if err := json.Unmarshal([]byte(v), retryPolicy); err != nil {
+		return nil, fmt.Errorf("invalid retry policy %s: %v", v, err)
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retry policy %s: %v", v, err)
+	}
+	return retryPolicy, nil
+}
+
 // init webhook job
 func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error {
 	wj.logger = ctx.GetLogger()
@@ -79,31 +135,118 @@ func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error
 			wj.client = httpHelper.clients[insecure]
 		}
 	}
+
+	secret, err := loadSigningSecret(ctx, params)
+	if err != nil {
+		return err
+	}
+	wj.signingSecret = secret
 	return nil
 }
 
-// execute webhook job
+// loadSigningSecret loads the signing secret of the target from the policy, returns empty
+// string if the request isn't required to be signed
+func loadSigningSecret(ctx job.Context, params map[string]interface{}) (string, error) {
+	policyID, ok := toInt64(params["policy_id"])
+	if !ok {
+		return "", nil
+	}
+	index, ok := toInt64(params["target_index"])
+	if !ok {
+		return "", fmt.Errorf("invalid target index %v of policy %d", params["target_index"], policyID)
+	}
+	ply, err := policyMgr.Get(ctx.SystemContext(), policyID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the notification policy %d: %v", policyID, err)
+	}
+	if index >= 0 && index < int64(len(ply.Targets)) && ply.Targets[index].Address == params["address"] {
+		return ply.Targets[index].SigningSecret, nil
+	}
+	// the index is stale if the targets are changed after the event is triggered or the job
+	// is re-delivered to a single target, look up the target by the address instead
+	for _, target := range ply.Targets {
+		if target.Type == notifier_model.NotifyTypeHTTP && target.Address == params["address"] {
+			return target.SigningSecret, nil
+		}
+	}
+	return "", fmt.Errorf("the target %v doesn't exist in policy %d", params["address"], policyID)
+}
+
+// toInt64 converts the numeric parameter, which becomes float64 after the JSON round trip
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// execute webhook job and check in the result of the delivery attempt
 func (wj *WebhookJob) execute(ctx job.Context, params map[string]interface{}) error {
+	statusCode, response, err := wj.deliver(params)
+	a := &attempt{
+		StatusCode: statusCode,
+		Response:   response,
+	}
+	if err != nil {
+		a.Error = err.Error()
+	}
+	if data, e := json.Marshal(a); e == nil {
+		if e = ctx.Checkin(string(data)); e != nil {
+			wj.logger.Warningf("failed to check in the delivery attempt: %v", e)
+		}
+	}
+	return err
+}
+
+// deliver sends the request, returns the status code and the snippet of the response body
+func (wj *WebhookJob) deliver(params map[string]interface{}) (int, string, error) {
 	payload := params["payload"].(string)
 	address := params["address"].(string)
 
 	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader([]byte(payload)))
 	if err != nil {
-		return err
+		return 0, "", err
 	}
 	if v, ok := params["auth_header"]; ok && len(v.(string)) > 0 {
 		req.Header.Set("Authorization", v.(string))
 	}
-	req.Header.Set("Content-Type", "application/json")
+	contentType := "application/json"
+	if v, ok := params["content_type"].(string); ok && len(v) > 0 {
+		contentType = v
+	}
+	req.Header.Set("Content-Type", contentType)
+	if len(wj.signingSecret) > 0 {
+		req.Header.Set(signature.Header, signature.Sign(wj.signingSecret, []byte(payload), time.Now()))
+	}
+	// the extra headers, e.g. the attributes of the CloudEvents in binary mode
+	switch headers := params["headers"].(type) {
+	case map[string]interface{}:
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				req.Header.Set(k, s)
+			}
+		}
+	case map[string]string:
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
 
 	resp, err := wj.client.Do(req)
 	if err != nil {
-		return err
+		return 0, "", err
 	}
 	defer resp.Body.Close()
+	snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook job(target: %s) response code is %d", address, resp.StatusCode)
+		return resp.StatusCode, string(snippet), fmt.Errorf("webhook job(target: %s) response code is %d", address, resp.StatusCode)
 	}
 
-	return nil
+	return resp.StatusCode, string(snippet), nil
 }
@@ -1,13 +1,20 @@
 package notification
 
 import (
-	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
-	"github.com/stretchr/testify/assert"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
+
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
+	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
+	notification "github.com/goharbor/harbor/src/testing/pkg/notification/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestMaxFails(t *testing.T) {
@@ -39,6 +46,7 @@ func TestRun(t *testing.T) {
 	logger := &mockjobservice.MockJobLogger{}
 
 	ctx.On("GetLogger").Return(logger)
+	ctx.On("Checkin", mock.Anything).Return(nil)
 
 	rep := &WebhookJob{}
 
@@ -78,3 +86,134 @@ func TestRun(t *testing.T) {
 	// test incorrect webhook response
 	assert.NotNil(t, rep.Run(ctx, paramsWrong))
 }
+
+func TestWebhookJobRunWithHeaders(t *testing.T) {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("Checkin", mock.Anything).Return(nil)
+
+	rep := &WebhookJob{}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/cloudevents+json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "harbor.artifact.pushed", r.Header.Get("ce-type"))
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"skip_cert_verify": true,
+		"payload":          `{"key": "value"}`,
+		"address":          ts.URL,
+		"content_type":     "application/cloudevents+json",
+		"headers": map[string]interface{}{
+			"ce-type": "harbor.artifact.pushed",
+		},
+	}
+	assert.Nil(t, rep.Run(ctx, params))
+}
+
+func TestWebhookJobRunWithSignature(t *testing.T) {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("Checkin", mock.Anything).Return(nil)
+	ctx.On("SystemContext").Return(context.TODO())
+
+	mgr := policyMgr
+	defer func() {
+		policyMgr = mgr
+	}()
+	fakeMgr := &notification.Manager{}
+	policyMgr = fakeMgr
+
+	rep := &WebhookJob{}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if err := signature.Verify("secret", r.Header.Get(signature.Header), body, time.Minute); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"skip_cert_verify": true,
+		"payload":          `{"key": "value"}`,
+		"address":          ts.URL,
+		// the numbers become float64 after the parameters are stored by the jobservice
+		"policy_id":    float64(1),
+		"target_index": float64(0),
+	}
+	fakeMgr.On("Get", mock.Anything, int64(1)).Return(&policy_model.Policy{
+		Targets: []policy_model.EventTarget{{Address: ts.URL, SigningSecret: "secret"}},
+	}, nil).Once()
+	assert.Nil(t, rep.Run(ctx, params))
+
+	fakeMgr.On("Get", mock.Anything, int64(1)).Return(&policy_model.Policy{
+		Targets: []policy_model.EventTarget{{Address: ts.URL, SigningSecret: "wrong"}},
+	}, nil).Once()
+	assert.NotNil(t, rep.Run(ctx, params))
+
+	// the index is stale, the target is looked up by the address
+	fakeMgr.On("Get", mock.Anything, int64(1)).Return(&policy_model.Policy{
+		Targets: []policy_model.EventTarget{
+			{Type: "http", Address: "http://other.com", SigningSecret: "wrong"},
+			{Type: "http", Address: ts.URL, SigningSecret: "secret"},
+		},
+	}, nil).Once()
+	assert.Nil(t, rep.Run(ctx, params))
+
+	// the target is removed from the policy
+	fakeMgr.On("Get", mock.Anything, int64(1)).Return(&policy_model.Policy{}, nil).Once()
+	assert.NotNil(t, rep.Run(ctx, params))
+	fakeMgr.AssertExpectations(t)
+}
+
+func TestWebhookJobRunWithRetryPolicy(t *testing.T) {
+	var checkins []string
+	ctx := &mockjobservice.MockJobContext{}
+	ctx.On("GetLogger").Return(&mockjobservice.MockJobLogger{})
+	ctx.On("Checkin", mock.Anything).Run(func(args mock.Arguments) {
+		checkins = append(checkins, args.String(0))
+	}).Return(nil)
+
+	count := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count++
+			if count < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("unavailable"))
+			}
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"payload":      `{"key": "value"}`,
+		"address":      ts.URL,
+		"retry_policy": `{"max_retries":3,"backoff":10}`,
+	}
+
+	// the failed attempt is re-enqueued by the core rather than retried by the jobservice
+	rep := &WebhookJob{}
+	assert.NotNil(t, rep.Run(ctx, params))
+	assert.False(t, rep.ShouldRetry())
+	assert.Equal(t, 1, count)
+
+	// the re-enqueued job carrying the attempt count succeeds
+	params["attempt"] = float64(2)
+	rep = &WebhookJob{}
+	assert.Nil(t, rep.Run(ctx, params))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{
+		`{"status_code":503,"response":"unavailable","error":"webhook job(target: ` + ts.URL + `) response code is 503"}`,
+		`{"status_code":200}`,
+	}, checkins)
+
+	// invalid retry policy
+	params["retry_policy"] = `{"max_retries":100,"backoff":10}`
+	assert.NotNil(t, rep.Run(ctx, params))
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,160 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package purge
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/audit"
+)
+
+const (
+	// RetentionHour the parameter key of the retention hours of the audit logs
+	RetentionHour = "audit_retention_hour"
+	// IncludeOperations the parameter key of the operations of the audit logs to be purged, separated by comma
+	IncludeOperations = "include_operations"
+	// DryRun the parameter key of the dry run mode
+	DryRun = "dry_run"
+	// DefaultIncludeOperations the operations purged by default
+	DefaultIncludeOperations = "create,delete,pull"
+	// MaxRetentionHour the max retention hours, about 10 years
+	MaxRetentionHour = 240000
+	// the count of the audit logs purged in one batch
+	purgeBatchSize = 10000
+)
+
+// Job the job to purge the audit logs
+type Job struct {
+	retentionHour     int
+	includeOperations []string
+	dryRun            bool
+	auditMgr          audit.Manager
+	logger            logger.Interface
+}
+
+// MaxFails implements the interface in job/Interface
+func (j *Job) MaxFails() uint {
+	return 1
+}
+
+// MaxCurrency is implementation of same method in Interface.
+func (j *Job) MaxCurrency() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (j *Job) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (j *Job) Validate(params job.Parameters) error {
+	if params == nil {
+		return fmt.Errorf("missing the parameter %s", RetentionHour)
+	}
+	hour, err := parseRetentionHour(params[RetentionHour])
+	if err != nil {
+		return err
+	}
+	if hour <= 0 || hour > MaxRetentionHour {
+		return fmt.Errorf("the %s should be in range (0, %d], got %d", RetentionHour, MaxRetentionHour, hour)
+	}
+	return nil
+}
+
+// parseParams set the parameters according to the API call
+func (j *Job) parseParams(params job.Parameters) {
+	j.retentionHour, _ = parseRetentionHour(params[RetentionHour])
+
+	ops := DefaultIncludeOperations
+	if v, ok := params[IncludeOperations].(string); ok && len(strings.TrimSpace(v)) > 0 {
+		ops = v
+	}
+	j.includeOperations = nil
+	for _, op := range strings.Split(ops, ",") {
+		if op = strings.TrimSpace(op); len(op) > 0 {
+			j.includeOperations = append(j.includeOperations, op)
+		}
+	}
+
+	j.dryRun = false
+	if dryRun, ok := params[DryRun].(bool); ok {
+		j.dryRun = dryRun
+	}
+}
+
+// Run implements the interface in job/Interface
+func (j *Job) Run(ctx job.Context, params job.Parameters) error {
+	j.logger = ctx.GetLogger()
+	if opCmd, flag := ctx.OPCommand(); flag && opCmd.IsStop() {
+		j.logger.Info("received the stop signal, quit the purge audit log job.")
+		return nil
+	}
+	// UT will use the mock manager
+	if os.Getenv("UTTEST") != "true" {
+		j.auditMgr = audit.Mgr
+	}
+	j.parseParams(params)
+	j.logger.Infof("Purge audit log parameters: [%s: %d, %s: %s, %s: %t]",
+		RetentionHour, j.retentionHour, IncludeOperations, strings.Join(j.includeOperations, ","), DryRun, j.dryRun)
+
+	if j.dryRun {
+		total, err := j.auditMgr.Purge(ctx.SystemContext(), j.retentionHour, j.includeOperations, true, 0)
+		if err != nil {
+			j.logger.Errorf("failed to purge audit logs, error: %v", err)
+			return err
+		}
+		j.logger.Infof("dry run mode, %d audit logs would be purged", total)
+		return nil
+	}
+
+	// purge in batches to avoid the long transaction on the huge table, and check the stop signal between batches
+	var total int64
+	for {
+		if opCmd, flag := ctx.OPCommand(); flag && opCmd.IsStop() {
+			j.logger.Infof("received the stop signal, %d audit logs purged before stopping", total)
+			return nil
+		}
+		n, err := j.auditMgr.Purge(ctx.SystemContext(), j.retentionHour, j.includeOperations, false, purgeBatchSize)
+		if err != nil {
+			j.logger.Errorf("failed to purge audit logs, %d audit logs purged before the error: %v", total, err)
+			return err
+		}
+		total += n
+		if n < purgeBatchSize {
+			break
+		}
+	}
+	j.logger.Infof("purged %d audit logs", total)
+	return nil
+}
+
+// the numbers in the parameters are float64 after the JSON decoding
+func parseRetentionHour(v interface{}) (int, error) {
+	switch h := v.(type) {
+	case int:
+		return h, nil
+	case int64:
+		return int(h), nil
+	case float64:
+		return int(h), nil
+	default:
+		return 0, fmt.Errorf("invalid %s: %v", RetentionHour, v)
+	}
+}
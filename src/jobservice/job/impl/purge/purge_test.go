@@ -0,0 +1,141 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package purge
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
+	"github.com/goharbor/harbor/src/testing/mock"
+	"github.com/goharbor/harbor/src/testing/pkg/audit"
+	"github.com/stretchr/testify/suite"
+)
+
+type purgeTestSuite struct {
+	suite.Suite
+	auditMgr *audit.Manager
+}
+
+func (suite *purgeTestSuite) SetupTest() {
+	suite.auditMgr = &audit.Manager{}
+}
+
+func (suite *purgeTestSuite) TestMaxFails() {
+	j := &Job{}
+	suite.Equal(uint(1), j.MaxFails())
+}
+
+func (suite *purgeTestSuite) TestShouldRetry() {
+	j := &Job{}
+	suite.False(j.ShouldRetry())
+}
+
+func (suite *purgeTestSuite) TestValidate() {
+	j := &Job{}
+	suite.NotNil(j.Validate(nil))
+	suite.NotNil(j.Validate(job.Parameters{}))
+	suite.NotNil(j.Validate(job.Parameters{RetentionHour: "24"}))
+	suite.NotNil(j.Validate(job.Parameters{RetentionHour: float64(0)}))
+	suite.NotNil(j.Validate(job.Parameters{RetentionHour: float64(MaxRetentionHour + 1)}))
+	suite.Nil(j.Validate(job.Parameters{RetentionHour: float64(24)}))
+	suite.Nil(j.Validate(job.Parameters{RetentionHour: 24}))
+}
+
+func (suite *purgeTestSuite) TestParseParams() {
+	j := &Job{}
+	j.parseParams(job.Parameters{RetentionHour: float64(48)})
+	suite.Equal(48, j.retentionHour)
+	suite.Equal([]string{"create", "delete", "pull"}, j.includeOperations)
+	suite.False(j.dryRun)
+
+	j.parseParams(job.Parameters{
+		RetentionHour:     float64(24),
+		IncludeOperations: "create, pull",
+		DryRun:            true,
+	})
+	suite.Equal(24, j.retentionHour)
+	suite.Equal([]string{"create", "pull"}, j.includeOperations)
+	suite.True(j.dryRun)
+}
+
+func (suite *purgeTestSuite) TestRun() {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("OPCommand").Return(job.NilCommand, true)
+	suite.auditMgr.On("Purge", mock.Anything, 24, []string{"create"}, true, 0).Return(int64(10), nil)
+
+	j := &Job{auditMgr: suite.auditMgr}
+	err := j.Run(ctx, job.Parameters{
+		RetentionHour:     float64(24),
+		IncludeOperations: "create",
+		DryRun:            true,
+	})
+	suite.Nil(err)
+	suite.auditMgr.AssertExpectations(suite.T())
+}
+
+func (suite *purgeTestSuite) TestRunInBatches() {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("OPCommand").Return(job.NilCommand, true)
+	suite.auditMgr.On("Purge", mock.Anything, 24, []string{"create"}, false, purgeBatchSize).Return(int64(purgeBatchSize), nil).Twice()
+	suite.auditMgr.On("Purge", mock.Anything, 24, []string{"create"}, false, purgeBatchSize).Return(int64(10), nil).Once()
+
+	j := &Job{auditMgr: suite.auditMgr}
+	err := j.Run(ctx, job.Parameters{
+		RetentionHour:     float64(24),
+		IncludeOperations: "create",
+	})
+	suite.Nil(err)
+	suite.auditMgr.AssertExpectations(suite.T())
+}
+
+func (suite *purgeTestSuite) TestStopBetweenBatches() {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("OPCommand").Return(job.NilCommand, true).Twice()
+	ctx.On("OPCommand").Return(job.StopCommand, true)
+	suite.auditMgr.On("Purge", mock.Anything, 24, []string{"create"}, false, purgeBatchSize).Return(int64(purgeBatchSize), nil).Once()
+
+	j := &Job{auditMgr: suite.auditMgr}
+	err := j.Run(ctx, job.Parameters{
+		RetentionHour:     float64(24),
+		IncludeOperations: "create",
+	})
+	suite.Nil(err)
+	suite.auditMgr.AssertExpectations(suite.T())
+}
+
+func (suite *purgeTestSuite) TestStop() {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+	ctx.On("GetLogger").Return(logger)
+	ctx.On("OPCommand").Return(job.StopCommand, true)
+
+	j := &Job{auditMgr: suite.auditMgr}
+	err := j.Run(ctx, job.Parameters{RetentionHour: float64(24)})
+	suite.Nil(err)
+	suite.auditMgr.AssertNotCalled(suite.T(), "Purge", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeTestSuite(t *testing.T) {
+	os.Setenv("UTTEST", "true")
+	suite.Run(t, &purgeTestSuite{})
+}
@@ -56,7 +56,7 @@ func (r *Replication) Validate(params job.Parameters) error {
 func (r *Replication) Run(ctx job.Context, params job.Parameters) error {
 	logger := ctx.GetLogger()
 
-	src, dst, speed, err := parseParams(params)
+	src, dst, opts, err := parseParams(params)
 	if err != nil {
 		logger.Errorf("failed to parse parameters: %v", err)
 		return err
@@ -81,17 +81,42 @@ func (r *Replication) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 
-	return trans.Transfer(src, dst, speed)
+	// only compare the source resource with the destination one in verify mode
+	if verify, _ := params["verify"].(bool); verify {
+		return r.verify(ctx, trans, src, dst)
+	}
+
+	return trans.Transfer(src, dst, opts)
 }
 
-func parseParams(params map[string]interface{}) (*model.Resource, *model.Resource, int32, error) {
+func (r *Replication) verify(ctx job.Context, trans transfer.Transfer, src, dst *model.Resource) error {
+	logger := ctx.GetLogger()
+	verifier, ok := trans.(transfer.Verifier)
+	if !ok {
+		err := fmt.Errorf("the transfer for resource type %s doesn't support verifying", src.Type)
+		logger.Error(err)
+		return err
+	}
+	result, err := verifier.Verify(src, dst)
+	if err != nil {
+		logger.Errorf("failed to verify: %v", err)
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return ctx.Checkin(string(data))
+}
+
+func parseParams(params map[string]interface{}) (*model.Resource, *model.Resource, *transfer.Options, error) {
 	src := &model.Resource{}
 	if err := parseParam(params, "src_resource", src); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, nil, err
 	}
 	dst := &model.Resource{}
 	if err := parseParam(params, "dst_resource", dst); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, nil, err
 	}
 	var speed int32 = 0
 	value, exist := params["speed"]
@@ -107,12 +132,28 @@ func parseParams(params map[string]interface{}) (*model.Resource, *model.Resourc
 				if s, ok := value.(float64); ok {
 					speed = int32(s)
 				} else {
-					return nil, nil, 0, fmt.Errorf("the value of speed isn't integer (%T)", value)
+					return nil, nil, nil, fmt.Errorf("the value of speed isn't integer (%T)", value)
 				}
 			}
 		}
 	}
-	return src, dst, speed, nil
+	opts := &transfer.Options{
+		Speed: speed,
+	}
+	// the bandwidth windows are optional for the jobs submitted by the old versions
+	if _, exist := params["bandwidth_windows"]; exist {
+		if err := parseParam(params, "bandwidth_windows", &opts.BandwidthWindows); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if value, exist := params["copy_by_chunk"]; exist {
+		copyByChunk, ok := value.(bool)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("the value of copy_by_chunk isn't bool (%T)", value)
+		}
+		opts.CopyByChunk = copyByChunk
+	}
+	return src, dst, opts, nil
 }
 
 func parseParam(params map[string]interface{}, name string, v interface{}) error {
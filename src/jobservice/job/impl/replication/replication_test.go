@@ -53,11 +53,25 @@ func TestParseParams(t *testing.T) {
 		"src_resource": `{"type":"chart"}`,
 		"dst_resource": `{"type":"chart"}`,
 	}
-	res, dst, speed, err := parseParams(params)
+	res, dst, opts, err := parseParams(params)
 	require.Nil(t, err)
 	assert.Equal(t, "chart", string(res.Type))
 	assert.Equal(t, "chart", string(dst.Type))
-	assert.Equal(t, int32(0), speed)
+	assert.Equal(t, int32(0), opts.Speed)
+	assert.Len(t, opts.BandwidthWindows, 0)
+
+	// with speed and bandwidth windows
+	params["speed"] = float64(100)
+	params["bandwidth_windows"] = `[{"start":"08:00","end":"18:00","speed":1024}]`
+	params["copy_by_chunk"] = true
+	_, _, opts, err = parseParams(params)
+	require.Nil(t, err)
+	assert.Equal(t, int32(100), opts.Speed)
+	assert.True(t, opts.CopyByChunk)
+	require.Len(t, opts.BandwidthWindows, 1)
+	assert.Equal(t, "08:00", opts.BandwidthWindows[0].Start)
+	assert.Equal(t, "18:00", opts.BandwidthWindows[0].End)
+	assert.Equal(t, int32(1024), opts.BandwidthWindows[0].Speed)
 }
 
 func TestMaxFails(t *testing.T) {
@@ -83,7 +97,7 @@ var fakedTransferFactory = func(transfer.Logger, transfer.StopFunc) (transfer.Tr
 
 type fakedTransfer struct{}
 
-func (f *fakedTransfer) Transfer(src *model.Resource, dst *model.Resource, speed int32) error {
+func (f *fakedTransfer) Transfer(src *model.Resource, dst *model.Resource, opts *transfer.Options) error {
 	transferred = true
 	return nil
 }
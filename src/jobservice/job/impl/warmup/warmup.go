@@ -0,0 +1,268 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/controller/proxy"
+	"github.com/goharbor/harbor/src/jobservice/config"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/registry"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// ProjectID the parameter key of the ID of the proxy cache project
+	ProjectID = "project_id"
+	// Pattern the parameter key of the pattern of the artifacts to warm up, the format is
+	// "repository:tag", e.g. "library/nginx:1.*", all tags are matched if the tag part is omitted
+	Pattern = "pattern"
+)
+
+// Progress is the progress of the warm-up job which is checked in periodically
+type Progress struct {
+	Total  int `json:"total"`
+	Cached int `json:"cached"`
+	Failed int `json:"failed"`
+}
+
+// Job pre-populates the proxy cache project. It lists the artifacts matching the pattern in the
+// upstream registries and pulls them through the proxy cache project of Harbor core, so the manifests
+// and blobs are cached by the proxy controller in the same way as they are pulled by the clients.
+// The content can't be pushed into the local storage inside jobservice directly, as the secret used
+// by the proxy controller to push the content only lives in the core process
+type Job struct {
+	projectID int64
+	pattern   string
+	logger    logger.Interface
+	local     registry.Client
+	progress  *Progress
+}
+
+// MaxFails implements the interface in job/Interface
+func (j *Job) MaxFails() uint {
+	return 1
+}
+
+// MaxCurrency is implementation of same method in Interface.
+func (j *Job) MaxCurrency() uint {
+	return 0
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (j *Job) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (j *Job) Validate(params job.Parameters) error {
+	_, _, err := parseParams(params)
+	return err
+}
+
+// Run implements the interface in job/Interface
+func (j *Job) Run(ctx job.Context, params job.Parameters) error {
+	j.logger = ctx.GetLogger()
+	projectID, pattern, err := parseParams(params)
+	if err != nil {
+		j.logger.Errorf("failed to parse parameters: %v", err)
+		return err
+	}
+	j.projectID, j.pattern = projectID, pattern
+
+	p, err := project.Ctl.Get(ctx.SystemContext(), j.projectID)
+	if err != nil {
+		j.logger.Errorf("failed to get the project %d: %v", j.projectID, err)
+		return err
+	}
+	if !p.IsProxy() {
+		err = fmt.Errorf("the project %s isn't a proxy cache project", p.Name)
+		j.logger.Error(err)
+		return err
+	}
+	remote, err := proxy.NewRemoteHelper(ctx.SystemContext(), p.ProxyRegistryIDs()...)
+	if err != nil {
+		j.logger.Errorf("failed to create the client for the upstream registries: %v", err)
+		return err
+	}
+
+	repository, tag := ParsePattern(j.pattern)
+	resources, err := remote.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: repository},
+		{Type: model.FilterTypeTag, Value: tag},
+	})
+	if err != nil {
+		j.logger.Errorf("failed to list the artifacts matching %s in the upstream registries: %v", j.pattern, err)
+		return err
+	}
+	references := listReferences(resources)
+	j.logger.Infof("%d artifacts matching %s found in the upstream registries", len(references), j.pattern)
+
+	j.local = registry.NewClientWithAuthorizer(config.GetCoreURL(), auth.NewSecretAuthorizer(config.GetAuthSecret()), true)
+	j.progress = &Progress{Total: len(references)}
+	j.checkIn(ctx)
+	for _, ref := range references {
+		if isStopped(ctx) {
+			j.logger.Info("the job is stopped")
+			return nil
+		}
+		// pull through the proxy cache project
+		repository := p.Name + "/" + ref.repository
+		if err := j.warmUp(repository, ref.tag); err != nil {
+			j.logger.Errorf("failed to warm up %s:%s: %v", repository, ref.tag, err)
+			j.progress.Failed++
+		} else {
+			j.logger.Infof("%s:%s warmed up", repository, ref.tag)
+			j.progress.Cached++
+		}
+		j.checkIn(ctx)
+	}
+
+	j.logger.Infof("warm up completed, total: %d, cached: %d, failed: %d",
+		j.progress.Total, j.progress.Cached, j.progress.Failed)
+	if j.progress.Failed > 0 {
+		return fmt.Errorf("%d artifacts failed to warm up", j.progress.Failed)
+	}
+	return nil
+}
+
+// warmUp pulls the manifest and the content it references recursively
+func (j *Job) warmUp(repository, reference string) error {
+	manifest, _, err := j.local.PullManifest(repository, reference)
+	if err != nil {
+		return err
+	}
+	for _, desc := range manifest.References() {
+		switch desc.MediaType {
+		case schema2.MediaTypeManifest, v1.MediaTypeImageManifest,
+			manifestlist.MediaTypeManifestList, v1.MediaTypeImageIndex:
+			if err := j.warmUp(repository, string(desc.Digest)); err != nil {
+				return err
+			}
+		default:
+			if err := j.warmUpBlob(repository, string(desc.Digest)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (j *Job) warmUpBlob(repository, digest string) error {
+	exist, err := j.local.BlobExist(repository, digest)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	_, blob, err := j.local.PullBlob(repository, digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	// the blob is cached by the proxy while pulling, drain the content only
+	_, err = io.Copy(ioutil.Discard, blob)
+	return err
+}
+
+func (j *Job) checkIn(ctx job.Context) {
+	data, err := json.Marshal(j.progress)
+	if err != nil {
+		j.logger.Errorf("failed to marshal the progress: %v", err)
+		return
+	}
+	if err := ctx.Checkin(string(data)); err != nil {
+		j.logger.Errorf("failed to check in the progress: %v", err)
+	}
+}
+
+func isStopped(ctx job.Context) bool {
+	cmd, exist := ctx.OPCommand()
+	return exist && cmd == job.StopCommand
+}
+
+type reference struct {
+	repository string
+	tag        string
+}
+
+func listReferences(resources []*model.Resource) []*reference {
+	var references []*reference
+	for _, resource := range resources {
+		if resource.Metadata == nil || resource.Metadata.Repository == nil {
+			continue
+		}
+		repository := resource.Metadata.Repository.Name
+		var tags []string
+		for _, artifact := range resource.Metadata.Artifacts {
+			tags = append(tags, artifact.Tags...)
+		}
+		if len(tags) == 0 {
+			tags = resource.Metadata.Vtags
+		}
+		for _, tag := range tags {
+			references = append(references, &reference{repository: repository, tag: tag})
+		}
+	}
+	return references
+}
+
+// ParsePattern splits the "repository:tag" pattern into the repository and tag patterns
+func ParsePattern(pattern string) (string, string) {
+	if i := strings.Index(pattern, ":"); i >= 0 {
+		return pattern[:i], pattern[i+1:]
+	}
+	return pattern, ""
+}
+
+func parseParams(params job.Parameters) (int64, string, error) {
+	if params == nil {
+		return 0, "", fmt.Errorf("missing the parameter %s", ProjectID)
+	}
+	var projectID int64
+	switch id := params[ProjectID].(type) {
+	case int:
+		projectID = int64(id)
+	case int64:
+		projectID = id
+	case float64:
+		projectID = int64(id)
+	default:
+		return 0, "", fmt.Errorf("the value of %s isn't integer (%T)", ProjectID, params[ProjectID])
+	}
+	if projectID <= 0 {
+		return 0, "", fmt.Errorf("invalid %s: %d", ProjectID, projectID)
+	}
+	pattern, ok := params[Pattern].(string)
+	if !ok || len(pattern) == 0 {
+		return 0, "", fmt.Errorf("missing the parameter %s", Pattern)
+	}
+	if repository, _ := ParsePattern(pattern); len(repository) == 0 {
+		return 0, "", fmt.Errorf("the repository part of the pattern %s is empty", pattern)
+	}
+	return projectID, pattern, nil
+}
@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type warmUpTestSuite struct {
+	suite.Suite
+}
+
+func (w *warmUpTestSuite) TestMaxFails() {
+	j := &Job{}
+	w.Equal(uint(1), j.MaxFails())
+}
+
+func (w *warmUpTestSuite) TestShouldRetry() {
+	j := &Job{}
+	w.False(j.ShouldRetry())
+}
+
+func (w *warmUpTestSuite) TestValidate() {
+	j := &Job{}
+	w.NotNil(j.Validate(nil))
+	w.NotNil(j.Validate(job.Parameters{}))
+	w.NotNil(j.Validate(job.Parameters{ProjectID: "1", Pattern: "library/nginx"}))
+	w.NotNil(j.Validate(job.Parameters{ProjectID: float64(0), Pattern: "library/nginx"}))
+	w.NotNil(j.Validate(job.Parameters{ProjectID: float64(1)}))
+	w.NotNil(j.Validate(job.Parameters{ProjectID: float64(1), Pattern: ":latest"}))
+	w.Nil(j.Validate(job.Parameters{ProjectID: float64(1), Pattern: "library/nginx"}))
+	w.Nil(j.Validate(job.Parameters{ProjectID: 1, Pattern: "library/*:1.*"}))
+}
+
+func (w *warmUpTestSuite) TestParsePattern() {
+	repository, tag := ParsePattern("library/nginx")
+	w.Equal("library/nginx", repository)
+	w.Equal("", tag)
+
+	repository, tag = ParsePattern("library/**:{1.20,latest}")
+	w.Equal("library/**", repository)
+	w.Equal("{1.20,latest}", tag)
+}
+
+func (w *warmUpTestSuite) TestListReferences() {
+	resources := []*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{Name: "library/nginx"},
+				Artifacts: []*model.Artifact{
+					{Tags: []string{"1.20", "latest"}},
+					{Tags: []string{"1.19"}},
+				},
+			},
+		},
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{Name: "library/redis"},
+				Vtags:      []string{"6"},
+			},
+		},
+		{},
+	}
+	references := listReferences(resources)
+	w.Require().Len(references, 4)
+	w.Equal(reference{repository: "library/nginx", tag: "1.20"}, *references[0])
+	w.Equal(reference{repository: "library/nginx", tag: "1.19"}, *references[2])
+	w.Equal(reference{repository: "library/redis", tag: "6"}, *references[3])
+}
+
+func TestWarmUpTestSuite(t *testing.T) {
+	suite.Run(t, &warmUpTestSuite{})
+}
@@ -24,14 +24,20 @@ const (
 	ImageScanJob = "IMAGE_SCAN"
 	// GarbageCollection job name
 	GarbageCollection = "GARBAGE_COLLECTION"
+	// PurgeAudit job name
+	PurgeAudit = "PURGE_AUDIT"
 	// Replication : the name of the replication job in job service
 	Replication = "REPLICATION"
 	// WebhookJob : the name of the webhook job in job service
 	WebhookJob = "WEBHOOK"
 	// SlackJob : the name of the slack job in job service
 	SlackJob = "SLACK"
+	// EmailJob : the name of the email job in job service
+	EmailJob = "EMAIL"
 	// Retention : the name of the retention job
 	Retention = "RETENTION"
 	// P2PPreheat : the name of the P2P preheat job
 	P2PPreheat = "P2P_PREHEAT"
+	// ProxyWarmUp : the name of the job which pre-populates the proxy cache project
+	ProxyWarmUp = "PROXY_WARM_UP"
 )
@@ -196,6 +196,16 @@ func NotificationEnable(ctx context.Context) bool {
 	return defaultMgr().Get(ctx, common.NotificationEnable).GetBool()
 }
 
+// AuditLogForwardEndpoint returns the endpoint which the audit logs are forwarded to
+func AuditLogForwardEndpoint(ctx context.Context) string {
+	return defaultMgr().Get(ctx, common.AuditLogForwardEndpoint).GetString()
+}
+
+// SkipAuditLogDatabase returns a bool to indicates if the audit logs are skipped to be stored in the database
+func SkipAuditLogDatabase(ctx context.Context) bool {
+	return defaultMgr().Get(ctx, common.SkipAuditLogDatabase).GetBool()
+}
+
 // QuotaPerProjectEnable returns a bool to indicates if quota per project enabled in harbor
 func QuotaPerProjectEnable(ctx context.Context) bool {
 	return defaultMgr().Get(ctx, common.QuotaPerProjectEnable).GetBool()
@@ -26,6 +26,7 @@ const (
 	contextKeyArtifactInfo contextKey = "artifactInfo"
 	contextKeyAuthMode     contextKey = "authMode"
 	contextKeyCarrySession contextKey = "carrySession"
+	contextKeyUpstreamTags contextKey = "upstreamTags"
 )
 
 // ArtifactInfo wraps the artifact info extracted from the request to "/v2/"
@@ -112,3 +113,17 @@ func GetCarrySession(ctx context.Context) bool {
 	}
 	return carrySession
 }
+
+// WithUpstreamTags returns a context with the tags listed from the upstream registry of the proxy cache project set
+func WithUpstreamTags(ctx context.Context, tags []string) context.Context {
+	return setToContext(ctx, contextKeyUpstreamTags, tags)
+}
+
+// GetUpstreamTags gets the tags listed from the upstream registry of the proxy cache project from the context
+func GetUpstreamTags(ctx context.Context) (tags []string, exist bool) {
+	value := getFromContext(ctx, contextKeyUpstreamTags)
+	if value != nil {
+		tags, exist = value.([]string)
+	}
+	return
+}
@@ -39,3 +39,15 @@ func TestGetAPIVersion(t *testing.T) {
 	version = GetAPIVersion(ctx)
 	assert.Equal(t, "1.0", version)
 }
+
+func TestGetUpstreamTags(t *testing.T) {
+	// not set in context
+	_, exist := GetUpstreamTags(context.Background())
+	assert.False(t, exist)
+
+	// set in context
+	ctx := WithUpstreamTags(context.Background(), []string{"latest"})
+	tags, exist := GetUpstreamTags(ctx)
+	assert.True(t, exist)
+	assert.Equal(t, []string{"latest"}, tags)
+}
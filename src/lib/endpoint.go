@@ -17,6 +17,7 @@ package lib
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/goharbor/harbor/src/lib/errors"
@@ -44,3 +45,33 @@ func ValidateHTTPURL(s string) (string, error) {
 	// To avoid SSRF security issue, refer to #3755 for more detail
 	return fmt.Sprintf("%s://%s%s", url.Scheme, url.Host, url.Path), nil
 }
+
+// ValidateFileURL checks whether the provided string is a valid file URL.
+// If it is, return the URL in format "file:///path" with the path cleaned,
+// the path must be relative to the root of the storage so ".." isn't allowed
+func ValidateFileURL(s string) (string, error) {
+	s = strings.Trim(s, " ")
+	if len(s) == 0 {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("empty string")
+	}
+	url, err := url.Parse(s)
+	if err != nil {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid URL: %s", err.Error())
+	}
+	if url.Scheme != "file" {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid file scheme: %s", url.Scheme)
+	}
+	if len(url.Host) > 0 {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("host isn't supported in file URL: %s", url.Host)
+	}
+	for _, elem := range strings.Split(url.Path, "/") {
+		if elem == ".." {
+			return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the path of file URL cannot contain \"..\"")
+		}
+	}
+	p := path.Clean("/" + url.Path)
+	if p == "/" {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("empty path in file URL")
+	}
+	return "file://" + p, nil
+}
@@ -12,6 +12,8 @@ func RegisterCollectors() {
 		TotalInFlightGauge,
 		TotalReqCnt,
 		TotalReqDurSummary,
+		ProxyStaleServeCnt,
+		ProxyEvictedArtifactCnt,
 	}...)
 }
 
@@ -47,4 +49,26 @@ var (
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 		},
 		[]string{"method", "operation"})
+
+	// ProxyStaleServeCnt used to collect the count of stale manifests served by the proxy cache projects
+	ProxyStaleServeCnt = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "proxy_stale_serve_total",
+			Help:      "The total number of stale manifests served by the proxy cache projects when the upstream registries are unreachable",
+		},
+		[]string{"project"},
+	)
+
+	// ProxyEvictedArtifactCnt used to collect the count of artifacts evicted from the proxy cache projects
+	ProxyEvictedArtifactCnt = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "proxy_evicted_artifacts_total",
+			Help:      "The total number of artifacts evicted from the proxy cache projects when the storage usage nears the quota",
+		},
+		[]string{"project"},
+	)
 )
@@ -30,6 +30,8 @@ var (
 	V2CatalogURLRe = regexp.MustCompile(`^/v2/_catalog(/.*)?$`)
 	// RepositoryNameRe is the regular expression for  matching repository name
 	RepositoryNameRe = regexp.MustCompile(fmt.Sprintf("^%s$", reference.NameRegexp))
+	// TagNameRe is the regular expression for matching tag name
+	TagNameRe = regexp.MustCompile(fmt.Sprintf("^%s$", reference.TagRegexp))
 )
 
 // MatchManifestURLPattern checks whether the provided path matches the manifest URL pattern,
@@ -0,0 +1,173 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	beegoorm "github.com/astaxie/beego/orm"
+	common_dao "github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/accessory/model"
+	artdao "github.com/goharbor/harbor/src/pkg/artifact/dao"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type daoTestSuite struct {
+	suite.Suite
+	dao         DAO
+	artDAO      artdao.DAO
+	subArtID    int64
+	sigArtID    int64
+	accessoryID int64
+	ctx         context.Context
+}
+
+func (d *daoTestSuite) SetupSuite() {
+	d.dao = New()
+	d.artDAO = artdao.New()
+	common_dao.PrepareTestForPostgresSQL()
+	d.ctx = orm.NewContext(nil, beegoorm.NewOrm())
+}
+
+func (d *daoTestSuite) SetupTest() {
+	now := time.Now()
+	id, err := d.artDAO.Create(d.ctx, &artdao.Artifact{
+		Type:              "IMAGE",
+		MediaType:         v1.MediaTypeImageConfig,
+		ManifestMediaType: v1.MediaTypeImageManifest,
+		ProjectID:         1,
+		RepositoryID:      1,
+		RepositoryName:    "library/hello-world",
+		Digest:            "subject_digest",
+		PushTime:          now,
+		PullTime:          now,
+	})
+	d.Require().Nil(err)
+	d.subArtID = id
+
+	id, err = d.artDAO.Create(d.ctx, &artdao.Artifact{
+		Type:              "IMAGE",
+		MediaType:         "application/vnd.oci.image.config.v1+json",
+		ManifestMediaType: v1.MediaTypeImageManifest,
+		ProjectID:         1,
+		RepositoryID:      1,
+		RepositoryName:    "library/hello-world",
+		Digest:            "signature_digest",
+		PushTime:          now,
+		PullTime:          now,
+	})
+	d.Require().Nil(err)
+	d.sigArtID = id
+
+	id, err = d.dao.Create(d.ctx, &model.Accessory{
+		ArtifactID:        d.sigArtID,
+		SubjectArtifactID: d.subArtID,
+		Type:              model.TypeCosignSignature,
+		Size:              1024,
+		Digest:            "signature_digest",
+	})
+	d.Require().Nil(err)
+	d.accessoryID = id
+}
+
+func (d *daoTestSuite) TearDownTest() {
+	_, err := d.dao.DeleteAccessories(d.ctx, q.New(q.KeyWords{"SubjectArtifactID": d.subArtID}))
+	d.Require().Nil(err)
+	err = d.artDAO.Delete(d.ctx, d.sigArtID)
+	d.Require().Nil(err)
+	err = d.artDAO.Delete(d.ctx, d.subArtID)
+	d.Require().Nil(err)
+}
+
+func (d *daoTestSuite) TestCount() {
+	total, err := d.dao.Count(d.ctx, q.New(q.KeyWords{"SubjectArtifactID": d.subArtID}))
+	d.Require().Nil(err)
+	d.Equal(int64(1), total)
+
+	total, err = d.dao.Count(d.ctx, q.New(q.KeyWords{"SubjectArtifactID": d.sigArtID}))
+	d.Require().Nil(err)
+	d.Equal(int64(0), total)
+}
+
+func (d *daoTestSuite) TestList() {
+	accessories, err := d.dao.List(d.ctx, q.New(q.KeyWords{
+		"SubjectArtifactID": d.subArtID,
+		"Type":              model.TypeCosignSignature,
+	}))
+	d.Require().Nil(err)
+	d.Require().Len(accessories, 1)
+	d.Equal(d.sigArtID, accessories[0].ArtifactID)
+	d.Equal("signature_digest", accessories[0].Digest)
+}
+
+func (d *daoTestSuite) TestGet() {
+	// not exist
+	_, err := d.dao.Get(d.ctx, 10000)
+	d.Require().NotNil(err)
+	d.True(errors.IsErr(err, errors.NotFoundCode))
+
+	// exist
+	accessory, err := d.dao.Get(d.ctx, d.accessoryID)
+	d.Require().Nil(err)
+	d.Equal(d.subArtID, accessory.SubjectArtifactID)
+	d.Equal(model.TypeCosignSignature, accessory.Type)
+}
+
+func (d *daoTestSuite) TestCreate() {
+	// conflict
+	_, err := d.dao.Create(d.ctx, &model.Accessory{
+		ArtifactID:        d.sigArtID,
+		SubjectArtifactID: d.subArtID,
+		Type:              model.TypeCosignSignature,
+	})
+	d.Require().NotNil(err)
+	d.True(errors.IsErr(err, errors.ConflictCode))
+
+	// the artifact doesn't exist
+	_, err = d.dao.Create(d.ctx, &model.Accessory{
+		ArtifactID:        10000,
+		SubjectArtifactID: d.subArtID,
+		Type:              model.TypeCosignSignature,
+	})
+	d.Require().NotNil(err)
+	d.True(errors.IsErr(err, errors.ViolateForeignKeyConstraintCode))
+}
+
+func (d *daoTestSuite) TestDelete() {
+	// not exist
+	err := d.dao.Delete(d.ctx, 10000)
+	d.Require().NotNil(err)
+	d.True(errors.IsErr(err, errors.NotFoundCode))
+}
+
+func (d *daoTestSuite) TestDeleteAccessories() {
+	n, err := d.dao.DeleteAccessories(d.ctx, q.New(q.KeyWords{"ArtifactID": d.sigArtID}))
+	d.Require().Nil(err)
+	d.Equal(int64(1), n)
+
+	_, err = d.dao.Get(d.ctx, d.accessoryID)
+	d.Require().NotNil(err)
+	d.True(errors.IsErr(err, errors.NotFoundCode))
+}
+
+func TestDaoTestSuite(t *testing.T) {
+	suite.Run(t, &daoTestSuite{})
+}
@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessory
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/accessory/dao"
+	"github.com/goharbor/harbor/src/pkg/accessory/model"
+)
+
+var (
+	// Mgr is a global accessory manager instance
+	Mgr = NewManager()
+)
+
+// Manager is the only interface of accessory module to provide the management functions for accessories
+type Manager interface {
+	// Ensure the accessory specified by the artifact ID is attached to the subject artifact,
+	// creates the link if it doesn't exist
+	Ensure(ctx context.Context, subjectArtifactID, artifactID, size int64, digest, accessoryType string) (err error)
+	// Get the accessory specified by ID
+	Get(ctx context.Context, id int64) (accessory *model.Accessory, err error)
+	// Count returns the total count of accessories according to the query
+	Count(ctx context.Context, query *q.Query) (total int64, err error)
+	// List accessories according to the query
+	List(ctx context.Context, query *q.Query) (accessories []*model.Accessory, err error)
+	// Delete the accessory specified by ID
+	Delete(ctx context.Context, id int64) (err error)
+	// DeleteAccessories deletes the accessories according to the query
+	DeleteAccessories(ctx context.Context, query *q.Query) (err error)
+}
+
+// NewManager returns an instance of the default manager
+func NewManager() Manager {
+	return &manager{
+		dao: dao.New(),
+	}
+}
+
+var _ Manager = &manager{}
+
+type manager struct {
+	dao dao.DAO
+}
+
+func (m *manager) Ensure(ctx context.Context, subjectArtifactID, artifactID, size int64, digest, accessoryType string) error {
+	total, err := m.dao.Count(ctx, q.New(q.KeyWords{"ArtifactID": artifactID, "SubjectArtifactID": subjectArtifactID}))
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return nil
+	}
+	_, err = m.dao.Create(ctx, &model.Accessory{
+		ArtifactID:        artifactID,
+		SubjectArtifactID: subjectArtifactID,
+		Type:              accessoryType,
+		Size:              size,
+		Digest:            digest,
+	})
+	// the accessory is created by another request concurrently
+	if err != nil && errors.IsConflictErr(err) {
+		return nil
+	}
+	return err
+}
+
+func (m *manager) Get(ctx context.Context, id int64) (*model.Accessory, error) {
+	return m.dao.Get(ctx, id)
+}
+
+func (m *manager) Count(ctx context.Context, query *q.Query) (int64, error) {
+	return m.dao.Count(ctx, query)
+}
+
+func (m *manager) List(ctx context.Context, query *q.Query) ([]*model.Accessory, error) {
+	return m.dao.List(ctx, query)
+}
+
+func (m *manager) Delete(ctx context.Context, id int64) error {
+	return m.dao.Delete(ctx, id)
+}
+
+func (m *manager) DeleteAccessories(ctx context.Context, query *q.Query) error {
+	_, err := m.dao.DeleteAccessories(ctx, query)
+	return err
+}
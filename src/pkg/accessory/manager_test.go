@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/accessory/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	"github.com/goharbor/harbor/src/testing/pkg/accessory/dao"
+	"github.com/stretchr/testify/suite"
+)
+
+type managerTestSuite struct {
+	suite.Suite
+	mgr *manager
+	dao *dao.DAO
+}
+
+func (m *managerTestSuite) SetupTest() {
+	m.dao = &dao.DAO{}
+	m.mgr = &manager{
+		dao: m.dao,
+	}
+}
+
+func (m *managerTestSuite) TestEnsure() {
+	// the accessory already exists
+	m.dao.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
+	err := m.mgr.Ensure(context.Background(), 1, 2, 100, "sha256:abc", model.TypeCosignSignature)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+
+	// reset the mock
+	m.SetupTest()
+
+	// the accessory doesn't exist
+	m.dao.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+	m.dao.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+	err = m.mgr.Ensure(context.Background(), 1, 2, 100, "sha256:abc", model.TypeCosignSignature)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+
+	// reset the mock
+	m.SetupTest()
+
+	// the accessory is created concurrently
+	m.dao.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+	m.dao.On("Create", mock.Anything, mock.Anything).Return(int64(0), errors.ConflictError(nil))
+	err = m.mgr.Ensure(context.Background(), 1, 2, 100, "sha256:abc", model.TypeCosignSignature)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestGet() {
+	m.dao.On("Get", mock.Anything, mock.Anything).Return(&model.Accessory{ID: 1}, nil)
+	acc, err := m.mgr.Get(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Equal(int64(1), acc.ID)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestCount() {
+	m.dao.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
+	n, err := m.mgr.Count(context.Background(), nil)
+	m.Require().Nil(err)
+	m.Equal(int64(1), n)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestList() {
+	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Accessory{
+		{
+			ID:                1,
+			ArtifactID:        2,
+			SubjectArtifactID: 1,
+			Type:              model.TypeCosignSignature,
+		},
+	}, nil)
+	accs, err := m.mgr.List(context.Background(), nil)
+	m.Require().Nil(err)
+	m.Require().Len(accs, 1)
+	m.Equal(int64(2), accs[0].ArtifactID)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestDelete() {
+	m.dao.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	err := m.mgr.Delete(context.Background(), 1)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestDeleteAccessories() {
+	m.dao.On("DeleteAccessories", mock.Anything, mock.Anything).Return(int64(1), nil)
+	err := m.mgr.DeleteAccessories(context.Background(), nil)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+}
+
+func TestManager(t *testing.T) {
+	suite.Run(t, &managerTestSuite{})
+}
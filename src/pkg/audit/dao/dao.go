@@ -16,6 +16,9 @@ package dao
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
@@ -34,6 +37,10 @@ type DAO interface {
 	Get(ctx context.Context, id int64) (access *model.AuditLog, err error)
 	// Delete the audit log specified by ID
 	Delete(ctx context.Context, id int64) (err error)
+	// Purge the audit logs older than the retention hours, only the operations specified by
+	// "includeOperations" are purged. With "dryRun" set, only returns the count of the logs to be purged.
+	// When "batchSize" is positive, at most "batchSize" logs with the smallest IDs are purged by one call
+	Purge(ctx context.Context, retentionHour int, includeOperations []string, dryRun bool, batchSize int) (total int64, err error)
 }
 
 // New returns an instance of the default DAO
@@ -118,3 +125,42 @@ func (d *dao) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+// Purge ...
+func (d *dao) Purge(ctx context.Context, retentionHour int, includeOperations []string, dryRun bool, batchSize int) (int64, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var ops []interface{}
+	for _, op := range includeOperations {
+		if op = strings.TrimSpace(op); len(op) > 0 {
+			ops = append(ops, strings.ToLower(op))
+		}
+	}
+	// nothing to purge when no operation specified
+	if len(ops) == 0 {
+		return 0, nil
+	}
+	where := fmt.Sprintf(`WHERE op_time < NOW() - ? * interval '1 hour' AND lower(operation) IN (%s)`,
+		orm.ParamPlaceholderForIn(len(ops)))
+	params := append([]interface{}{retentionHour}, ops...)
+	if dryRun {
+		var count int64
+		if err = ormer.Raw("SELECT count(1) FROM audit_log "+where, params...).QueryRow(&count); err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+	sql := "DELETE FROM audit_log " + where
+	if batchSize > 0 {
+		// delete the logs with the smallest IDs to keep the transaction short
+		sql = "DELETE FROM audit_log WHERE id IN (SELECT id FROM audit_log " + where + " ORDER BY id LIMIT ?)"
+		params = append(params, batchSize)
+	}
+	result, err := ormer.Raw(sql, params...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/audit/model"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type daoTestSuite struct {
@@ -157,6 +158,43 @@ func (d *daoTestSuite) TestDelete() {
 	d.Equal(errors.NotFoundCode, e.Code)
 }
 
+func (d *daoTestSuite) TestPurge() {
+	now := time.Now()
+	id, err := d.dao.Create(d.ctx, &model.AuditLog{
+		Operation:    "purge-test",
+		ResourceType: "artifact",
+		Resource:     "library/test-purge",
+		Username:     "admin",
+		OpTime:       now.Add(-48 * time.Hour),
+	})
+	d.Require().Nil(err)
+
+	// no operation specified
+	total, err := d.dao.Purge(d.ctx, 24, nil, false, 0)
+	d.Require().Nil(err)
+	d.Equal(int64(0), total)
+
+	// the operation isn't included
+	total, err = d.dao.Purge(d.ctx, 24, []string{"purge-test-nonexistent"}, true, 0)
+	d.Require().Nil(err)
+	d.Equal(int64(0), total)
+
+	// dry run, the operation is matched case insensitively
+	total, err = d.dao.Purge(d.ctx, 24, []string{"Purge-Test"}, true, 0)
+	d.Require().Nil(err)
+	d.Equal(int64(1), total)
+	_, err = d.dao.Get(d.ctx, id)
+	d.Require().Nil(err)
+
+	// purge in batches
+	total, err = d.dao.Purge(d.ctx, 24, []string{"Purge-Test"}, false, 100)
+	d.Require().Nil(err)
+	d.Equal(int64(1), total)
+	_, err = d.dao.Get(d.ctx, id)
+	d.Require().NotNil(err)
+	d.True(errors.IsNotFoundErr(err))
+}
+
 func TestDaoTestSuite(t *testing.T) {
 	suite.Run(t, &daoTestSuite{})
 }
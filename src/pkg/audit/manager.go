@@ -36,6 +36,10 @@ type Manager interface {
 	Create(ctx context.Context, audit *model.AuditLog) (id int64, err error)
 	// Delete the audit log specified by ID
 	Delete(ctx context.Context, id int64) (err error)
+	// Purge the audit logs older than the retention hours, only the operations specified by
+	// "includeOperations" are purged. With "dryRun" set, only returns the count of the logs to be purged.
+	// When "batchSize" is positive, at most "batchSize" logs with the smallest IDs are purged by one call
+	Purge(ctx context.Context, retentionHour int, includeOperations []string, dryRun bool, batchSize int) (total int64, err error)
 }
 
 // New returns a default implementation of Manager
@@ -73,3 +77,8 @@ func (m *manager) Create(ctx context.Context, audit *model.AuditLog) (int64, err
 func (m *manager) Delete(ctx context.Context, id int64) error {
 	return m.dao.Delete(ctx, id)
 }
+
+// Purge ...
+func (m *manager) Purge(ctx context.Context, retentionHour int, includeOperations []string, dryRun bool, batchSize int) (int64, error) {
+	return m.dao.Purge(ctx, retentionHour, includeOperations, dryRun, batchSize)
+}
@@ -47,6 +47,10 @@ func (f *fakeDao) Delete(ctx context.Context, id int64) error {
 	args := f.Called()
 	return args.Error(0)
 }
+func (f *fakeDao) Purge(ctx context.Context, retentionHour int, includeOperations []string, dryRun bool, batchSize int) (int64, error) {
+	args := f.Called()
+	return int64(args.Int(0)), args.Error(1)
+}
 
 type managerTestSuite struct {
 	suite.Suite
@@ -114,6 +118,14 @@ func (m *managerTestSuite) TestDelete() {
 	m.dao.AssertExpectations(m.T())
 }
 
+func (m *managerTestSuite) TestPurge() {
+	m.dao.On("Purge", mock.Anything).Return(10, nil)
+	total, err := m.mgr.Purge(nil, 24, []string{"create", "delete", "pull"}, true, 0)
+	m.Require().Nil(err)
+	m.dao.AssertExpectations(m.T())
+	m.Equal(int64(10), total)
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, &managerTestSuite{})
 }
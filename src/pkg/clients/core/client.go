@@ -16,7 +16,6 @@ package core
 
 import (
 	"fmt"
-	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"net/http"
 
 	"github.com/goharbor/harbor/src/chartserver"
@@ -34,7 +33,7 @@ type Client interface {
 
 // ArtifactClient defines the methods that an image client should implement
 type ArtifactClient interface {
-	ListAllArtifacts(project, repository string) ([]*modelsv2.Artifact, error)
+	ListAllArtifacts(project, repository string) ([]*Artifact, error)
 	DeleteArtifact(project, repository, digest string) error
 	DeleteArtifactRepository(project, repository string) error
 }
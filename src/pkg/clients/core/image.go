@@ -16,14 +16,34 @@ package core
 
 import (
 	"fmt"
+
 	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/lib/encode/repository"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
-func (c *client) ListAllArtifacts(project, repo string) ([]*modelsv2.Artifact, error) {
+// Artifact is the artifact returned by the artifact API of core, including the scan overview
+type Artifact struct {
+	modelsv2.Artifact
+	ScanOverview map[string]*vuln.NativeReportSummary `json:"scan_overview,omitempty"`
+}
+
+// Severity returns the highest severity among the scan reports of the artifact,
+// vuln.None is returned if the artifact isn't scanned
+func (a *Artifact) Severity() vuln.Severity {
+	severity := vuln.None
+	for _, summary := range a.ScanOverview {
+		if summary != nil && summary.Severity.Code() > severity.Code() {
+			severity = summary.Severity
+		}
+	}
+	return severity
+}
+
+func (c *client) ListAllArtifacts(project, repo string) ([]*Artifact, error) {
 	repo = repository.Encode(repo)
-	url := c.buildURL(fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s/artifacts", project, repo))
-	var arts []*modelsv2.Artifact
+	url := c.buildURL(fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s/artifacts?with_scan_overview=true", project, repo))
+	var arts []*Artifact
 	if err := c.httpclient.GetAndIteratePagination(url, &arts); err != nil {
 		return nil, err
 	}
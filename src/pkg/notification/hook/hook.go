@@ -14,12 +14,16 @@ import (
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/notification/job"
 	job_model "github.com/goharbor/harbor/src/pkg/notification/job/model"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
 )
 
 // Manager send hook
 type Manager interface {
 	StartHook(context.Context, *model.HookEvent, *models.JobData) error
+	// RetryHook re-enqueues the failed notification job with a delay according to the
+	// retry policy carried in the parameters, returns false if no retry is left
+	RetryHook(ctx context.Context, id int64, name string, params map[string]interface{}) (bool, error)
 }
 
 // DefaultManager ...
@@ -45,13 +49,14 @@ func (hm *DefaultManager) StartHook(ctx context.Context, event *model.HookEvent,
 
 	t := time.Now()
 	id, err := hm.jobMgr.Create(ctx, &job_model.Job{
-		PolicyID:     event.PolicyID,
-		EventType:    event.EventType,
-		NotifyType:   event.Target.Type,
-		Status:       cModels.JobPending,
-		CreationTime: t,
-		UpdateTime:   t,
-		JobDetail:    string(payload),
+		PolicyID:      event.PolicyID,
+		EventType:     event.EventType,
+		NotifyType:    event.Target.Type,
+		TargetAddress: event.Target.Address,
+		Status:        cModels.JobPending,
+		CreationTime:  t,
+		UpdateTime:    t,
+		JobDetail:     string(payload),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create the job record for notification based on policy %d: %v", event.PolicyID, err)
@@ -84,3 +89,58 @@ func (hm *DefaultManager) StartHook(ctx context.Context, event *model.HookEvent,
 	}
 	return nil
 }
+
+// RetryHook submits a delayed job with the same parameters and the increased attempt count for
+// the failed notification job, the notification job record is kept and linked to the new job
+func (hm *DefaultManager) RetryHook(ctx context.Context, id int64, name string, params map[string]interface{}) (bool, error) {
+	v, ok := params["retry_policy"].(string)
+	if !ok || len(v) == 0 {
+		return false, nil
+	}
+	retryPolicy := &policy_model.RetryPolicy{}
+	if err := json.Unmarshal([]byte(v), retryPolicy); err != nil {
+		return false, fmt.Errorf("invalid retry policy %s: %v", v, err)
+	}
+	// the attempt count of the failed job, the first job doesn't carry it and the
+	// numeric parameters become float64 after the JSON round trip
+	attempt := 1
+	switch n := params["attempt"].(type) {
+	case float64:
+		attempt = int(n)
+	case int:
+		attempt = n
+	}
+	if attempt > retryPolicy.MaxRetries {
+		return false, nil
+	}
+
+	parameters := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		parameters[k] = v
+	}
+	parameters["attempt"] = attempt + 1
+	data := &models.JobData{
+		Name:       name,
+		Parameters: parameters,
+		Metadata: &models.JobMetadata{
+			JobKind:       cJob.JobKindScheduled,
+			ScheduleDelay: uint64(retryPolicy.Interval(attempt).Seconds()),
+		},
+		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/webhook/%d", config.InternalCoreURL(), id),
+	}
+	jobUUID, err := hm.client.SubmitJob(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to re-enqueue the notification job %d: %v", id, err)
+	}
+	log.Debugf("re-enqueued the notification job %d, attempt: %d, delay: %d seconds", id, attempt+1, data.Metadata.ScheduleDelay)
+
+	if err = hm.jobMgr.Update(ctx, &job_model.Job{
+		ID:         id,
+		UUID:       jobUUID,
+		Status:     cModels.JobScheduled,
+		UpdateTime: time.Now(),
+	}, "UUID", "Status", "UpdateTime"); err != nil {
+		return false, fmt.Errorf("failed to update the notification job %d: %v", id, err)
+	}
+	return true, nil
+}
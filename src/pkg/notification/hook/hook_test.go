@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	cJob "github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/job/models"
+	cModels "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/lib/config"
+	_ "github.com/goharbor/harbor/src/pkg/config/inmemory"
+	"github.com/goharbor/harbor/src/pkg/notification/job"
+	job_model "github.com/goharbor/harbor/src/pkg/notification/job/model"
+	testingjob "github.com/goharbor/harbor/src/testing/job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJobManager struct {
+	job.Manager
+	updated *job_model.Job
+}
+
+func (f *fakeJobManager) Update(ctx context.Context, job *job_model.Job, props ...string) error {
+	f.updated = job
+	return nil
+}
+
+type fakeJobClient struct {
+	testingjob.MockJobClient
+	submitted *models.JobData
+}
+
+func (f *fakeJobClient) SubmitJob(data *models.JobData) (string, error) {
+	f.submitted = data
+	return "uuid", nil
+}
+
+func TestRetryHook(t *testing.T) {
+	config.InitWithSettings(map[string]interface{}{common.CoreURL: "http://core:8080"})
+	jobMgr := &fakeJobManager{}
+	client := &fakeJobClient{}
+	hm := &DefaultManager{jobMgr: jobMgr, client: client}
+
+	// no retry policy
+	retried, err := hm.RetryHook(context.TODO(), 1, "WEBHOOK", map[string]interface{}{"address": "http://a"})
+	require.Nil(t, err)
+	assert.False(t, retried)
+	assert.Nil(t, client.submitted)
+
+	// the first job fails
+	params := map[string]interface{}{
+		"address":      "http://a",
+		"retry_policy": `{"max_retries":2,"backoff":10}`,
+	}
+	retried, err = hm.RetryHook(context.TODO(), 1, "WEBHOOK", params)
+	require.Nil(t, err)
+	assert.True(t, retried)
+	require.NotNil(t, client.submitted)
+	assert.Equal(t, "WEBHOOK", client.submitted.Name)
+	assert.Equal(t, 2, client.submitted.Parameters["attempt"])
+	assert.Equal(t, "http://a", client.submitted.Parameters["address"])
+	assert.Equal(t, cJob.JobKindScheduled, client.submitted.Metadata.JobKind)
+	assert.Equal(t, uint64(10), client.submitted.Metadata.ScheduleDelay)
+	assert.Equal(t, "http://core:8080/service/notifications/jobs/webhook/1", client.submitted.StatusHook)
+	assert.Equal(t, "uuid", jobMgr.updated.UUID)
+	assert.Equal(t, cModels.JobScheduled, jobMgr.updated.Status)
+	_, ok := params["attempt"]
+	assert.False(t, ok)
+
+	// the second job fails
+	client.submitted = nil
+	params["attempt"] = float64(2)
+	retried, err = hm.RetryHook(context.TODO(), 1, "WEBHOOK", params)
+	require.Nil(t, err)
+	assert.True(t, retried)
+	assert.Equal(t, 3, client.submitted.Parameters["attempt"])
+	assert.Equal(t, uint64(20), client.submitted.Metadata.ScheduleDelay)
+
+	// no retry is left
+	client.submitted = nil
+	params["attempt"] = float64(3)
+	retried, err = hm.RetryHook(context.TODO(), 1, "WEBHOOK", params)
+	require.Nil(t, err)
+	assert.False(t, retried)
+	assert.Nil(t, client.submitted)
+}
@@ -36,6 +36,12 @@ type DAO interface {
 
 	// DeleteByPolicyID
 	DeleteByPolicyID(ctx context.Context, policyID int64) error
+
+	// CreateAttempt creates the delivery attempt of the job
+	CreateAttempt(ctx context.Context, attempt *model.Attempt) (int64, error)
+
+	// ListAttempts lists the delivery attempts of the jobs
+	ListAttempts(ctx context.Context, query *q.Query) ([]*model.Attempt, error)
 }
 
 // New creates a default implementation for Dao
@@ -183,3 +189,35 @@ func (d *dao) DeleteByPolicyID(ctx context.Context, policyID int64) error {
 	}
 	return nil
 }
+
+// CreateAttempt ...
+func (d *dao) CreateAttempt(ctx context.Context, attempt *model.Attempt) (int64, error) {
+	if attempt == nil {
+		return 0, errors.New("nil attempt")
+	}
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	id, err := ormer.Insert(attempt)
+	if err != nil {
+		if e := orm.AsForeignKeyError(err, "the notification job %d doesn't exist", attempt.JobID); e != nil {
+			err = e
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// ListAttempts ...
+func (d *dao) ListAttempts(ctx context.Context, query *q.Query) ([]*model.Attempt, error) {
+	attempts := []*model.Attempt{}
+	qs, err := orm.QuerySetter(ctx, &model.Attempt{}, query)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = qs.All(&attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
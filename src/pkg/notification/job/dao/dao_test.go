@@ -70,6 +70,34 @@ func (suite *DaoTestSuite) TestCreate() {
 	suite.NotNil(err)
 }
 
+func (suite *DaoTestSuite) TestAttempts() {
+	_, err := suite.dao.CreateAttempt(orm.Context(), nil)
+	suite.NotNil(err)
+
+	// the job doesn't exist
+	_, err = suite.dao.CreateAttempt(orm.Context(), &model.Attempt{JobID: 1234})
+	suite.NotNil(err)
+
+	_, err = suite.dao.CreateAttempt(orm.Context(), &model.Attempt{
+		JobID:      suite.jobID1,
+		StatusCode: 500,
+		Response:   "internal error",
+	})
+	suite.Require().Nil(err)
+	_, err = suite.dao.CreateAttempt(orm.Context(), &model.Attempt{
+		JobID:      suite.jobID1,
+		StatusCode: 200,
+	})
+	suite.Require().Nil(err)
+
+	attempts, err := suite.dao.ListAttempts(orm.Context(), q.New(q.KeyWords{"JobID": suite.jobID1}))
+	suite.Require().Nil(err)
+	suite.Require().Len(attempts, 2)
+	suite.Equal(500, attempts[0].StatusCode)
+	suite.Equal("internal error", attempts[0].Response)
+	suite.Equal(200, attempts[1].StatusCode)
+}
+
 func (suite *DaoTestSuite) TestDelete() {
 	err := suite.dao.Delete(orm.Context(), 1234)
 	suite.Require().NotNil(err)
@@ -28,6 +28,15 @@ type Manager interface {
 
 	// Count ...
 	Count(ctx context.Context, query *q.Query) (total int64, err error)
+
+	// Get the notification job specified by ID
+	Get(ctx context.Context, id int64) (*model.Job, error)
+
+	// CreateAttempt records the delivery attempt of the notification job
+	CreateAttempt(ctx context.Context, attempt *model.Attempt) (int64, error)
+
+	// ListAttempts lists the delivery attempts of the notification job
+	ListAttempts(ctx context.Context, jobID int64) ([]*model.Attempt, error)
 }
 
 var _ Manager = &manager{}
@@ -67,3 +76,15 @@ func (d *manager) Update(ctx context.Context, job *model.Job, props ...string) e
 func (d *manager) ListJobsGroupByEventType(ctx context.Context, policyID int64) ([]*model.Job, error) {
 	return d.dao.GetLastTriggerJobsGroupByEventType(ctx, policyID)
 }
+
+func (d *manager) Get(ctx context.Context, id int64) (*model.Job, error) {
+	return d.dao.Get(ctx, id)
+}
+
+func (d *manager) CreateAttempt(ctx context.Context, attempt *model.Attempt) (int64, error) {
+	return d.dao.CreateAttempt(ctx, attempt)
+}
+
+func (d *manager) ListAttempts(ctx context.Context, jobID int64) ([]*model.Attempt, error) {
+	return d.dao.ListAttempts(ctx, q.New(q.KeyWords{"JobID": jobID}))
+}
@@ -76,6 +76,39 @@ func (m *managerTestSuite) TestListJobsGroupByEventType() {
 	m.dao.AssertExpectations(m.T())
 }
 
+func (m *managerTestSuite) TestGet() {
+	m.dao.On("Get", mock.Anything, int64(1)).Return(&model.Job{
+		ID:        1,
+		EventType: "test_job",
+	}, nil)
+	job, err := m.mgr.Get(context.Background(), 1)
+	m.Nil(err)
+	m.Equal(int64(1), job.ID)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestCreateAttempt() {
+	m.dao.On("CreateAttempt", mock.Anything, mock.Anything).Return(int64(1), nil)
+	id, err := m.mgr.CreateAttempt(context.Background(), &model.Attempt{JobID: 1})
+	m.Nil(err)
+	m.Equal(int64(1), id)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestListAttempts() {
+	m.dao.On("ListAttempts", mock.Anything, mock.Anything).Return([]*model.Attempt{
+		{
+			ID:         1,
+			JobID:      1,
+			StatusCode: 500,
+		},
+	}, nil)
+	attempts, err := m.mgr.ListAttempts(context.Background(), 1)
+	m.Nil(err)
+	m.Equal(1, len(attempts))
+	m.dao.AssertExpectations(m.T())
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, &managerTestSuite{})
 }
@@ -6,23 +6,39 @@ import (
 )
 
 func init() {
-	orm.RegisterModel(&Job{})
+	orm.RegisterModel(&Job{}, &Attempt{})
 }
 
 // Job is the model for a notification job
 type Job struct {
-	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
-	PolicyID     int64     `orm:"column(policy_id)" json:"policy_id"`
-	EventType    string    `orm:"column(event_type)" json:"event_type"`
-	NotifyType   string    `orm:"column(notify_type)" json:"notify_type"`
-	Status       string    `orm:"column(status)" json:"status"`
-	JobDetail    string    `orm:"column(job_detail)" json:"job_detail"`
-	UUID         string    `orm:"column(job_uuid)" json:"-"`
-	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
-	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time" sort:"default:desc"`
+	ID            int64     `orm:"pk;auto;column(id)" json:"id"`
+	PolicyID      int64     `orm:"column(policy_id)" json:"policy_id"`
+	EventType     string    `orm:"column(event_type)" json:"event_type"`
+	NotifyType    string    `orm:"column(notify_type)" json:"notify_type"`
+	Status        string    `orm:"column(status)" json:"status"`
+	JobDetail     string    `orm:"column(job_detail)" json:"job_detail"`
+	UUID          string    `orm:"column(job_uuid)" json:"-"`
+	TargetAddress string    `orm:"column(target_address)" json:"target_address"`
+	CreationTime  time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime    time.Time `orm:"column(update_time);auto_now" json:"update_time" sort:"default:desc"`
 }
 
 // TableName set table name for ORM.
 func (j *Job) TableName() string {
 	return "notification_job"
 }
+
+// Attempt is the model for a delivery attempt of the notification job
+type Attempt struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id" sort:"default"`
+	JobID        int64     `orm:"column(job_id)" json:"job_id"`
+	StatusCode   int       `orm:"column(status_code)" json:"status_code"`
+	Response     string    `orm:"column(response)" json:"response"`
+	Error        string    `orm:"column(error)" json:"error"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName set table name for ORM.
+func (a *Attempt) TableName() string {
+	return "notification_job_attempt"
+}
@@ -62,12 +62,22 @@ func initSupportedNotifyType() {
 		event.TopicScanningCompleted,
 		event.TopicReplication,
 		event.TopicTagRetention,
+		event.TopicCreateProject,
+		event.TopicDeleteProject,
+		event.TopicAddMember,
+		event.TopicUpdateMemberRole,
+		event.TopicRemoveMember,
+		event.TopicCreateRobot,
+		event.TopicRobotExpired,
+		event.TopicCreatePolicy,
+		event.TopicUpdatePolicy,
+		event.TopicDeletePolicy,
 	}
 	for _, eventType := range eventTypes {
 		SupportedEventTypes[eventType] = struct{}{}
 	}
 
-	notifyTypes := []string{notifier_model.NotifyTypeHTTP, notifier_model.NotifyTypeSlack}
+	notifyTypes := []string{notifier_model.NotifyTypeHTTP, notifier_model.NotifyTypeSlack, notifier_model.NotifyTypeEmail}
 	for _, notifyType := range notifyTypes {
 		SupportedNotifyTypes[notifyType] = struct{}{}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"time"
 
 	commonhttp "github.com/goharbor/harbor/src/common/http"
@@ -145,6 +146,8 @@ func (m *manager) Test(policy *model.Policy) error {
 		switch target.Type {
 		case notifier_model.NotifyTypeHTTP, notifier_model.NotifyTypeSlack:
 			return m.policyHTTPTest(target.Address, target.SkipCertVerify)
+		case notifier_model.NotifyTypeEmail:
+			return m.policyEmailTest(target.Address)
 		default:
 			return fmt.Errorf("invalid policy target type: %s", target.Type)
 		}
@@ -152,6 +155,15 @@ func (m *manager) Test(policy *model.Policy) error {
 	return nil
 }
 
+// policyEmailTest only validates the recipients as the email server is configured globally
+func (m *manager) policyEmailTest(address string) error {
+	if _, err := mail.ParseAddressList(address); err != nil {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("invalid email recipients %s: %v", address, err)
+	}
+	return nil
+}
+
 func (m *manager) policyHTTPTest(address string, skipCertVerify bool) error {
 	req, err := http.NewRequest(http.MethodPost, address, nil)
 	if err != nil {
@@ -111,6 +111,29 @@ func (m *managerTestSuite) TestGetRelatedPolices() {
 	m.dao.AssertExpectations(m.T())
 }
 
+func (m *managerTestSuite) TestTestEmail() {
+	err := m.mgr.Test(&model.Policy{
+		Targets: []model.EventTarget{
+			{
+				Type:    "email",
+				Address: "admin@example.com, dev@example.com",
+			},
+		},
+	})
+	m.Nil(err)
+
+	err = m.mgr.Test(&model.Policy{
+		Targets: []model.EventTarget{
+			{
+				Type:    "email",
+				Address: "invalid-address",
+			},
+		},
+	})
+	m.NotNil(err)
+	m.True(errors.IsErr(err, errors.BadRequestCode))
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, &managerTestSuite{})
 }
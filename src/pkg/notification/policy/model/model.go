@@ -2,10 +2,20 @@ package model
 
 import (
 	"encoding/json"
-	"github.com/astaxie/beego/orm"
 	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/lib/encrypt"
+	"github.com/goharbor/harbor/src/lib/errors"
 )
 
+// encryptor encrypts the signing secrets of the targets, declared as variable for testing
+var encryptor = encrypt.Instance
+
+// SystemLevelProjectID is the project ID of the system level policies, which receive the events
+// not related with a single project, e.g. the project creation and the system robot accounts
+const SystemLevelProjectID int64 = 0
+
 func init() {
 	orm.RegisterModel(&Policy{})
 }
@@ -24,6 +34,9 @@ type Policy struct {
 	CreationTime time.Time     `orm:"column(creation_time);auto_now_add" json:"creation_time" sort:"default:desc"`
 	UpdateTime   time.Time     `orm:"column(update_time);auto_now_add" json:"update_time"`
 	Enabled      bool          `orm:"column(enabled)" json:"enabled"`
+	// RetryPolicyDB is empty if the retry policy isn't set, the deliveries are retried by the jobservice then
+	RetryPolicyDB string       `orm:"column(retry_policy)" json:"-"`
+	RetryPolicy   *RetryPolicy `orm:"-" json:"retry_policy,omitempty"`
 }
 
 // TableName set table name for ORM.
@@ -34,7 +47,20 @@ func (w *Policy) TableName() string {
 // ConvertToDBModel convert struct data in notification policy to DB model data
 func (w *Policy) ConvertToDBModel() error {
 	if len(w.Targets) != 0 {
-		targets, err := json.Marshal(w.Targets)
+		// the signing secrets are encrypted at rest, keep the targets in memory as they are
+		encrypted := make([]EventTarget, len(w.Targets))
+		for i, target := range w.Targets {
+			target.ClearSigningSecret = false
+			if len(target.SigningSecret) > 0 {
+				secret, err := encryptor().Encrypt(target.SigningSecret)
+				if err != nil {
+					return err
+				}
+				target.SigningSecret = secret
+			}
+			encrypted[i] = target
+		}
+		targets, err := json.Marshal(encrypted)
 		if err != nil {
 			return err
 		}
@@ -47,6 +73,14 @@ func (w *Policy) ConvertToDBModel() error {
 		}
 		w.EventTypesDB = string(eventTypes)
 	}
+	w.RetryPolicyDB = ""
+	if w.RetryPolicy != nil {
+		retryPolicy, err := json.Marshal(w.RetryPolicy)
+		if err != nil {
+			return err
+		}
+		w.RetryPolicyDB = string(retryPolicy)
+	}
 
 	return nil
 }
@@ -60,6 +94,15 @@ func (w *Policy) ConvertFromDBModel() error {
 			return err
 		}
 	}
+	for i := range targets {
+		if len(targets[i].SigningSecret) > 0 {
+			secret, err := encryptor().Decrypt(targets[i].SigningSecret)
+			if err != nil {
+				return err
+			}
+			targets[i].SigningSecret = secret
+		}
+	}
 	w.Targets = targets
 
 	types := []string{}
@@ -71,13 +114,88 @@ func (w *Policy) ConvertFromDBModel() error {
 	}
 	w.EventTypes = types
 
+	w.RetryPolicy = nil
+	if len(w.RetryPolicyDB) != 0 {
+		retryPolicy := &RetryPolicy{}
+		if err := json.Unmarshal([]byte(w.RetryPolicyDB), retryPolicy); err != nil {
+			return err
+		}
+		w.RetryPolicy = retryPolicy
+	}
+
 	return nil
 }
 
+// const definitions of the limitations of the retry policy
+const (
+	// MaxRetries is the max count of the retries can be set
+	MaxRetries = 20
+	// MaxBackoff is the max interval(in seconds) between the retries can be set
+	MaxBackoff = 24 * 3600
+)
+
+// RetryPolicy defines how the failed deliveries of the policy are retried, the interval
+// between the retries starts from Backoff and doubles for each retry until it reaches MaxBackoff
+type RetryPolicy struct {
+	// MaxRetries is the count of the retries after the first attempt fails
+	MaxRetries int `json:"max_retries"`
+	// Backoff is the interval(in seconds) before the first retry
+	Backoff int `json:"backoff"`
+	// MaxBackoff is the upper limit(in seconds) of the interval, 0 means MaxBackoff
+	MaxBackoff int `json:"max_backoff,omitempty"`
+}
+
+// Validate the retry policy
+func (r *RetryPolicy) Validate() error {
+	if r.MaxRetries < 0 || r.MaxRetries > MaxRetries {
+		return errors.BadRequestError(nil).WithMessage("the max retries must be between 0 and %d", MaxRetries)
+	}
+	if r.Backoff < 1 || r.Backoff > MaxBackoff {
+		return errors.BadRequestError(nil).WithMessage("the backoff must be between 1 and %d seconds", MaxBackoff)
+	}
+	if r.MaxBackoff != 0 && (r.MaxBackoff < r.Backoff || r.MaxBackoff > MaxBackoff) {
+		return errors.BadRequestError(nil).WithMessage("the max backoff must be between the backoff and %d seconds", MaxBackoff)
+	}
+	return nil
+}
+
+// Interval returns the interval before the nth(starts from 1) retry
+func (r *RetryPolicy) Interval(n int) time.Duration {
+	max := r.MaxBackoff
+	if max == 0 {
+		max = MaxBackoff
+	}
+	interval := r.Backoff
+	for i := 1; i < n && interval < max; i++ {
+		interval *= 2
+	}
+	if interval > max {
+		interval = max
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// const definitions of the payload formats of the http target
+const (
+	// PayloadFormatDefault is the harbor's own payload format
+	PayloadFormatDefault = "Default"
+	// PayloadFormatCloudEventsStructured sends the event as a CloudEvents 1.0 JSON document
+	PayloadFormatCloudEventsStructured = "CloudEventsStructured"
+	// PayloadFormatCloudEventsBinary sends the event data as body and the CloudEvents attributes as "ce-" headers
+	PayloadFormatCloudEventsBinary = "CloudEventsBinary"
+)
+
 // EventTarget defines the structure of target a notification send to
 type EventTarget struct {
 	Type           string `json:"type"`
 	Address        string `json:"address"`
 	AuthHeader     string `json:"auth_header,omitempty"`
 	SkipCertVerify bool   `json:"skip_cert_verify"`
+	// PayloadFormat is only effective for the http target, empty means PayloadFormatDefault
+	PayloadFormat string `json:"payload_format,omitempty"`
+	// SigningSecret is used to sign the requests sent to the http target with HMAC-SHA256 if it is set
+	SigningSecret string `json:"signing_secret,omitempty"`
+	// ClearSigningSecret removes the existing signing secret when updating the policy, it's only
+	// carried by the update request and never persisted
+	ClearSigningSecret bool `json:"clear_signing_secret,omitempty"`
 }
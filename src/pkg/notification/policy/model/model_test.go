@@ -1,9 +1,14 @@
 package model
 
 import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/encrypt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"testing"
 )
 
 func TestPolicy_ConvertFromDBModel(t *testing.T) {
@@ -98,3 +103,84 @@ func TestPolicy_ConvertToDBModel(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicy_SigningSecret(t *testing.T) {
+	keyFile, err := ioutil.TempFile("", "key")
+	require.Nil(t, err)
+	defer os.Remove(keyFile.Name())
+	_, err = keyFile.WriteString("9TXCcHgNAAp1aSHh")
+	require.Nil(t, err)
+	require.Nil(t, keyFile.Close())
+
+	enc := encryptor
+	defer func() {
+		encryptor = enc
+	}()
+	encryptor = func() encrypt.Encryptor {
+		return encrypt.NewAESEncryptor(encrypt.NewFileKeyProvider(keyFile.Name()))
+	}
+
+	policy := &Policy{
+		Targets: []EventTarget{
+			{
+				Type:          "http",
+				Address:       "http://127.0.0.1",
+				SigningSecret: "secret",
+			},
+		},
+	}
+	require.Nil(t, policy.ConvertToDBModel())
+	// the secret is encrypted in the DB model but kept in the targets
+	assert.NotContains(t, policy.TargetsDB, `"signing_secret":"secret"`)
+	assert.Contains(t, policy.TargetsDB, `"signing_secret":`)
+	assert.Equal(t, "secret", policy.Targets[0].SigningSecret)
+
+	loaded := &Policy{TargetsDB: policy.TargetsDB}
+	require.Nil(t, loaded.ConvertFromDBModel())
+	require.Len(t, loaded.Targets, 1)
+	assert.Equal(t, "secret", loaded.Targets[0].SigningSecret)
+}
+
+func TestPolicy_RetryPolicy(t *testing.T) {
+	policy := &Policy{
+		RetryPolicy: &RetryPolicy{
+			MaxRetries: 3,
+			Backoff:    10,
+			MaxBackoff: 60,
+		},
+	}
+	require.Nil(t, policy.ConvertToDBModel())
+	assert.Equal(t, `{"max_retries":3,"backoff":10,"max_backoff":60}`, policy.RetryPolicyDB)
+
+	p := &Policy{RetryPolicyDB: policy.RetryPolicyDB}
+	require.Nil(t, p.ConvertFromDBModel())
+	assert.Equal(t, policy.RetryPolicy, p.RetryPolicy)
+
+	// the retry policy is removed
+	policy.RetryPolicy = nil
+	require.Nil(t, policy.ConvertToDBModel())
+	assert.Empty(t, policy.RetryPolicyDB)
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	assert.Nil(t, (&RetryPolicy{MaxRetries: 0, Backoff: 1}).Validate())
+	assert.Nil(t, (&RetryPolicy{MaxRetries: 3, Backoff: 10, MaxBackoff: 60}).Validate())
+	assert.NotNil(t, (&RetryPolicy{MaxRetries: -1, Backoff: 10}).Validate())
+	assert.NotNil(t, (&RetryPolicy{MaxRetries: MaxRetries + 1, Backoff: 10}).Validate())
+	assert.NotNil(t, (&RetryPolicy{MaxRetries: 3, Backoff: 0}).Validate())
+	assert.NotNil(t, (&RetryPolicy{MaxRetries: 3, Backoff: 10, MaxBackoff: 5}).Validate())
+	assert.NotNil(t, (&RetryPolicy{MaxRetries: 3, Backoff: 10, MaxBackoff: MaxBackoff + 1}).Validate())
+}
+
+func TestRetryPolicy_Interval(t *testing.T) {
+	r := &RetryPolicy{MaxRetries: 5, Backoff: 10, MaxBackoff: 60}
+	assert.Equal(t, 10*time.Second, r.Interval(1))
+	assert.Equal(t, 20*time.Second, r.Interval(2))
+	assert.Equal(t, 40*time.Second, r.Interval(3))
+	assert.Equal(t, 60*time.Second, r.Interval(4))
+	assert.Equal(t, 60*time.Second, r.Interval(5))
+
+	// no upper limit specified
+	r = &RetryPolicy{MaxRetries: 20, Backoff: 3600}
+	assert.Equal(t, time.Duration(MaxBackoff)*time.Second, r.Interval(20))
+}
@@ -0,0 +1,113 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature signs the webhook requests sent by Harbor and verifies them on the receiver side.
+//
+// When a signing secret is configured for the webhook target, Harbor adds the header
+//
+//	X-Harbor-Signature: t=<unix timestamp>,v1=<hex encoded HMAC-SHA256>
+//
+// to the request, the HMAC is calculated with the signing secret over the string "<timestamp>.<request body>".
+// The receiver should recompute the HMAC with the shared secret, compare it in constant time and reject
+// the requests whose timestamp is too old to prevent the replay attacks, e.g.
+//
+//	body, _ := ioutil.ReadAll(r.Body)
+//	if err := signature.Verify(secret, r.Header.Get(signature.Header), body, 5*time.Minute); err != nil {
+//		w.WriteHeader(http.StatusUnauthorized)
+//		return
+//	}
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+const (
+	// Header is the name of the header carries the signature
+	Header = "X-Harbor-Signature"
+	// the version of the signature scheme
+	schemeV1 = "v1"
+)
+
+// now is declared as variable for testing
+var now = time.Now
+
+// Sign returns the value of the signature header for the body signed at the timestamp
+func Sign(secret string, body []byte, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,%s=%s", ts, schemeV1, compute(secret, ts, body))
+}
+
+// Verify checks the value of the signature header against the body, the signature
+// is considered invalid if its timestamp differs from now more than the tolerance.
+// The tolerance check is skipped if the tolerance isn't positive
+func Verify(secret string, header string, body []byte, tolerance time.Duration) error {
+	var (
+		ts         string
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case schemeV1:
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if len(ts) == 0 || len(signatures) == 0 {
+		return errors.New("malformed signature header")
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid timestamp of signature")
+	}
+	if tolerance > 0 {
+		diff := now().Sub(time.Unix(unix, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return errors.Errorf("the timestamp of signature is out of the tolerance %s", tolerance)
+		}
+	}
+
+	expected := compute(secret, ts, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return nil
+		}
+	}
+	return errors.New("signature mismatch")
+}
+
+func compute(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type signatureTestSuite struct {
+	suite.Suite
+	timestamp time.Time
+}
+
+func (s *signatureTestSuite) SetupTest() {
+	s.timestamp = time.Unix(1609459200, 0)
+	now = func() time.Time {
+		return s.timestamp.Add(time.Minute)
+	}
+}
+
+func (s *signatureTestSuite) TearDownTest() {
+	now = time.Now
+}
+
+func (s *signatureTestSuite) TestSign() {
+	header := Sign("secret", []byte(`{"type":"PUSH_ARTIFACT"}`), s.timestamp)
+	s.Equal("t=1609459200,v1=57a09e14c8e71e200f4feb77ca4c5144dc43ff7d43dc57de1cd77bc7af58b346", header)
+}
+
+func (s *signatureTestSuite) TestVerify() {
+	body := []byte(`{"type":"PUSH_ARTIFACT"}`)
+	header := Sign("secret", body, s.timestamp)
+
+	s.Nil(Verify("secret", header, body, 5*time.Minute))
+	// skip the tolerance check
+	s.Nil(Verify("secret", header, body, 0))
+	// wrong secret
+	s.Error(Verify("wrong", header, body, 5*time.Minute))
+	// tampered body
+	s.Error(Verify("secret", header, []byte(`{"type":"DELETE_ARTIFACT"}`), 5*time.Minute))
+	// expired
+	s.Error(Verify("secret", header, body, 30*time.Second))
+	// malformed
+	s.Error(Verify("secret", "", body, 5*time.Minute))
+	s.Error(Verify("secret", "t=abc,v1=abc", body, 5*time.Minute))
+	// multiple signatures, e.g. during the secret rotation
+	s.Nil(Verify("secret", header+",v1=0123", body, 5*time.Minute))
+}
+
+func TestSignatureTestSuite(t *testing.T) {
+	suite.Run(t, &signatureTestSuite{})
+}
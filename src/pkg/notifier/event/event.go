@@ -38,19 +38,27 @@ type Metadata interface {
 
 // HookMetaData defines hook notification related event data
 type HookMetaData struct {
+	ProjectID int64
 	PolicyID  int64
 	EventType string
 	Target    *policy_model.EventTarget
-	Payload   *model.Payload
+	// TargetIndex is the index of the target in the targets of the policy
+	TargetIndex int
+	Payload     *model.Payload
+	// RetryPolicy of the policy, nil means the deliveries are retried by the jobservice
+	RetryPolicy *policy_model.RetryPolicy
 }
 
 // Resolve hook metadata into hook event
 func (h *HookMetaData) Resolve(evt *Event) error {
 	data := &model.HookEvent{
-		PolicyID:  h.PolicyID,
-		EventType: h.EventType,
-		Target:    h.Target,
-		Payload:   h.Payload,
+		ProjectID:   h.ProjectID,
+		PolicyID:    h.PolicyID,
+		EventType:   h.EventType,
+		Target:      h.Target,
+		TargetIndex: h.TargetIndex,
+		Payload:     h.Payload,
+		RetryPolicy: h.RetryPolicy,
 	}
 
 	evt.Topic = h.Target.Type
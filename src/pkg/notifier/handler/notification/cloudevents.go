@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/google/uuid"
+)
+
+const (
+	// CloudEventsSpecVersion is the version of the CloudEvents specification the events comply with
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsContentType is the content type of the structured mode CloudEvents
+	CloudEventsContentType = "application/cloudevents+json"
+	// the prefix of the types of the CloudEvents
+	cloudEventsTypePrefix = "harbor."
+	// the header prefix of the CloudEvents attributes in binary mode
+	cloudEventsHeaderPrefix = "ce-"
+)
+
+// extEndpoint is declared as variable for testing
+var extEndpoint = config.ExtEndpoint
+
+// cloudEventsTypes maps the harbor event types to the stable CloudEvents types, the
+// receivers may route the events by the type so the values must not be changed
+var cloudEventsTypes = map[string]string{
+	"PUSH_ARTIFACT":      "harbor.artifact.pushed",
+	"PULL_ARTIFACT":      "harbor.artifact.pulled",
+	"DELETE_ARTIFACT":    "harbor.artifact.deleted",
+	"UPLOAD_CHART":       "harbor.chart.uploaded",
+	"DOWNLOAD_CHART":     "harbor.chart.downloaded",
+	"DELETE_CHART":       "harbor.chart.deleted",
+	"QUOTA_EXCEED":       "harbor.quota.exceeded",
+	"QUOTA_WARNING":      "harbor.quota.warned",
+	"SCANNING_FAILED":    "harbor.scan.failed",
+	"SCANNING_STOPPED":   "harbor.scan.stopped",
+	"SCANNING_COMPLETED": "harbor.scan.completed",
+	"REPLICATION":        "harbor.replication.status.changed",
+	"TAG_RETENTION":      "harbor.tag_retention.finished",
+	"CREATE_PROJECT":     "harbor.project.created",
+	"DELETE_PROJECT":     "harbor.project.deleted",
+	"ADD_MEMBER":         "harbor.member.added",
+	"UPDATE_MEMBER_ROLE": "harbor.member.role_updated",
+	"REMOVE_MEMBER":      "harbor.member.removed",
+	"CREATE_ROBOT":       "harbor.robot.created",
+	"ROBOT_EXPIRED":      "harbor.robot.expired",
+	"CREATE_POLICY":      "harbor.policy.created",
+	"UPDATE_POLICY":      "harbor.policy.updated",
+	"DELETE_POLICY":      "harbor.policy.deleted",
+}
+
+// CloudEvent is the event in CloudEvents 1.0 format
+type CloudEvent struct {
+	SpecVersion     string           `json:"specversion"`
+	ID              string           `json:"id"`
+	Source          string           `json:"source"`
+	Type            string           `json:"type"`
+	Subject         string           `json:"subject,omitempty"`
+	Time            string           `json:"time"`
+	DataContentType string           `json:"datacontenttype"`
+	Operator        string           `json:"operator,omitempty"`
+	Data            *model.EventData `json:"data,omitempty"`
+}
+
+// CloudEventsType returns the CloudEvents type of the harbor event type
+func CloudEventsType(eventType string) string {
+	if t, ok := cloudEventsTypes[eventType]; ok {
+		return t
+	}
+	return cloudEventsTypePrefix + strings.ToLower(eventType)
+}
+
+// NewCloudEvent converts the hook event to the CloudEvent
+func NewCloudEvent(event *model.HookEvent) (*CloudEvent, error) {
+	if event == nil || event.Payload == nil {
+		return nil, fmt.Errorf("empty payload")
+	}
+	payload := event.Payload
+	// the source identifies the producer of the event, it's the project of the Harbor instance
+	// and doesn't change with the policies by which the event is delivered
+	ext, err := extEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	// the same event delivered to the different targets or retried shares the same ID,
+	// so the receivers are able to de-duplicate the events by the source and ID
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	ce := &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              uuid.NewSHA1(uuid.NameSpaceURL, data).String(),
+		Source:          fmt.Sprintf("%s/projects/%d", strings.TrimSuffix(ext, "/"), event.ProjectID),
+		Type:            CloudEventsType(payload.Type),
+		Time:            time.Unix(payload.OccurAt, 0).UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+		Operator:        payload.Operator,
+		Data:            payload.EventData,
+	}
+	if payload.EventData != nil && payload.EventData.Repository != nil {
+		ce.Subject = payload.EventData.Repository.RepoFullName
+		// the subject of the event on the single artifact is the artifact reference
+		if len(payload.EventData.Resources) == 1 && len(payload.EventData.Resources[0].Digest) > 0 {
+			ce.Subject = fmt.Sprintf("%s@%s", ce.Subject, payload.EventData.Resources[0].Digest)
+		}
+	} else if payload.EventData != nil && payload.EventData.Project != nil {
+		ce.Subject = payload.EventData.Project.Name
+	}
+	return ce, nil
+}
+
+// Structured returns the body in structured content mode
+func (c *CloudEvent) Structured() (string, error) {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Binary returns the body and the headers of the attributes in binary content mode
+func (c *CloudEvent) Binary() (string, map[string]string, error) {
+	body := []byte{}
+	if c.Data != nil {
+		var err error
+		if body, err = json.Marshal(c.Data); err != nil {
+			return "", nil, err
+		}
+	}
+	headers := map[string]string{
+		cloudEventsHeaderPrefix + "specversion": c.SpecVersion,
+		cloudEventsHeaderPrefix + "id":          c.ID,
+		cloudEventsHeaderPrefix + "source":      c.Source,
+		cloudEventsHeaderPrefix + "type":        c.Type,
+		cloudEventsHeaderPrefix + "time":        c.Time,
+	}
+	if len(c.Subject) > 0 {
+		headers[cloudEventsHeaderPrefix+"subject"] = c.Subject
+	}
+	if len(c.Operator) > 0 {
+		headers[cloudEventsHeaderPrefix+"operator"] = c.Operator
+	}
+	return string(body), headers, nil
+}
@@ -0,0 +1,153 @@
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	eventModel "github.com/goharbor/harbor/src/controller/event/model"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockExtEndpoint() func() {
+	original := extEndpoint
+	extEndpoint = func() (string, error) {
+		return "https://harbor.example.com", nil
+	}
+	return func() {
+		extEndpoint = original
+	}
+}
+
+func newPushHookEvent(payloadFormat string) *model.HookEvent {
+	return &model.HookEvent{
+		ProjectID: 1,
+		PolicyID:  2,
+		EventType: "PUSH_ARTIFACT",
+		Target: &policy_model.EventTarget{
+			Type:          "http",
+			Address:       "http://127.0.0.1:8080",
+			PayloadFormat: payloadFormat,
+		},
+		Payload: &model.Payload{
+			Type:     "PUSH_ARTIFACT",
+			OccurAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			Operator: "admin",
+			EventData: &model.EventData{
+				Resources: []*model.Resource{
+					{
+						Digest: "sha256:abc",
+						Tag:    "v1.0",
+					},
+				},
+				Repository: &model.Repository{
+					Name:         "debian",
+					Namespace:    "library",
+					RepoFullName: "library/debian",
+				},
+			},
+		},
+	}
+}
+
+func TestCloudEventsType(t *testing.T) {
+	assert.Equal(t, "harbor.artifact.pushed", CloudEventsType("PUSH_ARTIFACT"))
+	assert.Equal(t, "harbor.replication.status.changed", CloudEventsType("REPLICATION"))
+	assert.Equal(t, "harbor.member.added", CloudEventsType("ADD_MEMBER"))
+	assert.Equal(t, "harbor.unknown_event", CloudEventsType("UNKNOWN_EVENT"))
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	defer mockExtEndpoint()()
+
+	_, err := NewCloudEvent(&model.HookEvent{})
+	assert.NotNil(t, err)
+
+	ce, err := NewCloudEvent(newPushHookEvent(policy_model.PayloadFormatCloudEventsStructured))
+	require.Nil(t, err)
+	assert.Equal(t, "1.0", ce.SpecVersion)
+	assert.NotEmpty(t, ce.ID)
+	assert.Equal(t, "https://harbor.example.com/projects/1", ce.Source)
+	assert.Equal(t, "harbor.artifact.pushed", ce.Type)
+	assert.Equal(t, "library/debian@sha256:abc", ce.Subject)
+	assert.Equal(t, "2021-01-01T00:00:00Z", ce.Time)
+	assert.Equal(t, "admin", ce.Operator)
+
+	// the event delivered by another policy or to another target keeps the source and ID
+	event := newPushHookEvent(policy_model.PayloadFormatCloudEventsBinary)
+	event.PolicyID = 3
+	event.TargetIndex = 1
+	another, err := NewCloudEvent(event)
+	require.Nil(t, err)
+	assert.Equal(t, ce.ID, another.ID)
+	assert.Equal(t, ce.Source, another.Source)
+
+	// the different event gets a different ID
+	event.Payload.OccurAt++
+	another, err = NewCloudEvent(event)
+	require.Nil(t, err)
+	assert.NotEqual(t, ce.ID, another.ID)
+
+	// the subject of the project level event is the project name
+	ce, err = NewCloudEvent(&model.HookEvent{
+		ProjectID: 1,
+		PolicyID:  2,
+		EventType: "ADD_MEMBER",
+		Payload: &model.Payload{
+			Type:    "ADD_MEMBER",
+			OccurAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			EventData: &model.EventData{
+				Project: &eventModel.Project{
+					ID:   1,
+					Name: "library",
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "harbor.member.added", ce.Type)
+	assert.Equal(t, "library", ce.Subject)
+}
+
+func TestHTTPHandler_Convert(t *testing.T) {
+	defer mockExtEndpoint()()
+
+	handler := &HTTPHandler{}
+
+	// default
+	body, contentType, headers, err := handler.convert(newPushHookEvent(""))
+	require.Nil(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Nil(t, headers)
+	payload := &model.Payload{}
+	require.Nil(t, json.Unmarshal([]byte(body), payload))
+	assert.Equal(t, "PUSH_ARTIFACT", payload.Type)
+
+	// structured
+	body, contentType, headers, err = handler.convert(newPushHookEvent(policy_model.PayloadFormatCloudEventsStructured))
+	require.Nil(t, err)
+	assert.Equal(t, CloudEventsContentType, contentType)
+	assert.Nil(t, headers)
+	ce := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal([]byte(body), &ce))
+	assert.Equal(t, "1.0", ce["specversion"])
+	assert.Equal(t, "harbor.artifact.pushed", ce["type"])
+	assert.Equal(t, "application/json", ce["datacontenttype"])
+	assert.NotNil(t, ce["data"])
+
+	// binary
+	body, contentType, headers, err = handler.convert(newPushHookEvent(policy_model.PayloadFormatCloudEventsBinary))
+	require.Nil(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "1.0", headers["ce-specversion"])
+	assert.Equal(t, "harbor.artifact.pushed", headers["ce-type"])
+	assert.Equal(t, "https://harbor.example.com/projects/1", headers["ce-source"])
+	assert.Equal(t, "library/debian@sha256:abc", headers["ce-subject"])
+	assert.NotEmpty(t, headers["ce-id"])
+	data := &model.EventData{}
+	require.Nil(t, json.Unmarshal([]byte(body), data))
+	assert.Equal(t, "library/debian", data.Repository.RepoFullName)
+}
@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+const (
+	// EmailBodyTemplate defines the email message template, the sections are rendered
+	// according to the data carried by the event, e.g. the resources of the push/scan events,
+	// the replication info of the replication events, the details of the quota events and
+	// the project, member, robot account and policy of the project level events
+	EmailBodyTemplate = `<html>
+<body>
+<h3>Harbor webhook events</h3>
+<p><b>event_type:</b> {{.Type}}</p>
+<p><b>occur_at:</b> {{.OccurAt}}</p>
+<p><b>operator:</b> {{.Operator}}</p>
+{{- with .EventData}}
+{{- with .Repository}}
+<p><b>repository:</b> {{.RepoFullName}}</p>
+{{- end}}
+{{- if .Resources}}
+<p><b>resources:</b></p>
+<ul>
+{{- range .Resources}}
+<li>{{if .Tag}}{{.Tag}} {{end}}{{.Digest}}{{if .ResourceURL}} ({{.ResourceURL}}){{end}}
+{{- range $mimeType, $summary := .ScanOverview}}
+<br/>scan overview: {{$summary}}
+{{- end}}
+</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Replication}}
+<p><b>replication:</b> {{.JobStatus}}, triggered by {{.TriggerType}}</p>
+{{- with .SrcResource}}
+<p><b>source:</b> {{.RegistryName}} {{.Endpoint}} {{.Namespace}}</p>
+{{- end}}
+{{- with .DestResource}}
+<p><b>destination:</b> {{.RegistryName}} {{.Endpoint}} {{.Namespace}}</p>
+{{- end}}
+{{- if .SuccessfulArtifact}}
+<p><b>successful artifacts:</b></p>
+<ul>
+{{- range .SuccessfulArtifact}}
+<li>{{.NameAndTag}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .FailedArtifact}}
+<p><b>failed artifacts:</b></p>
+<ul>
+{{- range .FailedArtifact}}
+<li>{{.NameAndTag}}: {{.FailReason}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- with .Project}}
+<p><b>project:</b> {{.Name}}</p>
+{{- end}}
+{{- with .Member}}
+<p><b>member:</b> {{.EntityName}}{{if .Role}}, role {{.Role}}{{end}}</p>
+{{- end}}
+{{- with .Robot}}
+<p><b>robot account:</b> {{.Name}}</p>
+{{- end}}
+{{- with .Policy}}
+<p><b>{{.Type}} policy:</b> {{.ID}}{{if .Name}} {{.Name}}{{end}}</p>
+{{- end}}
+{{- range $key, $value := .Custom}}
+<p><b>{{$key}}:</b> {{$value}}</p>
+{{- end}}
+{{- end}}
+</body>
+</html>`
+)
+
+var emailTemplate = template.Must(template.New("email").Parse(EmailBodyTemplate))
+
+// EmailHandler preprocess event data to email and start the hook processing
+type EmailHandler struct {
+}
+
+// Name ...
+func (e *EmailHandler) Name() string {
+	return "Email"
+}
+
+// Handle handles event to email
+func (e *EmailHandler) Handle(ctx context.Context, value interface{}) error {
+	if value == nil {
+		return errors.New("EmailHandler cannot handle nil value")
+	}
+
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil {
+		return errors.New("invalid notification email event")
+	}
+
+	return e.process(ctx, event)
+}
+
+// IsStateful ...
+func (e *EmailHandler) IsStateful() bool {
+	return false
+}
+
+func (e *EmailHandler) process(ctx context.Context, event *model.HookEvent) error {
+	j := &models.JobData{
+		Metadata: &models.JobMetadata{
+			JobKind: job.KindGeneric,
+		},
+	}
+	// Create an emailJob to send message to the recipients
+	j.Name = job.EmailJob
+
+	subject, message, err := e.convert(event.Payload)
+	if err != nil {
+		return fmt.Errorf("convert payload to email message failed: %v", err)
+	}
+
+	// the address of the email target is the recipients separated by comma
+	j.Parameters = map[string]interface{}{
+		"subject": subject,
+		"payload": message,
+		"address": event.Target.Address,
+	}
+	return notification.HookManager.StartHook(ctx, event, j)
+}
+
+func (e *EmailHandler) convert(payload *model.Payload) (string, string, error) {
+	if payload == nil {
+		return "", "", errors.New("empty payload")
+	}
+	subject := fmt.Sprintf("[Harbor] %s", payload.Type)
+	if payload.EventData != nil && payload.EventData.Repository != nil {
+		subject = fmt.Sprintf("%s: %s", subject, payload.EventData.Repository.RepoFullName)
+	}
+
+	data := struct {
+		*model.Payload
+		OccurAt string
+	}{
+		Payload: payload,
+		OccurAt: time.Unix(payload.OccurAt, 0).UTC().Format(time.RFC1123),
+	}
+	var buf bytes.Buffer
+	if err := emailTemplate.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
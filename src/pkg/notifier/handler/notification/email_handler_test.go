@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	evtmodel "github.com/goharbor/harbor/src/controller/event/model"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailHandler_Handle(t *testing.T) {
+	hookMgr := notification.HookManager
+	defer func() {
+		notification.HookManager = hookMgr
+	}()
+	notification.HookManager = &fakedHookManager{}
+
+	handler := &EmailHandler{}
+
+	assert.NotNil(t, handler.Handle(context.TODO(), nil))
+	assert.NotNil(t, handler.Handle(context.TODO(), &model.EventData{}))
+
+	err := handler.Handle(context.TODO(), &model.HookEvent{
+		PolicyID:  1,
+		EventType: "PUSH_ARTIFACT",
+		Target: &policy_model.EventTarget{
+			Type:    "email",
+			Address: "admin@example.com",
+		},
+		Payload: &model.Payload{
+			OccurAt:  time.Now().Unix(),
+			Type:     "PUSH_ARTIFACT",
+			Operator: "admin",
+		},
+	})
+	assert.Nil(t, err)
+}
+
+func TestEmailHandler_Convert(t *testing.T) {
+	handler := &EmailHandler{}
+
+	_, _, err := handler.convert(nil)
+	assert.NotNil(t, err)
+
+	// push artifact
+	subject, message, err := handler.convert(&model.Payload{
+		Type:     "PUSH_ARTIFACT",
+		OccurAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Operator: "admin",
+		EventData: &model.EventData{
+			Resources: []*model.Resource{
+				{
+					Tag:         "v1.0",
+					Digest:      "sha256:abc",
+					ResourceURL: "harbor.example.com/library/debian:v1.0",
+				},
+			},
+			Repository: &model.Repository{
+				Name:         "debian",
+				Namespace:    "library",
+				RepoFullName: "library/debian",
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "[Harbor] PUSH_ARTIFACT: library/debian", subject)
+	assert.Contains(t, message, "<b>event_type:</b> PUSH_ARTIFACT")
+	assert.Contains(t, message, "<b>occur_at:</b> Fri, 01 Jan 2021 00:00:00 UTC")
+	assert.Contains(t, message, "<b>repository:</b> library/debian")
+	assert.Contains(t, message, "<li>v1.0 sha256:abc (harbor.example.com/library/debian:v1.0)")
+
+	// replication
+	subject, message, err = handler.convert(&model.Payload{
+		Type:     "REPLICATION",
+		Operator: "MANUAL",
+		EventData: &model.EventData{
+			Replication: &evtmodel.Replication{
+				JobStatus:   "Failed",
+				TriggerType: "MANUAL",
+				FailedArtifact: []*evtmodel.ArtifactInfo{
+					{
+						NameAndTag: "library/debian:v1.0",
+						FailReason: "timeout",
+					},
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "[Harbor] REPLICATION", subject)
+	assert.Contains(t, message, "<b>replication:</b> Failed, triggered by MANUAL")
+	assert.Contains(t, message, "<li>library/debian:v1.0: timeout</li>")
+
+	// quota exceed, the content should be escaped
+	_, message, err = handler.convert(&model.Payload{
+		Type: "QUOTA_EXCEED",
+		EventData: &model.EventData{
+			Custom: map[string]string{
+				"Details": "<quota exceeded>",
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Contains(t, message, "<b>Details:</b> &lt;quota exceeded&gt;")
+}
+
+func TestEmailHandler_IsStateful(t *testing.T) {
+	handler := &EmailHandler{}
+	assert.False(t, handler.IsStateful())
+}
+
+func TestEmailHandler_Name(t *testing.T) {
+	handler := &EmailHandler{}
+	assert.Equal(t, "Email", handler.Name())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
 )
 
@@ -46,18 +47,64 @@ func (h *HTTPHandler) process(ctx context.Context, event *model.HookEvent) error
 	}
 	j.Name = job.WebhookJob
 
-	payload, err := json.Marshal(event.Payload)
+	payload, contentType, headers, err := h.convert(event)
 	if err != nil {
-		return fmt.Errorf("marshal from payload %v failed: %v", event.Payload, err)
+		return err
 	}
 
 	j.Parameters = map[string]interface{}{
-		"payload": string(payload),
-		"address": event.Target.Address,
+		"payload":      payload,
+		"content_type": contentType,
+		"headers":      headers,
+		"address":      event.Target.Address,
 		// Users can define a auth header in http statement in notification(webhook) policy.
 		// So it will be sent in header in http request.
 		"auth_header":      event.Target.AuthHeader,
 		"skip_cert_verify": event.Target.SkipCertVerify,
 	}
+	// the job loads the signing secret from the policy rather than carrying it in the
+	// parameters, as the parameters are stored by the jobservice in plaintext
+	if len(event.Target.SigningSecret) > 0 {
+		j.Parameters["policy_id"] = event.PolicyID
+		j.Parameters["target_index"] = event.TargetIndex
+	}
+	// the core re-enqueues the failed job with a delay according to the retry policy if it is set
+	if event.RetryPolicy != nil {
+		retryPolicy, err := json.Marshal(event.RetryPolicy)
+		if err != nil {
+			return err
+		}
+		j.Parameters["retry_policy"] = string(retryPolicy)
+	}
 	return notification.HookManager.StartHook(ctx, event, j)
 }
+
+// convert the payload according to the payload format of the target, returns the body,
+// the content type and the extra headers of the request
+func (h *HTTPHandler) convert(event *model.HookEvent) (string, string, map[string]string, error) {
+	switch event.Target.PayloadFormat {
+	case policy_model.PayloadFormatCloudEventsStructured, policy_model.PayloadFormatCloudEventsBinary:
+		ce, err := NewCloudEvent(event)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("convert payload to cloudevents failed: %v", err)
+		}
+		if event.Target.PayloadFormat == policy_model.PayloadFormatCloudEventsBinary {
+			body, headers, err := ce.Binary()
+			if err != nil {
+				return "", "", nil, fmt.Errorf("marshal from cloudevents %v failed: %v", ce, err)
+			}
+			return body, ce.DataContentType, headers, nil
+		}
+		body, err := ce.Structured()
+		if err != nil {
+			return "", "", nil, fmt.Errorf("marshal from cloudevents %v failed: %v", ce, err)
+		}
+		return body, CloudEventsContentType, nil, nil
+	default:
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("marshal from payload %v failed: %v", event.Payload, err)
+		}
+		return string(payload), "application/json", nil, nil
+	}
+}
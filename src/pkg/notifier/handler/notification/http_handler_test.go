@@ -22,6 +22,10 @@ func (f *fakedHookManager) StartHook(ctx context.Context, event *model.HookEvent
 	return nil
 }
 
+func (f *fakedHookManager) RetryHook(ctx context.Context, id int64, name string, params map[string]interface{}) (bool, error) {
+	return false, nil
+}
+
 func TestHTTPHandler_Handle(t *testing.T) {
 	hookMgr := notification.HookManager
 	defer func() {
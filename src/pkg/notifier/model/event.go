@@ -7,10 +7,15 @@ import (
 
 // HookEvent is hook related event data to publish
 type HookEvent struct {
+	ProjectID int64
 	PolicyID  int64
 	EventType string
 	Target    *policy_model.EventTarget
-	Payload   *Payload
+	// TargetIndex is the index of the target in the targets of the policy
+	TargetIndex int
+	Payload     *Payload
+	// RetryPolicy of the policy, nil means the deliveries are retried by the jobservice
+	RetryPolicy *policy_model.RetryPolicy
 }
 
 // Payload of notification event
@@ -27,6 +32,10 @@ type EventData struct {
 	Repository  *Repository        `json:"repository,omitempty"`
 	Replication *model.Replication `json:"replication,omitempty"`
 	Retention   *model.Retention   `json:"retention,omitempty"`
+	Project     *model.Project     `json:"project,omitempty"`
+	Member      *model.Member      `json:"member,omitempty"`
+	Robot       *model.Robot       `json:"robot,omitempty"`
+	Policy      *model.Policy      `json:"policy,omitempty"`
 	Custom      map[string]string  `json:"custom_attributes,omitempty"`
 }
 
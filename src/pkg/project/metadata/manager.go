@@ -42,7 +42,7 @@ type Manager interface {
 	// Get metadatas whose keys are specified in parameter meta, if it is absent, get all
 	Get(ctx context.Context, projectID int64, meta ...string) (map[string]string, error)
 
-	// List metadata according to the name and value
+	// List metadata according to the name and value, the value is ignored if it is empty
 	List(ctx context.Context, name, value string) ([]*models.ProjectMetadata, error)
 }
 
@@ -107,9 +107,13 @@ func (m *manager) Get(ctx context.Context, projectID int64, meta ...string) (map
 	return data, nil
 }
 
-// List metadata according to the name and value
+// List metadata according to the name and value, the value is ignored if it is empty
 func (m *manager) List(ctx context.Context, name string, value string) ([]*models.ProjectMetadata, error) {
-	return m.dao.List(ctx, q.New(q.KeyWords{"name": name, "value": value}))
+	kw := q.KeyWords{"name": name}
+	if len(value) > 0 {
+		kw["value"] = value
+	}
+	return m.dao.List(ctx, q.New(kw))
 }
 
 func makeQuery(projectID int64, meta ...string) *q.Query {
@@ -16,10 +16,15 @@ package models
 
 // keys of project metadata and severity values
 const (
-	ProMetaPublic               = "public"
-	ProMetaEnableContentTrust   = "enable_content_trust"
-	ProMetaPreventVul           = "prevent_vul" // prevent vulnerable images from being pulled
-	ProMetaSeverity             = "severity"
-	ProMetaAutoScan             = "auto_scan"
-	ProMetaReuseSysCVEAllowlist = "reuse_sys_cve_allowlist"
+	ProMetaPublic                  = "public"
+	ProMetaEnableContentTrust      = "enable_content_trust"
+	ProMetaPreventVul              = "prevent_vul" // prevent vulnerable images from being pulled
+	ProMetaSeverity                = "severity"
+	ProMetaAutoScan                = "auto_scan"
+	ProMetaReuseSysCVEAllowlist    = "reuse_sys_cve_allowlist"
+	ProMetaProxyUpstreamRegistries = "proxy_upstream_registries" // comma separated IDs of the fallback upstream registries of proxy cache project
+	ProMetaProxyServeStale         = "proxy_serve_stale"         // serve the cached manifests when the upstream registries are unreachable
+	ProMetaProxyMaxStaleness       = "proxy_max_staleness"       // the max staleness in minutes of the cached manifests can be served
+	ProMetaProxyEviction           = "proxy_eviction"            // evict the least recently pulled artifacts when the storage usage nears the quota
+	ProMetaProxyEvictionThreshold  = "proxy_eviction_threshold"  // the percentage of the storage quota which triggers the eviction
 )
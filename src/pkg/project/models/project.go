@@ -32,6 +32,8 @@ const (
 	ProjectPublic = "public"
 	// ProjectPrivate means project is private
 	ProjectPrivate = "private"
+	// DefaultProxyEvictionThreshold is the default percentage of the storage quota which triggers the eviction
+	DefaultProxyEvictionThreshold = 90
 )
 
 func init() {
@@ -94,6 +96,84 @@ func (p *Project) IsProxy() bool {
 	return p.RegistryID > 0
 }
 
+// ProxyRegistryIDs returns the ordered IDs of the upstream registries of the proxy cache project,
+// the registry specified by "RegistryID" comes first and then the fallback ones
+func (p *Project) ProxyRegistryIDs() []int64 {
+	if !p.IsProxy() {
+		return nil
+	}
+	ids := []int64{p.RegistryID}
+	upstreams, exist := p.GetMetadata(ProMetaProxyUpstreamRegistries)
+	if !exist {
+		return ids
+	}
+	for _, str := range strings.Split(upstreams, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		duplicated := false
+		for _, i := range ids {
+			if i == id {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ProxyServeStale returns true when the proxy cache project serves the cached manifests
+// if the upstream registries are unreachable
+func (p *Project) ProxyServeStale() bool {
+	serve, exist := p.GetMetadata(ProMetaProxyServeStale)
+	if !exist {
+		return false
+	}
+	return p.IsProxy() && isTrue(serve)
+}
+
+// ProxyMaxStaleness returns the max staleness of the cached manifests which can be served
+// when the upstream registries are unreachable, zero means no limitation
+func (p *Project) ProxyMaxStaleness() time.Duration {
+	value, exist := p.GetMetadata(ProMetaProxyMaxStaleness)
+	if !exist {
+		return 0
+	}
+	minutes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ProxyEvictionEnabled returns true when the proxy cache project evicts the least recently pulled
+// artifacts if the storage usage nears the quota
+func (p *Project) ProxyEvictionEnabled() bool {
+	evict, exist := p.GetMetadata(ProMetaProxyEviction)
+	if !exist {
+		return false
+	}
+	return p.IsProxy() && isTrue(evict)
+}
+
+// ProxyEvictionThreshold returns the percentage of the storage quota which triggers the eviction,
+// the default value is returned when it isn't set or invalid
+func (p *Project) ProxyEvictionThreshold() int {
+	value, exist := p.GetMetadata(ProMetaProxyEvictionThreshold)
+	if !exist {
+		return DefaultProxyEvictionThreshold
+	}
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold <= 0 || threshold > 100 {
+		return DefaultProxyEvictionThreshold
+	}
+	return threshold
+}
+
 // ContentTrustEnabled ...
 func (p *Project) ContentTrustEnabled() bool {
 	enabled, exist := p.GetMetadata(ProMetaEnableContentTrust)
@@ -65,6 +65,25 @@ type ArtifactRegistry interface {
 	DeleteTag(repository, tag string) error
 }
 
+// ChunkedArtifactRegistry defines the capabilities to copy the blobs by chunks, which is optional
+// for the artifact registry. The upload session can be resumed from the last committed byte
+type ChunkedArtifactRegistry interface {
+	PullBlobChunk(repository, digest string, blobSize, start, end int64) (size int64, blob io.ReadCloser, err error)
+	PushBlobChunk(repository, digest string, blobSize int64, chunk io.Reader, start, end int64, location string) (nextUploadLocation string, endRange int64, err error)
+}
+
+// TagRegistry defines the capability to list the tags of the repository directly, which is optional
+// for the artifact registry
+type TagRegistry interface {
+	ListTags(repository string) (tags []string, err error)
+}
+
+// LabelRegistry defines the capability to add labels to the artifacts, which is optional for the
+// artifact registry. The missing labels are created before being added
+type LabelRegistry interface {
+	AddLabels(repository, reference string, labels []*model.Label) error
+}
+
 // ChartRegistry defines the capabilities that a chart registry should have
 type ChartRegistry interface {
 	FetchCharts(filters []*model.Filter) ([]*model.Resource, error)
@@ -86,6 +86,10 @@ func (a *adapter) Info() (*model.RegistryInfo, error) {
 				Type:  model.FilterTypeTag,
 				Style: model.FilterStyleTypeText,
 			},
+			{
+				Type:  model.FilterTypePushTime,
+				Style: model.FilterStyleTypeText,
+			},
 		},
 		SupportedTriggers: []string{
 			model.TriggerTypeManual,
@@ -293,7 +297,7 @@ func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, er
 				}
 			}
 
-			var tags []string
+			var tags []Tag
 			page := 1
 			pageSize := 100
 			for {
@@ -301,9 +305,7 @@ func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, er
 				if err != nil {
 					return fmt.Errorf("get tags for repo '%s/%s' from DockerHub error: %v", repo.Namespace, repo.Name, err)
 				}
-				for _, t := range pageTags.Tags {
-					tags = append(tags, t.Name)
-				}
+				tags = append(tags, pageTags.Tags...)
 
 				if len(pageTags.Next) == 0 {
 					break
@@ -314,7 +316,8 @@ func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, er
 			var artifacts []*model.Artifact
 			for _, tag := range tags {
 				artifacts = append(artifacts, &model.Artifact{
-					Tags: []string{tag},
+					Tags:     []string{tag.Name},
+					PushTime: tag.LastUpdated,
 				})
 			}
 			filterArtifacts, err := filter.DoFilterArtifacts(artifacts, filters)
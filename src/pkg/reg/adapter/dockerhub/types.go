@@ -1,5 +1,7 @@
 package dockerhub
 
+import "time"
+
 // LoginCredential is request to login.
 type LoginCredential struct {
 	User     string `json:"username"`
@@ -77,6 +79,8 @@ type Tag struct {
 	Name string `json:"name"`
 	// FullSize is size of the image
 	FullSize int64 `json:"full_size"`
+	// LastUpdated is the time when the tag is pushed last time
+	LastUpdated time.Time `json:"last_updated"`
 }
 
 // TagsResp is response of tag list request
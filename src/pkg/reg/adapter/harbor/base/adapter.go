@@ -16,6 +16,7 @@ package base
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
@@ -25,6 +26,7 @@ import (
 	"github.com/goharbor/harbor/src/common/http/modifier"
 	common_http_auth "github.com/goharbor/harbor/src/common/http/modifier/auth"
 	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/reg/util"
@@ -139,9 +141,11 @@ func (a *Adapter) Info() (*model.RegistryInfo, error) {
 	return info, nil
 }
 
-// PrepareForPush creates projects
+// PrepareForPush creates projects, the metadata of the existing projects is updated if
+// the resources are required to sync the metadata
 func (a *Adapter) PrepareForPush(resources []*model.Resource) error {
 	projects := map[string]*Project{}
+	syncMetadata := map[string]bool{}
 	for _, resource := range resources {
 		if resource == nil {
 			return errors.New("the resource cannot be null")
@@ -160,6 +164,10 @@ func (a *Adapter) PrepareForPush(resources []*model.Resource) error {
 		projectName := paths[0]
 		// handle the public properties
 		metadata := abstractPublicMetadata(resource.Metadata.Repository.Metadata)
+		if resource.SyncMetadata {
+			metadata = abstractSyncedMetadata(resource.Metadata.Repository.Metadata)
+			syncMetadata[projectName] = true
+		}
 		pro, exist := projects[projectName]
 		if exist {
 			metadata = mergeMetadata(pro.Metadata, metadata)
@@ -173,6 +181,11 @@ func (a *Adapter) PrepareForPush(resources []*model.Resource) error {
 		if err := a.Client.CreateProject(project.Name, project.Metadata); err != nil {
 			if httpErr, ok := err.(*common_http.Error); ok && httpErr.Code == http.StatusConflict {
 				log.Debugf("got 409 when trying to create project %s", project.Name)
+				if syncMetadata[project.Name] {
+					if err = a.updateProjectMetadata(project); err != nil {
+						return err
+					}
+				}
 				continue
 			}
 			return err
@@ -182,6 +195,33 @@ func (a *Adapter) PrepareForPush(resources []*model.Resource) error {
 	return nil
 }
 
+// updateProjectMetadata updates the synced metadata of the existing project, the public metadata
+// isn't updated to avoid changing the visibility of the existing project
+func (a *Adapter) updateProjectMetadata(project *Project) error {
+	metadata := map[string]interface{}{}
+	for key, value := range project.Metadata {
+		if key == models.ProMetaPublic {
+			continue
+		}
+		metadata[key] = value
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	pro, err := a.Client.GetProject(project.Name)
+	if err != nil {
+		return err
+	}
+	if pro == nil {
+		return fmt.Errorf("project %s not found", project.Name)
+	}
+	if err = a.Client.UpdateProjectMetadata(pro.ID, metadata); err != nil {
+		return err
+	}
+	log.Debugf("the metadata of project %s updated", project.Name)
+	return nil
+}
+
 // ListProjects lists projects
 func (a *Adapter) ListProjects(filters []*model.Filter) ([]*Project, error) {
 	pattern := ""
@@ -233,12 +273,44 @@ func abstractPublicMetadata(metadata map[string]interface{}) map[string]interfac
 	}
 }
 
-// currently, mergeMetadata only handles the public metadata
+// syncedMetadataKeys are the keys of the project metadata which are synced besides the public metadata
+var syncedMetadataKeys = []string{
+	models.ProMetaAutoScan,
+	models.ProMetaSeverity,
+	models.ProMetaPreventVul,
+	models.ProMetaEnableContentTrust,
+}
+
+func abstractSyncedMetadata(metadata map[string]interface{}) map[string]interface{} {
+	result := abstractPublicMetadata(metadata)
+	for _, key := range syncedMetadataKeys {
+		value, exist := metadata[key]
+		if !exist {
+			continue
+		}
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// the project is public only when all the metadata are public, the other synced metadata
+// of the first resource wins
 func mergeMetadata(metadata1, metadata2 map[string]interface{}) map[string]interface{} {
 	public := parsePublic(metadata1) && parsePublic(metadata2)
-	return map[string]interface{}{
+	metadata := map[string]interface{}{
 		"public": strconv.FormatBool(public),
 	}
+	for _, key := range syncedMetadataKeys {
+		if value, exist := metadata1[key]; exist {
+			metadata[key] = value
+		} else if value, exist := metadata2[key]; exist {
+			metadata[key] = value
+		}
+	}
+	return metadata
 }
 
 func parsePublic(metadata map[string]interface{}) bool {
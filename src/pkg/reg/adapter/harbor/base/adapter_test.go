@@ -15,6 +15,7 @@
 package base
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"testing"
@@ -162,6 +163,65 @@ func TestPrepareForPush(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestPrepareForPushWithSyncMetadata(t *testing.T) {
+	var updated map[string]interface{}
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodPost,
+			Pattern: "/api/projects",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusConflict)
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/projects",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`[{"project_id":1,"name":"library","metadata":{"public":"false"}}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodPut,
+			Pattern: "/api/projects/1",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				project := &struct {
+					Metadata map[string]interface{} `json:"metadata"`
+				}{}
+				json.NewDecoder(r.Body).Decode(project)
+				updated = project.Metadata
+			},
+		})
+	defer server.Close()
+	adapter, err := New(&model.Registry{
+		URL: server.URL,
+	})
+	require.Nil(t, err)
+
+	err = adapter.PrepareForPush(
+		[]*model.Resource{
+			{
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: "library/hello-world",
+						Metadata: map[string]interface{}{
+							"public":    "true",
+							"auto_scan": "true",
+							"severity":  "high",
+							"retention": "1",
+						},
+					},
+				},
+				SyncMetadata: true,
+			},
+		})
+	require.Nil(t, err)
+	// the public metadata of the existing project isn't updated
+	assert.Equal(t, map[string]interface{}{
+		"auto_scan": "true",
+		"severity":  "high",
+	}, updated)
+}
+
 func TestParsePublic(t *testing.T) {
 	cases := []struct {
 		metadata map[string]interface{}
@@ -217,6 +277,40 @@ func TestMergeMetadata(t *testing.T) {
 		m := mergeMetadata(c.m1, c.m2)
 		assert.Equal(t, strconv.FormatBool(c.public), m["public"].(string))
 	}
+
+	// the synced metadata of the first one wins
+	m := mergeMetadata(map[string]interface{}{
+		"public":   "true",
+		"severity": "high",
+	}, map[string]interface{}{
+		"public":    "true",
+		"severity":  "low",
+		"auto_scan": "true",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"public":    "true",
+		"severity":  "high",
+		"auto_scan": "true",
+	}, m)
+}
+
+func TestAbstractSyncedMetadata(t *testing.T) {
+	// nil input metadata
+	meta := abstractSyncedMetadata(nil)
+	assert.Nil(t, meta)
+
+	metadata := map[string]interface{}{
+		"other":                "test",
+		"public":               "true",
+		"auto_scan":            "true",
+		"enable_content_trust": "false",
+	}
+	meta = abstractSyncedMetadata(metadata)
+	assert.Equal(t, map[string]interface{}{
+		"public":               "true",
+		"auto_scan":            "true",
+		"enable_content_trust": "false",
+	}, meta)
 }
 
 func TestAbstractPublicMetadata(t *testing.T) {
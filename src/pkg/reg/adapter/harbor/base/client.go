@@ -102,6 +102,16 @@ func (c *Client) CreateProject(name string, metadata map[string]interface{}) err
 	return c.C.Post(c.BasePath()+"/projects", project)
 }
 
+// UpdateProjectMetadata updates the metadata of the project
+func (c *Client) UpdateProjectMetadata(id int64, metadata map[string]interface{}) error {
+	project := struct {
+		Metadata map[string]interface{} `json:"metadata"`
+	}{
+		Metadata: metadata,
+	}
+	return c.C.Put(fmt.Sprintf("%s/projects/%d", c.BasePath(), id), project)
+}
+
 // ListProjects lists projects
 func (c *Client) ListProjects(name string) ([]*Project, error) {
 	projects := []*Project{}
@@ -17,17 +17,20 @@ package v2
 import (
 	"fmt"
 
+	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/utils"
 	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter/harbor/base"
 	"github.com/goharbor/harbor/src/pkg/reg/filter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 var _ adp.Adapter = &adapter{}
 var _ adp.ArtifactRegistry = &adapter{}
 var _ adp.ChartRegistry = &adapter{}
+var _ adp.LabelRegistry = &adapter{}
 
 // New creates a Adapter for Harbor 2.x
 func New(base *base.Adapter) adp.Adapter {
@@ -48,6 +51,27 @@ func (a *adapter) Info() (*model.RegistryInfo, error) {
 		return nil, err
 	}
 	info.SupportedResourceTypes = append(info.SupportedResourceTypes, model.ResourceTypeArtifact)
+	info.SupportedResourceFilters = append(info.SupportedResourceFilters,
+		&model.FilterStyle{
+			Type:  model.FilterTypeVulnerability,
+			Style: model.FilterStyleTypeRadio,
+			Values: []string{
+				vuln.Negligible.String(),
+				vuln.Low.String(),
+				vuln.Medium.String(),
+				vuln.High.String(),
+				vuln.Critical.String(),
+			},
+		},
+		&model.FilterStyle{
+			Type:   model.FilterTypeSignature,
+			Style:  model.FilterStyleTypeRadio,
+			Values: []string{"true", "false"},
+		},
+		&model.FilterStyle{
+			Type:  model.FilterTypePushTime,
+			Style: model.FilterStyleTypeText,
+		})
 	return info, err
 }
 
@@ -115,6 +139,51 @@ func (a *adapter) DeleteTag(repository, tag string) error {
 	return a.client.deleteTag(repository, tag)
 }
 
+// AddLabels adds the labels to the artifact, the missing global and project labels are created
+func (a *adapter) AddLabels(repository, reference string, labels []*model.Label) error {
+	projectName, _ := utils.ParseRepository(repository)
+	var project *base.Project
+	for _, label := range labels {
+		var projectID int64
+		if label.Scope == common.LabelScopeProject {
+			if project == nil {
+				pro, err := a.Client.GetProject(projectName)
+				if err != nil {
+					return err
+				}
+				if pro == nil {
+					return fmt.Errorf("project %s not found", projectName)
+				}
+				project = pro
+			}
+			projectID = project.ID
+		}
+		lb, err := a.client.getLabel(label.Name, label.Scope, projectID)
+		if err != nil {
+			return err
+		}
+		if lb == nil {
+			if err = a.client.createLabel(label, projectID); err != nil {
+				return err
+			}
+			if lb, err = a.client.getLabel(label.Name, label.Scope, projectID); err != nil {
+				return err
+			}
+			if lb == nil {
+				return fmt.Errorf("label %s not found after created", label.Name)
+			}
+		}
+		if err = a.client.addArtifactLabel(repository, reference, lb.ID); err != nil {
+			// the label is already added to the artifact
+			if e, ok := err.(*http.Error); ok && e.Code == 409 {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *adapter) listRepositories(project *base.Project, filters []*model.Filter) ([]*model.Repository, error) {
 	repositories, err := a.client.listRepositories(project)
 	if err != nil {
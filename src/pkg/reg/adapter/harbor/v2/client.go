@@ -16,13 +16,18 @@ package v2
 
 import (
 	"fmt"
+	neturl "net/url"
 
+	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/lib/encode/repository"
+	accessorymodel "github.com/goharbor/harbor/src/pkg/accessory/model"
+	labelmodel "github.com/goharbor/harbor/src/pkg/label/model"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter/harbor/base"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	repomodel "github.com/goharbor/harbor/src/pkg/repository/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 type client struct {
@@ -48,29 +53,67 @@ func (c *client) listRepositories(project *base.Project) ([]*model.Repository, e
 func (c *client) listArtifacts(repo string) ([]*model.Artifact, error) {
 	project, repo := utils.ParseRepository(repo)
 	repo = repository.Encode(repo)
-	url := fmt.Sprintf("%s/projects/%s/repositories/%s/artifacts?with_label=true",
+	url := fmt.Sprintf("%s/projects/%s/repositories/%s/artifacts?with_label=true&with_scan_overview=true&with_signature=true&with_accessory=true",
 		c.BasePath(), project, repo)
-	artifacts := []*artifact.Artifact{}
+	artifacts := []*scannedArtifact{}
 	if err := c.C.GetAndIteratePagination(url, &artifacts); err != nil {
 		return nil, err
 	}
 	var arts []*model.Artifact
 	for _, artifact := range artifacts {
 		art := &model.Artifact{
-			Type:   artifact.Type,
-			Digest: artifact.Digest,
+			Type:     artifact.Type,
+			Digest:   artifact.Digest,
+			Severity: artifact.severity(),
+			PushTime: artifact.PushTime,
 		}
 		for _, label := range artifact.Labels {
 			art.Labels = append(art.Labels, label.Name)
+			art.LabelDetails = append(art.LabelDetails, &model.Label{
+				Name:        label.Name,
+				Description: label.Description,
+				Color:       label.Color,
+				Scope:       label.Scope,
+			})
 		}
 		for _, tag := range artifact.Tags {
 			art.Tags = append(art.Tags, tag.Name)
+			if tag.Signed {
+				art.SignedTags = append(art.SignedTags, tag.Name)
+			}
+		}
+		for _, accessory := range artifact.Accessories {
+			if accessory.Type == accessorymodel.TypeCosignSignature {
+				art.Signed = true
+				break
+			}
 		}
 		arts = append(arts, art)
 	}
 	return arts, nil
 }
 
+// scannedArtifact is the artifact returned by the artifact API including the scan overview
+type scannedArtifact struct {
+	artifact.Artifact
+	ScanOverview map[string]*vuln.NativeReportSummary `json:"scan_overview"`
+}
+
+// severity returns the highest severity among the scan reports, empty string is returned if
+// the artifact isn't scanned
+func (s *scannedArtifact) severity() string {
+	var severity vuln.Severity
+	for _, summary := range s.ScanOverview {
+		if summary == nil || len(summary.Severity) == 0 {
+			continue
+		}
+		if len(severity) == 0 || summary.Severity.Code() > severity.Code() {
+			severity = summary.Severity
+		}
+	}
+	return severity.String()
+}
+
 func (c *client) deleteTag(repo, tag string) error {
 	project, repo := utils.ParseRepository(repo)
 	repo = repository.Encode(repo)
@@ -79,6 +122,47 @@ func (c *client) deleteTag(repo, tag string) error {
 	return c.C.Delete(url)
 }
 
+// getLabel returns the label by name, nil is returned if the label doesn't exist. The project ID
+// is required for the project level label
+func (c *client) getLabel(name, scope string, projectID int64) (*labelmodel.Label, error) {
+	url := fmt.Sprintf("%s/labels?scope=%s&name=%s", c.BasePath(), scope, neturl.QueryEscape(name))
+	if scope == common.LabelScopeProject {
+		url = fmt.Sprintf("%s&project_id=%d", url, projectID)
+	}
+	labels := []*labelmodel.Label{}
+	if err := c.C.GetAndIteratePagination(url, &labels); err != nil {
+		return nil, err
+	}
+	// the name query is fuzzy matching
+	for _, label := range labels {
+		if label.Name == name {
+			return label, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *client) createLabel(label *model.Label, projectID int64) error {
+	lb := &labelmodel.Label{
+		Name:        label.Name,
+		Description: label.Description,
+		Color:       label.Color,
+		Scope:       label.Scope,
+	}
+	if label.Scope == common.LabelScopeProject {
+		lb.ProjectID = projectID
+	}
+	return c.C.Post(fmt.Sprintf("%s/labels", c.BasePath()), lb)
+}
+
+func (c *client) addArtifactLabel(repo, reference string, labelID int64) error {
+	project, repo := utils.ParseRepository(repo)
+	repo = repository.Encode(repo)
+	url := fmt.Sprintf("%s/projects/%s/repositories/%s/artifacts/%s/labels",
+		c.BasePath(), project, repo, reference)
+	return c.C.Post(url, &labelmodel.Label{ID: labelID})
+}
+
 func (c *client) getRepositoryByBlobDigest(digest string) (string, error) {
 	repositories := []*repomodel.RepoRecord{}
 	url := fmt.Sprintf("%s/repositories?q=blob_digest=%s&page_size=1&page_number=1", c.BasePath(), digest)
@@ -0,0 +1,219 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+// !!!! Limits:
+// - The URL of the registry must be in format "https://nexus.example.com/repository/<docker-repository>",
+//   the Nexus REST API is served under the part before "/repository/" and the Docker registry API of the
+//   repository is served under the whole URL.
+// - The repositories and tags are listed by the components API of Nexus as the catalog API is
+//   often disabled.
+
+const repositoryPathPrefix = "/repository/"
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeNexus, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeNexus, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeNexus)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+var (
+	_ adp.Adapter          = (*adapter)(nil)
+	_ adp.ArtifactRegistry = (*adapter)(nil)
+)
+
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	client   *client
+	// the name of the docker repository in Nexus
+	repository string
+}
+
+func newAdapter(registry *model.Registry) (*adapter, error) {
+	baseURL, repository, err := parseURL(registry.URL)
+	if err != nil {
+		return nil, err
+	}
+	username, password := "", ""
+	if registry.Credential != nil {
+		username = registry.Credential.AccessKey
+		password = registry.Credential.AccessSecret
+	}
+	return &adapter{
+		Adapter:    native.NewAdapter(registry),
+		registry:   registry,
+		client:     newClient(baseURL, username, password, registry.Insecure),
+		repository: repository,
+	}, nil
+}
+
+// parseURL parses the URL in format "https://nexus.example.com/repository/<name>"
+// into the base URL of Nexus and the repository name
+func parseURL(s string) (string, string, error) {
+	u, err := url.Parse(strings.TrimSuffix(s, "/"))
+	if err != nil {
+		return "", "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid URL: %s", err.Error())
+	}
+	i := strings.LastIndex(u.Path, repositoryPathPrefix)
+	if i < 0 {
+		return "", "", errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the URL of Nexus must be in format https://nexus.example.com/repository/<name>: %s", s)
+	}
+	name := u.Path[i+len(repositoryPathPrefix):]
+	if len(name) == 0 || strings.Contains(name, "/") {
+		return "", "", errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the URL of Nexus must be in format https://nexus.example.com/repository/<name>: %s", s)
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path[:i]), name, nil
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeNexus,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypePushTime,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// HealthCheck checks whether the docker repository exists in Nexus and the registry API is available
+func (a *adapter) HealthCheck() (string, error) {
+	repository, err := a.client.getRepository(a.repository)
+	if err != nil {
+		log.Errorf("failed to get the repository %s from Nexus %s: %v", a.repository, a.client.url, err)
+		return model.Unhealthy, nil
+	}
+	if repository == nil || repository.Format != "docker" {
+		log.Errorf("the docker repository %s doesn't exist in Nexus %s", a.repository, a.client.url)
+		return model.Unhealthy, nil
+	}
+	return a.Adapter.HealthCheck()
+}
+
+// FetchArtifacts lists the images and tags by the components of the docker repository
+func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	components, err := a.client.listComponents(a.repository)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := map[string][]*model.Artifact{}
+	for _, component := range components {
+		if len(component.Name) == 0 || len(component.Version) == 0 {
+			continue
+		}
+		artifacts[component.Name] = append(artifacts[component.Name], &model.Artifact{
+			Tags:     []string{component.Version},
+			PushTime: component.pushTime(),
+		})
+	}
+
+	var repositories []*model.Repository
+	for name := range artifacts {
+		repositories = append(repositories, &model.Repository{
+			Name: name,
+		})
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+	repositories, err = filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*model.Resource
+	for _, repository := range repositories {
+		arts, err := filter.DoFilterArtifacts(artifacts[repository.Name], filters)
+		if err != nil {
+			return nil, err
+		}
+		if len(arts) == 0 {
+			continue
+		}
+		resources = append(resources, &model.Resource{
+			Type:     model.ResourceTypeImage,
+			Registry: a.registry,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: repository.Name,
+				},
+				Artifacts: arts,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// pushTime returns the time when the manifest of the component is pushed
+func (c *component) pushTime() time.Time {
+	for _, asset := range c.Assets {
+		if asset.BlobCreated != nil {
+			return *asset.BlobCreated
+		}
+		if asset.LastModified != nil {
+			return *asset.LastModified
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNexus is a stand-in of Nexus which serves the components API in two pages
+// and the registry API of the docker repository "docker-hosted"
+func mockNexus() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nexus/service/rest/v1/repositories":
+			w.Write([]byte(`[{"name":"maven-central","format":"maven2","type":"proxy"},{"name":"docker-hosted","format":"docker","type":"hosted"}]`))
+		case "/nexus/service/rest/v1/components":
+			if r.URL.Query().Get("repository") != "docker-hosted" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.URL.Query().Get("continuationToken") == "" {
+				w.Write([]byte(`{"items":[
+					{"name":"library/busybox","version":"1.0","assets":[{"path":"v2/library/busybox/manifests/1.0","blobCreated":"2021-01-01T00:00:00Z"}]},
+					{"name":"library/busybox","version":"latest","assets":[{"path":"v2/library/busybox/manifests/latest","lastModified":"2021-02-01T00:00:00Z"}]}
+				],"continuationToken":"next"}`))
+				return
+			}
+			w.Write([]byte(`{"items":[
+				{"name":"app/web","version":"v1","assets":[]}
+			],"continuationToken":null}`))
+		case "/nexus/repository/docker-hosted/v2/":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestParseURL(t *testing.T) {
+	base, repository, err := parseURL("https://nexus.example.com/repository/docker-hosted/")
+	require.Nil(t, err)
+	assert.Equal(t, "https://nexus.example.com", base)
+	assert.Equal(t, "docker-hosted", repository)
+
+	base, repository, err = parseURL("https://example.com/nexus/repository/docker-hosted")
+	require.Nil(t, err)
+	assert.Equal(t, "https://example.com/nexus", base)
+	assert.Equal(t, "docker-hosted", repository)
+
+	_, _, err = parseURL("https://nexus.example.com")
+	assert.NotNil(t, err)
+
+	_, _, err = parseURL("https://nexus.example.com/repository/")
+	assert.NotNil(t, err)
+
+	_, _, err = parseURL("https://nexus.example.com/repository/docker/v2")
+	assert.NotNil(t, err)
+}
+
+func TestHealthCheck(t *testing.T) {
+	server := mockNexus()
+	defer server.Close()
+
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeNexus,
+		URL:  server.URL + "/nexus/repository/docker-hosted",
+	})
+	require.Nil(t, err)
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+
+	a, err = newAdapter(&model.Registry{
+		Type: model.RegistryTypeNexus,
+		URL:  server.URL + "/nexus/repository/maven-central",
+	})
+	require.Nil(t, err)
+	status, err = a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Unhealthy, status)
+}
+
+func TestFetchArtifacts(t *testing.T) {
+	server := mockNexus()
+	defer server.Close()
+
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeNexus,
+		URL:  server.URL + "/nexus/repository/docker-hosted",
+	})
+	require.Nil(t, err)
+
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "app/web", resources[0].Metadata.Repository.Name)
+	require.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, []string{"v1"}, resources[0].Metadata.Artifacts[0].Tags)
+	assert.True(t, resources[0].Metadata.Artifacts[0].PushTime.IsZero())
+	assert.Equal(t, "library/busybox", resources[1].Metadata.Repository.Name)
+	require.Len(t, resources[1].Metadata.Artifacts, 2)
+	assert.Equal(t, 2021, resources[1].Metadata.Artifacts[0].PushTime.Year())
+	assert.False(t, resources[1].Metadata.Artifacts[1].PushTime.IsZero())
+
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/**",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "latest",
+		},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "library/busybox", resources[0].Metadata.Repository.Name)
+	require.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, []string{"latest"}, resources[0].Metadata.Artifacts[0].Tags)
+}
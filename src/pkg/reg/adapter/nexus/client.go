@@ -0,0 +1,116 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/pkg/registry/auth/basic"
+)
+
+// repository is the repository returned by the repositories API of Nexus
+type repository struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+}
+
+// component is the component returned by the components API of Nexus, for the docker
+// format, the name is the image name and the version is the tag
+type component struct {
+	ID         string   `json:"id"`
+	Repository string   `json:"repository"`
+	Format     string   `json:"format"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Assets     []*asset `json:"assets"`
+}
+
+type asset struct {
+	Path         string            `json:"path"`
+	Checksum     map[string]string `json:"checksum"`
+	LastModified *time.Time        `json:"lastModified"`
+	BlobCreated  *time.Time        `json:"blobCreated"`
+}
+
+type componentList struct {
+	Items             []*component `json:"items"`
+	ContinuationToken *string      `json:"continuationToken"`
+}
+
+// client for the REST API of Nexus
+type client struct {
+	client *common_http.Client
+	// the base URL of Nexus, e.g. https://nexus.example.com
+	url string
+}
+
+func newClient(url, username, password string, insecure bool) *client {
+	var modifiers []modifier.Modifier
+	if len(username) > 0 || len(password) > 0 {
+		modifiers = append(modifiers, basic.NewAuthorizer(username, password))
+	}
+	return &client{
+		client: common_http.NewClient(
+			&http.Client{
+				Transport: common_http.GetHTTPTransport(common_http.WithInsecure(insecure)),
+			},
+			modifiers...,
+		),
+		url: url,
+	}
+}
+
+// getRepository returns the repository by name, nil is returned if the repository doesn't exist
+func (c *client) getRepository(name string) (*repository, error) {
+	repositories := []*repository{}
+	if err := c.client.Get(fmt.Sprintf("%s/service/rest/v1/repositories", c.url), &repositories); err != nil {
+		return nil, err
+	}
+	for _, repository := range repositories {
+		if repository.Name == name {
+			return repository, nil
+		}
+	}
+	return nil, nil
+}
+
+// listComponents lists all the components of the repository by the continuation token
+func (c *client) listComponents(repository string) ([]*component, error) {
+	var components []*component
+	token := ""
+	for {
+		u := fmt.Sprintf("%s/service/rest/v1/components?repository=%s", c.url, url.QueryEscape(repository))
+		if len(token) > 0 {
+			u = fmt.Sprintf("%s&continuationToken=%s", u, url.QueryEscape(token))
+		}
+		list := &componentList{}
+		if err := c.client.Get(u, list); err != nil {
+			return nil, err
+		}
+		components = append(components, list.Items...)
+		if list.ContinuationToken == nil || len(*list.ContinuationToken) == 0 {
+			break
+		}
+		token = *list.ContinuationToken
+	}
+	return components, nil
+}
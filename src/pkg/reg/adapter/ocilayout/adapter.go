@@ -0,0 +1,428 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	// register the manifest types which are unmarshalled by "distribution.UnmarshalManifest"
+	_ "github.com/docker/distribution/manifest/ocischema"
+	_ "github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// the environment variable specifies the root directory that all the OCI image layouts are stored under
+	rootDirEnv     = "OCI_LAYOUT_ROOT_DIR"
+	defaultRootDir = "/data/oci-layout"
+)
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeOCILayout, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeOCILayout, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeOCILayout)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return NewAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+var (
+	_ adp.Adapter          = (*Adapter)(nil)
+	_ adp.ArtifactRegistry = (*Adapter)(nil)
+)
+
+// Adapter implements an adapter for the OCI image layout, it is used to replicate artifacts
+// between Harbor and the air-gapped environments. The URL of the registry is in format
+// "file:///path/to/layout" and the path is relative to the root directory specified by the
+// environment variable "OCI_LAYOUT_ROOT_DIR". The layout can be a directory which is readable
+// and writable, or a tarball(".tar", ".tar.gz" or ".tgz") which can only be used as the source
+type Adapter struct {
+	registry *model.Registry
+	rootDir  string
+	*layout
+}
+
+// NewAdapter returns an instance of the Adapter
+func NewAdapter(reg *model.Registry) (*Adapter, error) {
+	url, err := lib.ValidateFileURL(reg.URL)
+	if err != nil {
+		return nil, err
+	}
+	rootDir := os.Getenv(rootDirEnv)
+	if len(rootDir) == 0 {
+		rootDir = defaultRootDir
+	}
+	return &Adapter{
+		registry: reg,
+		rootDir:  rootDir,
+		layout:   newLayout(filepath.Join(rootDir, filepath.FromSlash(strings.TrimPrefix(url, "file://")))),
+	}, nil
+}
+
+// Info returns the basic information about the adapter
+func (a *Adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeOCILayout,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// PrepareForPush creates the layout if it doesn't exist
+func (a *Adapter) PrepareForPush([]*model.Resource) error {
+	return a.init()
+}
+
+// HealthCheck checks whether the layout is accessible. The directory layout which doesn't exist
+// is treated as healthy as it will be created when pushing
+func (a *Adapter) HealthCheck() (string, error) {
+	exist, err := a.exist()
+	if err != nil {
+		log.Errorf("failed to check the OCI image layout %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	if !exist {
+		if _, ok := a.storage.(*dirStorage); ok {
+			if info, err := os.Stat(a.rootDir); err == nil && info.IsDir() {
+				return model.Healthy, nil
+			}
+		}
+		log.Errorf("the OCI image layout %s doesn't exist", a.registry.URL)
+		return model.Unhealthy, nil
+	}
+	if _, err = a.readIndex(); err != nil {
+		log.Errorf("failed to read the index of OCI image layout %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// FetchArtifacts lists the artifacts recorded in the index of layout
+func (a *Adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	index, err := a.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	artifacts := map[string][]*model.Artifact{}
+	for _, desc := range index.Manifests {
+		repository, tag := a.parseName(desc)
+		var artifact *model.Artifact
+		for _, art := range artifacts[repository] {
+			if art.Digest == desc.Digest.String() {
+				artifact = art
+				break
+			}
+		}
+		if artifact == nil {
+			artifact = &model.Artifact{
+				Digest: desc.Digest.String(),
+			}
+			artifacts[repository] = append(artifacts[repository], artifact)
+		}
+		if len(tag) > 0 {
+			artifact.Tags = append(artifact.Tags, tag)
+		}
+	}
+
+	var repositories []*model.Repository
+	for name := range artifacts {
+		repositories = append(repositories, &model.Repository{
+			Name: name,
+		})
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+	repositories, err = filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*model.Resource
+	for _, repository := range repositories {
+		arts, err := filter.DoFilterArtifacts(artifacts[repository.Name], filters)
+		if err != nil {
+			return nil, err
+		}
+		if len(arts) == 0 {
+			continue
+		}
+		resources = append(resources, &model.Resource{
+			Type:     model.ResourceTypeImage,
+			Registry: a.registry,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: repository.Name,
+				},
+				Artifacts: arts,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// ManifestExist checks the existence of the manifest
+func (a *Adapter) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	desc, err := a.resolve(repository, reference)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	return true, &distribution.Descriptor{
+		MediaType: desc.MediaType,
+		Digest:    desc.Digest,
+		Size:      desc.Size,
+	}, nil
+}
+
+// PullManifest pulls the manifest referenced by the tag or digest
+func (a *Adapter) PullManifest(repository, reference string, accepttedMediaTypes ...string) (distribution.Manifest, string, error) {
+	desc, err := a.resolve(repository, reference)
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := a.readFile(blobPath(desc.Digest))
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType := desc.MediaType
+	if len(mediaType) == 0 {
+		mediaType = detectMediaType(payload)
+	}
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return manifest, desc.Digest.String(), nil
+}
+
+// PushManifest stores the manifest as a blob and records it in the index. The manifest pushed by
+// digest is recorded as an untagged one and is removed from the index once the index manifest
+// referencing it is pushed
+func (a *Adapter) PushManifest(repository, reference, mediaType string, payload []byte) (string, error) {
+	dgst := digest.FromBytes(payload)
+	ref, err := digest.Parse(reference)
+	isDigest := err == nil
+	if isDigest && ref != dgst {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the digest %s of manifest doesn't match the reference %s", dgst, reference)
+	}
+	if err = a.write(blobPath(dgst), strings.NewReader(string(payload)), nil); err != nil {
+		return "", err
+	}
+
+	var children []digest.Digest
+	if mediaType == v1.MediaTypeImageIndex || mediaType == manifestlist.MediaTypeManifestList {
+		index := &v1.Index{}
+		if err = json.Unmarshal(payload, index); err != nil {
+			return "", err
+		}
+		for _, m := range index.Manifests {
+			children = append(children, m.Digest)
+		}
+	}
+
+	err = a.updateIndex(func(index *v1.Index) error {
+		var manifests []v1.Descriptor
+		exist := false
+		for _, desc := range index.Manifests {
+			repo, tag := a.parseName(desc)
+			if repo == repository {
+				// the tag is moved to the new manifest
+				if !isDigest && tag == reference {
+					continue
+				}
+				// the untagged manifest is referenced by the pushing one or tagged by the pushing reference
+				if len(tag) == 0 && (desc.Digest == dgst && !isDigest || containsDigest(children, desc.Digest)) {
+					continue
+				}
+				if desc.Digest == dgst {
+					exist = true
+				}
+			}
+			manifests = append(manifests, desc)
+		}
+		switch {
+		case !isDigest:
+			manifests = append(manifests, v1.Descriptor{
+				MediaType: mediaType,
+				Digest:    dgst,
+				Size:      int64(len(payload)),
+				Annotations: map[string]string{
+					annotationImageName:  repository + ":" + reference,
+					v1.AnnotationRefName: reference,
+				},
+			})
+		case !exist:
+			manifests = append(manifests, v1.Descriptor{
+				MediaType: mediaType,
+				Digest:    dgst,
+				Size:      int64(len(payload)),
+				Annotations: map[string]string{
+					annotationImageName: repository + "@" + dgst.String(),
+				},
+			})
+		}
+		index.Manifests = manifests
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return dgst.String(), nil
+}
+
+// DeleteManifest removes all the records of the manifest from the index, the blobs are kept
+func (a *Adapter) DeleteManifest(repository, reference string) error {
+	desc, err := a.resolve(repository, reference)
+	if err != nil {
+		return err
+	}
+	return a.updateIndex(func(index *v1.Index) error {
+		var manifests []v1.Descriptor
+		for _, d := range index.Manifests {
+			if repo, _ := a.parseName(d); repo == repository && d.Digest == desc.Digest {
+				continue
+			}
+			manifests = append(manifests, d)
+		}
+		index.Manifests = manifests
+		return nil
+	})
+}
+
+// DeleteTag removes the tag from the index
+func (a *Adapter) DeleteTag(repository, tag string) error {
+	return a.updateIndex(func(index *v1.Index) error {
+		var manifests []v1.Descriptor
+		for _, d := range index.Manifests {
+			if repo, t := a.parseName(d); repo == repository && t == tag {
+				continue
+			}
+			manifests = append(manifests, d)
+		}
+		index.Manifests = manifests
+		return nil
+	})
+}
+
+// BlobExist checks the existence of the blob
+func (a *Adapter) BlobExist(repository, digest string) (bool, error) {
+	dgst, err := parseDigest(digest)
+	if err != nil {
+		return false, err
+	}
+	if _, err = a.stat(blobPath(dgst)); err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// PullBlob pulls the blob
+func (a *Adapter) PullBlob(repository, digest string) (int64, io.ReadCloser, error) {
+	dgst, err := parseDigest(digest)
+	if err != nil {
+		return 0, nil, err
+	}
+	return a.open(blobPath(dgst))
+}
+
+// PushBlob stores the blob, the content is verified against the digest before committing
+func (a *Adapter) PushBlob(repository, digest string, size int64, blob io.Reader) error {
+	dgst, err := parseDigest(digest)
+	if err != nil {
+		return err
+	}
+	verifier := dgst.Verifier()
+	return a.write(blobPath(dgst), io.TeeReader(blob, verifier), func() error {
+		if !verifier.Verified() {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the content of blob doesn't match the digest %s", dgst)
+		}
+		return nil
+	})
+}
+
+// MountBlob isn't supported for OCI image layout
+func (a *Adapter) MountBlob(srcRepository, digest, dstRepository string) error {
+	return errors.New("the blob mount isn't supported")
+}
+
+// CanBeMount isn't supported for OCI image layout
+func (a *Adapter) CanBeMount(digest string) (bool, string, error) {
+	return false, "", nil
+}
+
+func parseDigest(s string) (digest.Digest, error) {
+	dgst, err := digest.Parse(s)
+	if err != nil {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid digest %s: %v", s, err)
+	}
+	return dgst, nil
+}
+
+func containsDigest(digests []digest.Digest, dgst digest.Digest) bool {
+	for _, d := range digests {
+		if d == dgst {
+			return true
+		}
+	}
+	return false
+}
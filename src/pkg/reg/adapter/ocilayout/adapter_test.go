@@ -0,0 +1,303 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type adapterTestSuite struct {
+	suite.Suite
+	rootDir string
+}
+
+func (a *adapterTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "oci-layout")
+	a.Require().Nil(err)
+	a.rootDir = dir
+	a.Require().Nil(os.Setenv(rootDirEnv, dir))
+}
+
+func (a *adapterTestSuite) TearDownTest() {
+	os.Unsetenv(rootDirEnv)
+	os.RemoveAll(a.rootDir)
+}
+
+func (a *adapterTestSuite) newAdapter(url string) *Adapter {
+	adapter, err := NewAdapter(&model.Registry{URL: url})
+	a.Require().Nil(err)
+	return adapter
+}
+
+// pushImage pushes an image with one layer and returns the digest of the manifest
+func (a *adapterTestSuite) pushImage(adapter *Adapter, repository, reference, content string) string {
+	dgst, err := adapter.PushManifest(repository, reference, v1.MediaTypeImageManifest, a.pushBlobs(adapter, repository, content))
+	a.Require().Nil(err)
+	return dgst
+}
+
+// pushBlobs pushes the config and layer of an image and returns the payload of its manifest
+func (a *adapterTestSuite) pushBlobs(adapter *Adapter, repository, content string) []byte {
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	layer := []byte(content)
+	for _, blob := range [][]byte{config, layer} {
+		a.Require().Nil(adapter.PushBlob(repository, digest.FromBytes(blob).String(), int64(len(blob)), strings.NewReader(string(blob))))
+	}
+	manifest := &v1.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config: v1.Descriptor{
+			MediaType: v1.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+		Layers: []v1.Descriptor{
+			{
+				MediaType: v1.MediaTypeImageLayer,
+				Digest:    digest.FromBytes(layer),
+				Size:      int64(len(layer)),
+			},
+		},
+	}
+	payload, err := json.Marshal(manifest)
+	a.Require().Nil(err)
+	return payload
+}
+
+func (a *adapterTestSuite) TestNewAdapter() {
+	_, err := NewAdapter(&model.Registry{URL: "http://127.0.0.1"})
+	a.NotNil(err)
+
+	_, err = NewAdapter(&model.Registry{URL: "file:///a/../../etc"})
+	a.NotNil(err)
+
+	adapter := a.newAdapter("file:///a/b/")
+	a.Equal(filepath.Join(a.rootDir, "a", "b"), adapter.storage.(*dirStorage).root)
+
+	adapter = a.newAdapter("file:///a/b.tar.gz")
+	a.Equal(filepath.Join(a.rootDir, "a", "b.tar.gz"), adapter.storage.(*tarStorage).file)
+	a.Equal("b", adapter.defaultRepository)
+}
+
+func (a *adapterTestSuite) TestHealthCheck() {
+	// the directory doesn't exist but can be created
+	status, err := a.newAdapter("file:///bundle").HealthCheck()
+	a.Require().Nil(err)
+	a.Equal(model.Healthy, status)
+
+	// the tarball doesn't exist
+	status, err = a.newAdapter("file:///bundle.tar").HealthCheck()
+	a.Require().Nil(err)
+	a.Equal(model.Unhealthy, status)
+}
+
+func (a *adapterTestSuite) TestPushAndPull() {
+	adapter := a.newAdapter("file:///bundle")
+	a.Require().Nil(adapter.PrepareForPush(nil))
+	_, err := os.Stat(filepath.Join(a.rootDir, "bundle", v1.ImageLayoutFile))
+	a.Require().Nil(err)
+
+	dgst := a.pushImage(adapter, "library/hello-world", "latest", "layer1")
+	_, err = adapter.PushManifest("library/hello-world", "v1", v1.MediaTypeImageManifest,
+		a.readBlob(adapter, dgst))
+	a.Require().Nil(err)
+	dgst2 := a.pushImage(adapter, "library/busybox", "1.0", "layer2")
+
+	// the mismatched blob is rejected
+	err = adapter.PushBlob("library/busybox", digest.FromString("abc").String(), 3, strings.NewReader("def"))
+	a.NotNil(err)
+	exist, err := adapter.BlobExist("library/busybox", digest.FromString("abc").String())
+	a.Require().Nil(err)
+	a.False(exist)
+
+	resources, err := adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 2)
+	a.Equal("library/busybox", resources[0].Metadata.Repository.Name)
+	a.Equal(dgst2, resources[0].Metadata.Artifacts[0].Digest)
+	a.Equal("library/hello-world", resources[1].Metadata.Repository.Name)
+	a.Require().Len(resources[1].Metadata.Artifacts, 1)
+	a.Equal(dgst, resources[1].Metadata.Artifacts[0].Digest)
+	a.Equal([]string{"latest", "v1"}, resources[1].Metadata.Artifacts[0].Tags)
+
+	resources, err = adapter.FetchArtifacts([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/hello-*",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "v1",
+		},
+	})
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Equal([]string{"v1"}, resources[0].Metadata.Artifacts[0].Tags)
+
+	exist, desc, err := adapter.ManifestExist("library/hello-world", "latest")
+	a.Require().Nil(err)
+	a.True(exist)
+	a.Equal(dgst, desc.Digest.String())
+	exist, _, err = adapter.ManifestExist("library/hello-world", "2.0")
+	a.Require().Nil(err)
+	a.False(exist)
+
+	manifest, d, err := adapter.PullManifest("library/busybox", dgst2)
+	a.Require().Nil(err)
+	a.Equal(dgst2, d)
+	a.Len(manifest.References(), 2)
+	size, blob, err := adapter.PullBlob("library/busybox", manifest.References()[1].Digest.String())
+	a.Require().Nil(err)
+	defer blob.Close()
+	data, err := ioutil.ReadAll(blob)
+	a.Require().Nil(err)
+	a.Equal(int64(6), size)
+	a.Equal("layer2", string(data))
+
+	a.Require().Nil(adapter.DeleteTag("library/hello-world", "v1"))
+	exist, _, err = adapter.ManifestExist("library/hello-world", "v1")
+	a.Require().Nil(err)
+	a.False(exist)
+
+	a.Require().Nil(adapter.DeleteManifest("library/busybox", "1.0"))
+	resources, err = adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Equal("library/hello-world", resources[0].Metadata.Repository.Name)
+}
+
+func (a *adapterTestSuite) TestPushIndex() {
+	adapter := a.newAdapter("file:///bundle")
+	a.Require().Nil(adapter.PrepareForPush(nil))
+
+	// the children are pushed by digest before the index
+	var manifests []v1.Descriptor
+	for _, content := range []string{"amd64", "arm64"} {
+		payload := a.pushBlobs(adapter, "library/hello-world", content)
+		dgst, err := adapter.PushManifest("library/hello-world", digest.FromBytes(payload).String(), v1.MediaTypeImageManifest, payload)
+		a.Require().Nil(err)
+		manifests = append(manifests, v1.Descriptor{
+			MediaType: v1.MediaTypeImageManifest,
+			Digest:    digest.Digest(dgst),
+			Size:      int64(len(payload)),
+		})
+	}
+	resources, err := adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Len(resources[0].Metadata.Artifacts, 2)
+
+	payload, err := json.Marshal(&v1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: manifests,
+	})
+	a.Require().Nil(err)
+	dgst, err := adapter.PushManifest("library/hello-world", "latest", v1.MediaTypeImageIndex, payload)
+	a.Require().Nil(err)
+
+	// only the index is listed, the children can still be pulled by digest
+	resources, err = adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Require().Len(resources[0].Metadata.Artifacts, 1)
+	a.Equal(dgst, resources[0].Metadata.Artifacts[0].Digest)
+	manifest, _, err := adapter.PullManifest("library/hello-world", manifests[0].Digest.String())
+	a.Require().Nil(err)
+	a.Len(manifest.References(), 2)
+}
+
+func (a *adapterTestSuite) TestTarball() {
+	adapter := a.newAdapter("file:///bundle")
+	a.Require().Nil(adapter.PrepareForPush(nil))
+	dgst := a.pushImage(adapter, "library/hello-world", "latest", "layer1")
+
+	// archive the layout and rewrite the index with the "org.opencontainers.image.ref.name" annotation only
+	index, err := adapter.readIndex()
+	a.Require().Nil(err)
+	index.Manifests[0].Annotations = map[string]string{v1.AnnotationRefName: "1.0"}
+	indexData, err := json.Marshal(index)
+	a.Require().Nil(err)
+	file, err := os.Create(filepath.Join(a.rootDir, "hello-world.tar.gz"))
+	a.Require().Nil(err)
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+	root := filepath.Join(a.rootDir, "bundle")
+	err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, _ := filepath.Rel(root, p)
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if name == indexFile {
+			data = indexData
+		}
+		if err = tw.WriteHeader(&tar.Header{Name: "./" + filepath.ToSlash(name), Mode: 0644, Size: int64(len(data))}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	a.Require().Nil(err)
+	a.Require().Nil(tw.Close())
+	a.Require().Nil(gw.Close())
+	a.Require().Nil(file.Close())
+
+	adapter = a.newAdapter("file:///hello-world.tar.gz")
+	status, err := adapter.HealthCheck()
+	a.Require().Nil(err)
+	a.Equal(model.Healthy, status)
+
+	resources, err := adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Equal("hello-world", resources[0].Metadata.Repository.Name)
+	a.Equal([]string{"1.0"}, resources[0].Metadata.Artifacts[0].Tags)
+
+	manifest, d, err := adapter.PullManifest("hello-world", "1.0")
+	a.Require().Nil(err)
+	a.Equal(dgst, d)
+	exist, err := adapter.BlobExist("hello-world", manifest.References()[1].Digest.String())
+	a.Require().Nil(err)
+	a.True(exist)
+
+	// the tarball is read only
+	a.NotNil(adapter.PrepareForPush(nil))
+	a.NotNil(adapter.PushBlob("hello-world", digest.FromString("abc").String(), 3, strings.NewReader("abc")))
+}
+
+func (a *adapterTestSuite) readBlob(adapter *Adapter, dgst string) []byte {
+	data, err := adapter.readFile(blobPath(digest.Digest(dgst)))
+	a.Require().Nil(err)
+	return data
+}
+
+func TestAdapterTestSuite(t *testing.T) {
+	suite.Run(t, &adapterTestSuite{})
+}
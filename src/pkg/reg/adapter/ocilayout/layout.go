@@ -0,0 +1,549 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	indexFile = "index.json"
+	blobsDir  = "blobs"
+	// annotationImageName records the full reference("repository:tag" or "repository@digest")
+	// of the manifest, the annotation is the same as the one used by containerd
+	annotationImageName = "io.containerd.image.name"
+	// lockFile is locked exclusively when updating the "index.json", the lock is held by
+	// the file descriptor so the core and jobservice sharing the layout exclude each other
+	lockFile = ".index.lock"
+)
+
+var (
+	// tarIndexes caches the indexes of the tarballs by the path
+	tarIndexes = struct {
+		sync.Mutex
+		m map[string]*tarIndex
+	}{m: map[string]*tarIndex{}}
+
+	errReadOnly = errors.New(nil).WithCode(errors.MethodNotAllowedCode).
+			WithMessage("the OCI image layout tarball is read only")
+)
+
+// storage abstracts the access to the files of an OCI image layout, the names
+// passed to the methods are the slash separated paths relative to the layout root
+type storage interface {
+	// exist checks whether the layout exists
+	exist() (bool, error)
+	// init creates the layout if it doesn't exist
+	init() error
+	// stat returns the size of the file
+	stat(name string) (int64, error)
+	// open opens the file for reading
+	open(name string) (int64, io.ReadCloser, error)
+	// write writes the content into the file atomically, the file is only committed
+	// when the verify function returns nil
+	write(name string, content io.Reader, verify func() error) error
+	// lock locks the layout exclusively across processes, returns the function to unlock
+	lock() (func(), error)
+}
+
+func isTarball(p string) bool {
+	return strings.HasSuffix(p, ".tar") || strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+func newStorage(p string) storage {
+	if isTarball(p) {
+		return &tarStorage{file: p}
+	}
+	return &dirStorage{root: p}
+}
+
+// dirStorage stores the layout in a directory, it's readable and writable
+type dirStorage struct {
+	root string
+}
+
+func (d *dirStorage) path(name string) string {
+	return filepath.Join(d.root, filepath.FromSlash(name))
+}
+
+func (d *dirStorage) exist() (bool, error) {
+	info, err := os.Stat(d.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, errors.Errorf("%s isn't a directory", d.root)
+	}
+	return true, nil
+}
+
+func (d *dirStorage) init() error {
+	if err := os.MkdirAll(d.path(blobsDir), 0755); err != nil {
+		return err
+	}
+	if _, err := d.stat(v1.ImageLayoutFile); err != nil {
+		if !errors.IsNotFoundErr(err) {
+			return err
+		}
+		data, err := json.Marshal(&v1.ImageLayout{Version: v1.ImageLayoutVersion})
+		if err != nil {
+			return err
+		}
+		if err = d.write(v1.ImageLayoutFile, strings.NewReader(string(data)), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dirStorage) stat(name string) (int64, error) {
+	info, err := os.Stat(d.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, errors.NotFoundError(nil).WithMessage("%s not found", name)
+		}
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (d *dirStorage) open(name string) (int64, io.ReadCloser, error) {
+	file, err := os.Open(d.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil, errors.NotFoundError(nil).WithMessage("%s not found", name)
+		}
+		return 0, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, nil, err
+	}
+	return info.Size(), file, nil
+}
+
+func (d *dirStorage) write(name string, content io.Reader, verify func() error) error {
+	dir := filepath.Dir(d.path(name))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	// the temporary file is removed if it isn't renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if verify != nil {
+		if err = verify(); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), d.path(name))
+}
+
+func (d *dirStorage) lock() (func(), error) {
+	if err := os.MkdirAll(d.root, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(d.path(lockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "failed to lock %s", d.root)
+	}
+	return func() {
+		// closing the file releases the lock as well
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// tarStorage reads the layout from a tarball(optionally gzipped), it's read only
+type tarStorage struct {
+	file string
+}
+
+func (t *tarStorage) exist() (bool, error) {
+	info, err := os.Stat(t.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !info.Mode().IsRegular() {
+		return false, errors.Errorf("%s isn't a regular file", t.file)
+	}
+	return true, nil
+}
+
+func (t *tarStorage) init() error {
+	return errReadOnly
+}
+
+func (t *tarStorage) stat(name string) (int64, error) {
+	index, err := t.index()
+	if err != nil {
+		return 0, err
+	}
+	entry, exist := index.entries[name]
+	if !exist {
+		return 0, errors.NotFoundError(nil).WithMessage("%s not found in %s", name, t.file)
+	}
+	return entry.size, nil
+}
+
+// open reads the file content directly from the tarball by the offset recorded in the index
+func (t *tarStorage) open(name string) (int64, io.ReadCloser, error) {
+	index, err := t.index()
+	if err != nil {
+		return 0, nil, err
+	}
+	entry, exist := index.entries[name]
+	if !exist {
+		return 0, nil, errors.NotFoundError(nil).WithMessage("%s not found in %s", name, t.file)
+	}
+	file, err := os.Open(index.file)
+	if err != nil {
+		return 0, nil, err
+	}
+	return entry.size, &tarFileReader{
+		Reader:  io.NewSectionReader(file, entry.offset, entry.size),
+		closers: []io.Closer{file},
+	}, nil
+}
+
+// index returns the index of the tarball, it's built once and rebuilt only when the tarball is changed
+func (t *tarStorage) index() (*tarIndex, error) {
+	info, err := os.Stat(t.file)
+	if err != nil {
+		return nil, err
+	}
+	tarIndexes.Lock()
+	defer tarIndexes.Unlock()
+	index, exist := tarIndexes.m[t.file]
+	if exist && index.modTime.Equal(info.ModTime()) && index.size == info.Size() {
+		return index, nil
+	}
+	if exist {
+		index.clean()
+		delete(tarIndexes.m, t.file)
+	}
+	if index, err = buildTarIndex(t.file); err != nil {
+		return nil, err
+	}
+	index.modTime, index.size = info.ModTime(), info.Size()
+	tarIndexes.m[t.file] = index
+	return index, nil
+}
+
+func (t *tarStorage) write(name string, content io.Reader, verify func() error) error {
+	return errReadOnly
+}
+
+func (t *tarStorage) lock() (func(), error) {
+	return nil, errReadOnly
+}
+
+// tarIndex records the offset and size of the regular files in the uncompressed tarball
+type tarIndex struct {
+	modTime time.Time
+	size    int64
+	// file is the uncompressed tarball, it's a temporary file if the tarball is gzipped
+	file      string
+	temporary bool
+	entries   map[string]tarEntry
+}
+
+type tarEntry struct {
+	offset int64
+	size   int64
+}
+
+func (t *tarIndex) clean() {
+	if t.temporary {
+		// the readers opened already can still read the removed file
+		os.Remove(t.file)
+	}
+}
+
+// buildTarIndex scans the tarball once, the gzipped tarball is decompressed into a temporary
+// file first so that the files can be read by offset
+func buildTarIndex(p string) (*tarIndex, error) {
+	index := &tarIndex{
+		file:    p,
+		entries: map[string]tarEntry{},
+	}
+	if !strings.HasSuffix(p, ".tar") {
+		file, err := decompress(p)
+		if err != nil {
+			return nil, err
+		}
+		index.file, index.temporary = file, true
+	}
+
+	file, err := os.Open(index.file)
+	if err != nil {
+		index.clean()
+		return nil, err
+	}
+	defer file.Close()
+	// the tar reader seeks over the file content, so the current position of the file
+	// is the offset of the content after reading the header
+	tr := tar.NewReader(file)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return index, nil
+		}
+		if err != nil {
+			index.clean()
+			return nil, errors.Wrapf(err, "failed to read %s", p)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			index.clean()
+			return nil, err
+		}
+		index.entries[strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")] = tarEntry{
+			offset: offset,
+			size:   hdr.Size,
+		}
+	}
+}
+
+// decompress the gzipped tarball into a temporary file, returns the path of the file
+func decompress(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+	tmp, err := ioutil.TempFile("", "oci-layout-*.tar")
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(tmp, gz); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", errors.Wrapf(err, "failed to decompress %s", p)
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+type tarFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (t *tarFileReader) Close() error {
+	var err error
+	for i := len(t.closers) - 1; i >= 0; i-- {
+		if e := t.closers[i].Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// layout wraps the storage and provides the operations on the index and blobs
+type layout struct {
+	storage
+	// the repository name used for the manifests in index which only have the
+	// "org.opencontainers.image.ref.name" annotation
+	defaultRepository string
+}
+
+func newLayout(p string) *layout {
+	name := filepath.Base(p)
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar"} {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	return &layout{
+		storage:           newStorage(p),
+		defaultRepository: name,
+	}
+}
+
+func blobPath(dgst digest.Digest) string {
+	return path.Join(blobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+func (l *layout) readFile(name string) ([]byte, error) {
+	_, reader, err := l.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// readIndex returns an empty index if the "index.json" doesn't exist
+func (l *layout) readIndex() (*v1.Index, error) {
+	index := &v1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+	}
+	data, err := l.readFile(indexFile)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return index, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", indexFile)
+	}
+	return index, nil
+}
+
+// updateIndex reads the index, updates it by the provided function and writes it back
+func (l *layout) updateIndex(update func(index *v1.Index) error) error {
+	unlock, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := l.readIndex()
+	if err != nil {
+		return err
+	}
+	if err = update(index); err != nil {
+		return err
+	}
+	if index.Manifests == nil {
+		index.Manifests = []v1.Descriptor{}
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return l.write(indexFile, strings.NewReader(string(data)), nil)
+}
+
+// parseName returns the repository and tag of the manifest in index, the tag is empty for untagged manifest
+func (l *layout) parseName(desc v1.Descriptor) (string, string) {
+	if name := desc.Annotations[annotationImageName]; len(name) > 0 {
+		if i := strings.LastIndex(name, "@"); i > 0 {
+			return name[:i], ""
+		}
+		if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+			return name[:i], name[i+1:]
+		}
+		return name, ""
+	}
+	return l.defaultRepository, desc.Annotations[v1.AnnotationRefName]
+}
+
+// resolve returns the descriptor of the manifest referenced by the tag or digest
+func (l *layout) resolve(repository, reference string) (*v1.Descriptor, error) {
+	index, err := l.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	dgst, err := digest.Parse(reference)
+	isDigest := err == nil
+	for _, desc := range index.Manifests {
+		repo, tag := l.parseName(desc)
+		if repo != repository {
+			continue
+		}
+		if (isDigest && desc.Digest == dgst) || (!isDigest && tag == reference) {
+			d := desc
+			return &d, nil
+		}
+	}
+	// the manifests referenced by index manifest are only stored as blobs
+	if isDigest {
+		payload, err := l.readFile(blobPath(dgst))
+		if err != nil {
+			return nil, err
+		}
+		return &v1.Descriptor{
+			MediaType: detectMediaType(payload),
+			Digest:    dgst,
+			Size:      int64(len(payload)),
+		}, nil
+	}
+	return nil, errors.NotFoundError(nil).WithMessage("%s:%s not found", repository, reference)
+}
+
+// detectMediaType detects the media type of the manifest when it isn't recorded in index
+func detectMediaType(payload []byte) string {
+	m := &struct {
+		SchemaVersion int             `json:"schemaVersion"`
+		MediaType     string          `json:"mediaType"`
+		Manifests     json.RawMessage `json:"manifests"`
+	}{}
+	if err := json.Unmarshal(payload, m); err != nil {
+		return ""
+	}
+	switch {
+	case len(m.MediaType) > 0:
+		return m.MediaType
+	case m.SchemaVersion == 1:
+		return schema1.MediaTypeSignedManifest
+	case m.Manifests != nil:
+		return v1.MediaTypeImageIndex
+	default:
+		return v1.MediaTypeImageManifest
+	}
+}
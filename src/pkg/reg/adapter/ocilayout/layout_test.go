@@ -0,0 +1,128 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTarball(t *testing.T, p string, files map[string]string) {
+	file, err := os.Create(p)
+	require.Nil(t, err)
+	defer file.Close()
+	var w io.Writer = file
+	if filepath.Ext(p) == ".gz" {
+		gw := gzip.NewWriter(file)
+		defer gw.Close()
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+	for name, content := range files {
+		require.Nil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err = tw.Write([]byte(content))
+		require.Nil(t, err)
+	}
+}
+
+func TestTarStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"layout.tar", "layout.tar.gz"} {
+		p := filepath.Join(dir, name)
+		writeTarball(t, p, map[string]string{"./index.json": "{}", "blobs/sha256/a": "aaa"})
+		storage := &tarStorage{file: p}
+
+		size, err := storage.stat("blobs/sha256/a")
+		require.Nil(t, err)
+		assert.Equal(t, int64(3), size)
+		size, reader, err := storage.open("index.json")
+		require.Nil(t, err)
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		require.Nil(t, err)
+		assert.Equal(t, int64(2), size)
+		assert.Equal(t, "{}", string(data))
+		_, err = storage.stat("blobs/sha256/b")
+		assert.True(t, errors.IsNotFoundErr(err))
+
+		// the index is built only once
+		index, err := storage.index()
+		require.Nil(t, err)
+		assert.Equal(t, name == "layout.tar.gz", index.temporary)
+		again, err := storage.index()
+		require.Nil(t, err)
+		assert.True(t, index == again)
+
+		// the index is rebuilt after the tarball is changed
+		writeTarball(t, p, map[string]string{"blobs/sha256/b": "bbbb"})
+		require.Nil(t, os.Chtimes(p, time.Now(), time.Now().Add(time.Minute)))
+		size, err = storage.stat("blobs/sha256/b")
+		require.Nil(t, err)
+		assert.Equal(t, int64(4), size)
+		_, err = storage.stat("blobs/sha256/a")
+		assert.True(t, errors.IsNotFoundErr(err))
+		if index.temporary {
+			_, err = os.Stat(index.file)
+			assert.True(t, os.IsNotExist(err))
+		}
+		tarIndexes.Lock()
+		tarIndexes.m[p].clean()
+		tarIndexes.Unlock()
+	}
+}
+
+func TestDirStorageLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// the lock is held by the file descriptor, so the storages exclude each other
+	// even if they are in the same process
+	unlock, err := (&dirStorage{root: dir}).lock()
+	require.Nil(t, err)
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := (&dirStorage{root: dir}).lock()
+		if err == nil {
+			unlock()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("the layout shouldn't be locked twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the layout should be locked after unlocking")
+	}
+}
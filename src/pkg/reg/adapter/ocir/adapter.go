@@ -0,0 +1,367 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// !!!! Limits:
+// - OCIR authenticates the registry API with the user name in format "<tenancy-namespace>/<username>"
+//   (or "<tenancy-namespace>/oracleidentitycloudservice/<username>" for federated users) and an auth token
+//   as the password, the token service exchanges them for the bearer token.
+// - All the repositories are under the tenancy namespace. With the auth token only, the repositories are
+//   listed by the catalog which contains the ones in the compartments that the user is granted to read,
+//   and the repositories created by pushing are placed in the root compartment. Specify the compartment
+//   and the API signing key in the credential(refer to the "cred") to list the repositories in the
+//   compartment and create the missing ones in it through the OCI Artifacts API.
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeOCIR, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeOCIR, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeOCIR)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return getAdapterInfo()
+}
+
+// the region identifier -> the region key used in the OCIR endpoint
+var regions = [][2]string{
+	{"af-johannesburg-1", "jnb"},
+	{"ap-chuncheon-1", "yny"},
+	{"ap-hyderabad-1", "hyd"},
+	{"ap-melbourne-1", "mel"},
+	{"ap-mumbai-1", "bom"},
+	{"ap-osaka-1", "kix"},
+	{"ap-seoul-1", "icn"},
+	{"ap-singapore-1", "sin"},
+	{"ap-sydney-1", "syd"},
+	{"ap-tokyo-1", "nrt"},
+	{"ca-montreal-1", "yul"},
+	{"ca-toronto-1", "yyz"},
+	{"eu-amsterdam-1", "ams"},
+	{"eu-frankfurt-1", "fra"},
+	{"eu-madrid-1", "mad"},
+	{"eu-marseille-1", "mrs"},
+	{"eu-milan-1", "lin"},
+	{"eu-paris-1", "cdg"},
+	{"eu-stockholm-1", "arn"},
+	{"eu-zurich-1", "zrh"},
+	{"il-jerusalem-1", "mtz"},
+	{"me-abudhabi-1", "auh"},
+	{"me-dubai-1", "dxb"},
+	{"me-jeddah-1", "jed"},
+	{"mx-queretaro-1", "qro"},
+	{"sa-santiago-1", "scl"},
+	{"sa-saopaulo-1", "gru"},
+	{"sa-vinhedo-1", "vcp"},
+	{"uk-cardiff-1", "cwl"},
+	{"uk-london-1", "lhr"},
+	{"us-ashburn-1", "iad"},
+	{"us-chicago-1", "ord"},
+	{"us-phoenix-1", "phx"},
+	{"us-sanjose-1", "sjc"},
+}
+
+func getAdapterInfo() *model.AdapterPattern {
+	var endpoints []*model.Endpoint
+	for _, region := range regions {
+		endpoints = append(endpoints, &model.Endpoint{
+			Key:   region[0],
+			Value: fmt.Sprintf("https://%s.ocir.io", region[1]),
+		})
+	}
+	return &model.AdapterPattern{
+		EndpointPattern: &model.EndpointPattern{
+			EndpointType: model.EndpointPatternTypeList,
+			Endpoints:    endpoints,
+		},
+	}
+}
+
+var (
+	_ adp.Adapter          = (*adapter)(nil)
+	_ adp.ArtifactRegistry = (*adapter)(nil)
+)
+
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	// the tenancy namespace parsed from the user name, all the repositories are under it
+	namespace string
+	// artifacts is nil if the compartment isn't specified in the credential
+	artifacts *artifactsClient
+}
+
+func newAdapter(registry *model.Registry) (*adapter, error) {
+	a := &adapter{
+		registry: registry,
+	}
+	if registry.Credential == nil {
+		a.Adapter = native.NewAdapter(registry)
+		return a, nil
+	}
+
+	if len(registry.Credential.AccessKey) > 0 {
+		namespace, err := parseNamespace(registry.Credential.AccessKey)
+		if err != nil {
+			return nil, err
+		}
+		a.namespace = namespace
+	}
+	c, err := parseCred(registry.Credential.AccessSecret)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.CompartmentID) > 0 {
+		if len(a.namespace) == 0 {
+			return nil, errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the user name of OCIR is required to access the compartment %s", c.CompartmentID)
+		}
+		if a.artifacts, err = newArtifactsClient(registry.URL, registry.Insecure, c); err != nil {
+			return nil, err
+		}
+	}
+	// the registry API is authenticated by the auth token only
+	reg := *registry
+	reg.Credential = &model.Credential{
+		Type:         registry.Credential.Type,
+		AccessKey:    registry.Credential.AccessKey,
+		AccessSecret: c.AuthToken,
+	}
+	a.Adapter = native.NewAdapter(&reg)
+	return a, nil
+}
+
+// parseNamespace parses the tenancy namespace from the user name in format
+// "<tenancy-namespace>/<username>" or "<tenancy-namespace>/oracleidentitycloudservice/<username>"
+func parseNamespace(username string) (string, error) {
+	parts := strings.Split(username, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the user name of OCIR must be in format <tenancy-namespace>/<username>: %s", username)
+	}
+	for _, part := range parts {
+		if len(part) == 0 {
+			return "", errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the user name of OCIR must be in format <tenancy-namespace>/<username>: %s", username)
+		}
+	}
+	return parts[0], nil
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeOCIR,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+		SupportedRepositoryPathComponentType: model.RepositoryPathComponentTypeAtLeastTwo,
+	}, nil
+}
+
+// HealthCheck checks health status of the registry, the credential is required as
+// OCIR doesn't allow anonymous access to the registry API
+func (a *adapter) HealthCheck() (string, error) {
+	if a.registry.Credential == nil ||
+		len(a.registry.Credential.AccessKey) == 0 || len(a.registry.Credential.AccessSecret) == 0 {
+		log.Errorf("no credential to ping registry %s", a.registry.URL)
+		return model.Unhealthy, nil
+	}
+	if err := a.Ping(); err != nil {
+		log.Errorf("failed to ping registry %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// PrepareForPush checks whether the repositories are under the tenancy namespace. OCIR creates
+// the repositories automatically in the root compartment when pushing, so the repositories are
+// created in the compartment beforehand if it is specified
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	repositories := map[string]struct{}{}
+	for _, resource := range resources {
+		if resource == nil {
+			return errors.New("the resource cannot be null")
+		}
+		if resource.Metadata == nil {
+			return errors.New("the metadata of resource cannot be null")
+		}
+		if resource.Metadata.Repository == nil {
+			return errors.New("the repository of resource cannot be null")
+		}
+		name := resource.Metadata.Repository.Name
+		if len(a.namespace) > 0 && !strings.HasPrefix(name, a.namespace+"/") {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the repository %s isn't under the tenancy namespace %s of OCIR", name, a.namespace)
+		}
+		repositories[strings.TrimPrefix(name, a.namespace+"/")] = struct{}{}
+	}
+	if a.artifacts == nil {
+		return nil
+	}
+	for repository := range repositories {
+		if err := a.artifacts.createRepository(repository); err != nil {
+			return fmt.Errorf("failed to create the repository %s in compartment %s: %v", repository, a.artifacts.compartmentID, err)
+		}
+		log.Debugf("the repository %s is created in compartment %s", repository, a.artifacts.compartmentID)
+	}
+	return nil
+}
+
+// FetchArtifacts lists the artifacts of the repositories under the tenancy namespace
+func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	repositories, err := a.listRepositories(filters)
+	if err != nil {
+		return nil, err
+	}
+	if len(repositories) == 0 {
+		return nil, nil
+	}
+
+	var rawResources = make([]*model.Resource, len(repositories))
+	runner := utils.NewLimitedConcurrentRunner(adp.MaxConcurrency)
+
+	for i, r := range repositories {
+		index := i
+		repo := r
+		runner.AddTask(func() error {
+			artifacts, err := a.listArtifacts(repo.Name, filters)
+			if err != nil {
+				return fmt.Errorf("failed to list artifacts of repository %s: %v", repo.Name, err)
+			}
+			if len(artifacts) == 0 {
+				return nil
+			}
+			rawResources[index] = &model.Resource{
+				Type:     model.ResourceTypeImage,
+				Registry: a.registry,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: repo.Name,
+					},
+					Artifacts: artifacts,
+				},
+			}
+			return nil
+		})
+	}
+	if err = runner.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch artifacts: %v", err)
+	}
+
+	var resources []*model.Resource
+	for _, r := range rawResources {
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+func (a *adapter) listRepositories(filters []*model.Filter) ([]*model.Repository, error) {
+	pattern := ""
+	for _, filter := range filters {
+		if filter.Type == model.FilterTypeName {
+			pattern = filter.Value.(string)
+			break
+		}
+	}
+	var repositories []string
+	// if the pattern of repository name filter is a specific repository name, just returns
+	// the parsed repositories and will check the existence later when filtering the tags
+	if paths, ok := util.IsSpecificPath(pattern); ok {
+		repositories = paths
+	} else if a.artifacts != nil {
+		// the names of the repositories listed by the OCI Artifacts API don't contain the namespace
+		names, err := a.artifacts.listRepositories()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			repositories = append(repositories, a.namespace+"/"+name)
+		}
+	} else {
+		// the catalog only contains the repositories in the compartments that the user can read
+		catalog, err := a.Catalog()
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range catalog {
+			if len(a.namespace) > 0 && !strings.HasPrefix(repository, a.namespace+"/") {
+				continue
+			}
+			repositories = append(repositories, repository)
+		}
+	}
+
+	var result []*model.Repository
+	for _, repository := range repositories {
+		result = append(result, &model.Repository{
+			Name: repository,
+		})
+	}
+	return filter.DoFilterRepositories(result, filters)
+}
+
+func (a *adapter) listArtifacts(repository string, filters []*model.Filter) ([]*model.Artifact, error) {
+	tags, err := a.ListTags(repository)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []*model.Artifact
+	for _, tag := range tags {
+		artifacts = append(artifacts, &model.Artifact{
+			Tags: []string{tag},
+		})
+	}
+	return filter.DoFilterArtifacts(artifacts, filters)
+}
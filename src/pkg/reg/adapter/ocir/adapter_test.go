@@ -0,0 +1,387 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocir
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	username  = "tenancy/jdoe"
+	authToken = "auth-token"
+	token     = "bearer-token"
+)
+
+// mockOCIR is a stand-in of OCIR which authenticates the registry API with the
+// bearer token exchanged from the auth token by the token service
+func mockOCIR() *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/20180419/docker/token" {
+			user, password, ok := r.BasicAuth()
+			if !ok || user != username || password != authToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": token})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("Www-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/20180419/docker/token",service="%s"`, server.URL, strings.TrimPrefix(server.URL, "http://")))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/_catalog":
+			w.Write([]byte(`{"repositories":["tenancy/app/web","tenancy/busybox","other/busybox"]}`))
+		case "/v2/tenancy/app/web/tags/list":
+			w.Write([]byte(`{"name":"tenancy/app/web","tags":["v1","latest"]}`))
+		case "/v2/tenancy/busybox/tags/list":
+			w.Write([]byte(`{"name":"tenancy/busybox","tags":["1.0"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func newMockAdapter(t *testing.T, url, user, password string) *adapter {
+	registry := &model.Registry{
+		Type: model.RegistryTypeOCIR,
+		URL:  url,
+	}
+	if len(user) > 0 {
+		registry.Credential = &model.Credential{
+			AccessKey:    user,
+			AccessSecret: password,
+		}
+	}
+	a, err := newAdapter(registry)
+	require.Nil(t, err)
+	return a
+}
+
+func TestFactory(t *testing.T) {
+	factory, err := adp.GetFactory(model.RegistryTypeOCIR)
+	require.Nil(t, err)
+	pattern := factory.AdapterPattern()
+	require.NotNil(t, pattern)
+	assert.Equal(t, model.EndpointPatternTypeList, pattern.EndpointPattern.EndpointType)
+	found := false
+	for _, endpoint := range pattern.EndpointPattern.Endpoints {
+		if endpoint.Key == "us-ashburn-1" {
+			assert.Equal(t, "https://iad.ocir.io", endpoint.Value)
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	// invalid user name
+	_, err = factory.Create(&model.Registry{
+		Type: model.RegistryTypeOCIR,
+		URL:  "https://iad.ocir.io",
+		Credential: &model.Credential{
+			AccessKey:    "jdoe",
+			AccessSecret: authToken,
+		},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestParseNamespace(t *testing.T) {
+	namespace, err := parseNamespace("tenancy/jdoe")
+	require.Nil(t, err)
+	assert.Equal(t, "tenancy", namespace)
+
+	namespace, err = parseNamespace("tenancy/oracleidentitycloudservice/jdoe@example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "tenancy", namespace)
+
+	_, err = parseNamespace("tenancy/")
+	assert.NotNil(t, err)
+}
+
+func TestHealthCheck(t *testing.T) {
+	server := mockOCIR()
+	defer server.Close()
+
+	// no credential
+	status, err := newMockAdapter(t, server.URL, "", "").HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Unhealthy, status)
+
+	// invalid auth token
+	status, err = newMockAdapter(t, server.URL, username, "invalid").HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Unhealthy, status)
+
+	status, err = newMockAdapter(t, server.URL, username, authToken).HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+}
+
+func TestFetchArtifacts(t *testing.T) {
+	server := mockOCIR()
+	defer server.Close()
+	a := newMockAdapter(t, server.URL, username, authToken)
+
+	// only the repositories under the tenancy namespace are listed
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "tenancy/app/web", resources[0].Metadata.Repository.Name)
+	assert.Len(t, resources[0].Metadata.Artifacts, 2)
+	assert.Equal(t, "tenancy/busybox", resources[1].Metadata.Repository.Name)
+
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "tenancy/app/**",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "v*",
+		},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	require.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, []string{"v1"}, resources[0].Metadata.Artifacts[0].Tags)
+}
+
+func TestPrepareForPush(t *testing.T) {
+	a := newMockAdapter(t, "https://iad.ocir.io", username, authToken)
+	err := a.PrepareForPush([]*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "tenancy/busybox",
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = a.PrepareForPush([]*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+			},
+		},
+	})
+	assert.NotNil(t, err)
+}
+
+// mockArtifactsAPI is a stand-in of the OCI Artifacts API which verifies the signature of requests
+func mockArtifactsAPI(t *testing.T, key *rsa.PrivateKey, created *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifySignature(r, &key.PublicKey) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/20160918/container/repositories" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "ocid1.compartment.oc1..dev", r.URL.Query().Get("compartmentId"))
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("opc-next-page", "2")
+				w.Write([]byte(`{"items":[{"displayName":"app/web","compartmentId":"ocid1.compartment.oc1..dev"}]}`))
+				return
+			}
+			w.Write([]byte(`{"items":[{"displayName":"busybox","compartmentId":"ocid1.compartment.oc1..dev"}]}`))
+		case http.MethodPost:
+			repo := &repository{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(repo))
+			assert.Equal(t, "ocid1.compartment.oc1..dev", repo.CompartmentID)
+			if repo.DisplayName == "busybox" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			*created = append(*created, repo.DisplayName)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+}
+
+func verifySignature(r *http.Request, key *rsa.PublicKey) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Signature ") {
+		return false
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(auth, "Signature "), ",") {
+		kv := strings.SplitN(param, "=", 2)
+		params[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+	if params["keyId"] != "ocid1.tenancy.oc1..t/ocid1.user.oc1..u/12:34" {
+		return false
+	}
+	var lines []string
+	for _, header := range strings.Split(params["headers"], " ") {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(r.Method), r.URL.RequestURI()))
+		case "host":
+			lines = append(lines, fmt.Sprintf("host: %s", r.Host))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", header, r.Header.Get(header)))
+		}
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+}
+
+func newCompartmentAdapter(t *testing.T, registryURL, artifactsURL string, key *rsa.PrivateKey) *adapter {
+	endpoint := artifactsEndpoint
+	defer func() {
+		artifactsEndpoint = endpoint
+	}()
+	artifactsEndpoint = func(string) (string, error) {
+		return artifactsURL, nil
+	}
+	secret, err := json.Marshal(&cred{
+		AuthToken:     authToken,
+		CompartmentID: "ocid1.compartment.oc1..dev",
+		TenancyID:     "ocid1.tenancy.oc1..t",
+		UserID:        "ocid1.user.oc1..u",
+		Fingerprint:   "12:34",
+		PrivateKey:    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+	})
+	require.Nil(t, err)
+	return newMockAdapter(t, registryURL, username, string(secret))
+}
+
+func TestParseCred(t *testing.T) {
+	c, err := parseCred(authToken)
+	require.Nil(t, err)
+	assert.Equal(t, authToken, c.AuthToken)
+	assert.Empty(t, c.CompartmentID)
+
+	// no auth token
+	_, err = parseCred(`{"compartment_id":"ocid1.compartment.oc1..dev"}`)
+	assert.NotNil(t, err)
+
+	// no API signing key
+	_, err = parseCred(`{"auth_token":"token","compartment_id":"ocid1.compartment.oc1..dev"}`)
+	assert.NotNil(t, err)
+}
+
+func TestArtifactsEndpoint(t *testing.T) {
+	endpoint, err := artifactsEndpoint("https://iad.ocir.io")
+	require.Nil(t, err)
+	assert.Equal(t, "https://artifacts.us-ashburn-1.oci.oraclecloud.com", endpoint)
+
+	endpoint, err = artifactsEndpoint("https://eu-frankfurt-1.ocir.io")
+	require.Nil(t, err)
+	assert.Equal(t, "https://artifacts.eu-frankfurt-1.oci.oraclecloud.com", endpoint)
+
+	_, err = artifactsEndpoint("https://registry.example.com")
+	assert.NotNil(t, err)
+}
+
+func TestFetchArtifactsInCompartment(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	server := mockOCIR()
+	defer server.Close()
+	api := mockArtifactsAPI(t, key, nil)
+	defer api.Close()
+	a := newCompartmentAdapter(t, server.URL, api.URL, key)
+
+	// the registry API is still authenticated by the auth token
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+
+	// the repositories in the compartment are listed rather than the catalog
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "tenancy/app/web", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, "tenancy/busybox", resources[1].Metadata.Repository.Name)
+}
+
+func TestPrepareForPushInCompartment(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	var created []string
+	api := mockArtifactsAPI(t, key, &created)
+	defer api.Close()
+	a := newCompartmentAdapter(t, "https://iad.ocir.io", api.URL, key)
+
+	err = a.PrepareForPush([]*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "tenancy/app/api",
+				},
+			},
+		},
+		{
+			// exists already
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "tenancy/busybox",
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"app/api"}, created)
+
+	// the signature is invalid
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	a = newCompartmentAdapter(t, "https://iad.ocir.io", api.URL, other)
+	err = a.PrepareForPush([]*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "tenancy/app/api",
+				},
+			},
+		},
+	})
+	assert.NotNil(t, err)
+}
@@ -15,9 +15,12 @@
 package filter
 
 import (
+	"strings"
+	"time"
+
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/reg/util"
-	"strings"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 // DoFilterArtifacts filter the artifacts according to the filters
@@ -45,6 +48,27 @@ func BuildArtifactFilters(filters []*model.Filter) (ArtifactFilters, error) {
 				pattern:    filter.Value.(string),
 				decoration: filter.Decoration,
 			}
+		case model.FilterTypeVulnerability:
+			f = &artifactVulnerabilityFilter{
+				severity: vuln.Severity(filter.Value.(string)),
+			}
+		case model.FilterTypeSignature:
+			signed, _ := filter.Value.(bool)
+			if value, ok := filter.Value.(string); ok {
+				signed = value == "true"
+			}
+			f = &artifactSignatureFilter{
+				signed: signed,
+			}
+		case model.FilterTypePushTime:
+			days, err := model.ParsePushTimeDays(filter.Value)
+			if err != nil {
+				return nil, err
+			}
+			f = &artifactPushTimeFilter{
+				days:       days,
+				decoration: filter.Decoration,
+			}
 		case model.FilterTypeResource:
 			v := filter.Value.(string)
 			if v != model.ResourceTypeArtifact && v != model.ResourceTypeChart {
@@ -206,12 +230,104 @@ func (a *artifactTagFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifa
 			continue
 		}
 		// copy a new artifact here to avoid changing the original one
-		result = append(result, &model.Artifact{
-			Type:   artifact.Type,
-			Digest: artifact.Digest,
-			Labels: artifact.Labels,
-			Tags:   tags,
-		})
+		art := *artifact
+		art.Tags = tags
+		result = append(result, &art)
+	}
+	return result, nil
+}
+
+// filter the artifacts according to the vulnerability severity. Only the artifact whose severity
+// is lower than the one defined in the filter is the valid one, the artifact that isn't scanned or
+// whose severity is unknown is treated as the highest severity
+type artifactVulnerabilityFilter struct {
+	severity vuln.Severity
+}
+
+func (a *artifactVulnerabilityFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	if len(a.severity) == 0 {
+		return artifacts, nil
+	}
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		severity := vuln.Severity(artifact.Severity)
+		// the code of unknown severity is the same as none, exclude it explicitly
+		if severity == vuln.Unknown {
+			continue
+		}
+		if severity.Code() < a.severity.Code() {
+			result = append(result, artifact)
+		}
+	}
+	return result, nil
+}
+
+// filter the signed artifacts. The artifact signed as a whole is kept with all tags, otherwise
+// only the signed tags are kept and the artifact without any signed tag is dropped
+type artifactSignatureFilter struct {
+	signed bool
+}
+
+func (a *artifactSignatureFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	if !a.signed {
+		return artifacts, nil
+	}
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		if artifact.Signed {
+			result = append(result, artifact)
+			continue
+		}
+		signed := map[string]struct{}{}
+		for _, tag := range artifact.SignedTags {
+			signed[tag] = struct{}{}
+		}
+		var tags []string
+		for _, tag := range artifact.Tags {
+			if _, exist := signed[tag]; exist {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		// copy a new artifact here to avoid changing the original one
+		art := *artifact
+		art.Tags = tags
+		result = append(result, &art)
+	}
+	return result, nil
+}
+
+// filter the artifacts according to the push time. The artifact pushed within the last N days
+// is the valid one for "matches" and the one pushed earlier is the valid one for "excludes", the
+// artifact whose push time is unknown is dropped
+type artifactPushTimeFilter struct {
+	days int
+	// "matches", "excludes"
+	decoration string
+}
+
+func (a *artifactPushTimeFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	if a.days <= 0 {
+		return artifacts, nil
+	}
+	since := time.Now().Add(-time.Duration(a.days) * 24 * time.Hour)
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		if artifact.PushTime.IsZero() {
+			continue
+		}
+		within := artifact.PushTime.After(since)
+		if a.decoration == model.Excludes {
+			if !within {
+				result = append(result, artifact)
+			}
+		} else {
+			if within {
+				result = append(result, artifact)
+			}
+		}
 	}
 	return result, nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestArtifactTagFilters(t *testing.T) {
@@ -159,3 +160,162 @@ func TestArtifactLabelFilters(t *testing.T) {
 	require.EqualValues(t, "ddddd", arts[1].Digest)
 	require.Nil(t, arts[1].Labels)
 }
+
+func TestArtifactVulnerabilityFilters(t *testing.T) {
+	var artifacts = []*model.Artifact{
+		{
+			Digest:   "aaaaa",
+			Severity: "None",
+		},
+		{
+			Digest:   "bbbbb",
+			Severity: "Medium",
+		},
+		{
+			Digest:   "ccccc",
+			Severity: "Critical",
+		},
+		{
+			// not scanned
+			Digest: "ddddd",
+		},
+		{
+			Digest:   "eeeee",
+			Severity: "Unknown",
+		},
+	}
+
+	var filters = []*model.Filter{
+		{
+			Type:  model.FilterTypeVulnerability,
+			Value: "High",
+		},
+	}
+
+	artFilters, err := BuildArtifactFilters(filters)
+	require.Nil(t, err)
+
+	arts, err := artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(arts))
+	require.EqualValues(t, "aaaaa", arts[0].Digest)
+	require.EqualValues(t, "bbbbb", arts[1].Digest)
+}
+
+func TestArtifactSignatureFilters(t *testing.T) {
+	var artifacts = []*model.Artifact{
+		{
+			Digest: "aaaaa",
+			Tags:   []string{"v1", "latest"},
+			Signed: true,
+		},
+		{
+			Digest:       "bbbbb",
+			Tags:         []string{"v2", "dev"},
+			SignedTags:   []string{"v2"},
+			Labels:       []string{"prod"},
+			LabelDetails: []*model.Label{{Name: "prod", Scope: "g"}},
+		},
+		{
+			Digest: "ccccc",
+			Tags:   []string{"v3"},
+		},
+	}
+
+	var filters = []*model.Filter{
+		{
+			Type:  model.FilterTypeSignature,
+			Value: true,
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "v*",
+		},
+	}
+
+	artFilters, err := BuildArtifactFilters(filters)
+	require.Nil(t, err)
+
+	arts, err := artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(arts))
+	require.EqualValues(t, "aaaaa", arts[0].Digest)
+	require.EqualValues(t, []string{"v1"}, arts[0].Tags)
+	require.EqualValues(t, "bbbbb", arts[1].Digest)
+	require.EqualValues(t, []string{"v2"}, arts[1].Tags)
+	// the copied artifact keeps the labels and the original one isn't changed
+	require.EqualValues(t, []string{"prod"}, arts[1].Labels)
+	require.EqualValues(t, []*model.Label{{Name: "prod", Scope: "g"}}, arts[1].LabelDetails)
+	require.EqualValues(t, []string{"v2", "dev"}, artifacts[1].Tags)
+
+	// the value submitted by the portal is string
+	filters[0].Value = "true"
+	artFilters, err = BuildArtifactFilters(filters)
+	require.Nil(t, err)
+	arts, err = artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(arts))
+
+	filters = []*model.Filter{
+		{
+			Type:  model.FilterTypeSignature,
+			Value: false,
+		},
+	}
+	artFilters, err = BuildArtifactFilters(filters)
+	require.Nil(t, err)
+
+	arts, err = artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(arts))
+}
+
+func TestArtifactPushTimeFilters(t *testing.T) {
+	now := time.Now()
+	var artifacts = []*model.Artifact{
+		{
+			Digest:   "aaaaa",
+			PushTime: now.Add(-time.Hour),
+		},
+		{
+			Digest:   "bbbbb",
+			PushTime: now.Add(-10 * 24 * time.Hour),
+		},
+		{
+			// the push time is unknown
+			Digest: "ccccc",
+		},
+	}
+
+	var filters = []*model.Filter{
+		{
+			Type:  model.FilterTypePushTime,
+			Value: 7,
+		},
+	}
+
+	artFilters, err := BuildArtifactFilters(filters)
+	require.Nil(t, err)
+
+	arts, err := artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(arts))
+	require.EqualValues(t, "aaaaa", arts[0].Digest)
+
+	// the value submitted by the portal is string
+	filters = []*model.Filter{
+		{
+			Type:       model.FilterTypePushTime,
+			Value:      "7",
+			Decoration: model.Excludes,
+		},
+	}
+
+	artFilters, err = BuildArtifactFilters(filters)
+	require.Nil(t, err)
+
+	arts, err = artFilters.Filter(artifacts)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(arts))
+	require.EqualValues(t, "bbbbb", arts[0].Digest)
+}
@@ -51,6 +51,12 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/tencentcr"
 	// register the Github Container Registry adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/githubcr"
+	// register the OCI image layout adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/ocilayout"
+	// register the Oracle Cloud Infrastructure Registry adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/ocir"
+	// register the Sonatype Nexus Repository adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/nexus"
 
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/lib/q"
@@ -14,7 +14,12 @@
 
 package model
 
-import "github.com/goharbor/harbor/src/lib/errors"
+import (
+	"strconv"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
 
 // const definition
 const (
@@ -22,6 +27,13 @@ const (
 	FilterTypeName     = "name"
 	FilterTypeTag      = "tag"
 	FilterTypeLabel    = "label"
+	// FilterTypeVulnerability filters the artifacts whose vulnerability severity is lower than the value
+	FilterTypeVulnerability = "vulnerability"
+	// FilterTypeSignature filters the signed artifacts when the value is true
+	FilterTypeSignature = "signature"
+	// FilterTypePushTime filters the artifacts pushed within the last N days("matches") or
+	// pushed earlier than N days ago("excludes"), the value is N
+	FilterTypePushTime = "push_time"
 
 	TriggerTypeManual     = "manual"
 	TriggerTypeScheduled  = "scheduled"
@@ -74,11 +86,46 @@ func (f *Filter) Validate() error {
 					WithMessage("the type of label filter value isn't string slice")
 			}
 		}
+	case FilterTypeVulnerability:
+		value, ok := f.Value.(string)
+		if !ok {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the type of vulnerability filter value isn't string")
+		}
+		// only the severities which have a meaningful order can be used as the threshold
+		if code := vuln.Severity(value).Code(); code < vuln.Negligible.Code() || code > vuln.Critical.Code() {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("invalid vulnerability filter: %s", value)
+		}
+	case FilterTypeSignature:
+		// the portal submits the value of radio style filter as string
+		switch value := f.Value.(type) {
+		case bool:
+		case string:
+			if value != "true" && value != "false" {
+				return errors.New(nil).WithCode(errors.BadRequestCode).
+					WithMessage("invalid signature filter: %s", value)
+			}
+		default:
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the type of signature filter value isn't bool")
+		}
+	case FilterTypePushTime:
+		if _, err := ParsePushTimeDays(f.Value); err != nil {
+			return err
+		}
 	default:
 		return errors.New(nil).WithCode(errors.BadRequestCode).
 			WithMessage("invalid filter type")
 	}
 
+	if f.Type == FilterTypeVulnerability || f.Type == FilterTypeSignature {
+		if f.Decoration != "" {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("only tag and label filter support decoration")
+		}
+	}
+
 	if f.Decoration != "" && f.Decoration != Matches && f.Decoration != Excludes {
 		return errors.New(nil).WithCode(errors.BadRequestCode).
 			WithMessage("invalid filter decoration, :%s", f.Decoration)
@@ -97,3 +144,30 @@ type Trigger struct {
 type TriggerSettings struct {
 	Cron string `json:"cron"`
 }
+
+// ParsePushTimeDays returns the days of the push time filter, the value is number in the
+// API request and is the numeric string when it's submitted by the portal
+func ParsePushTimeDays(value interface{}) (int, error) {
+	var days float64
+	switch v := value.(type) {
+	case int:
+		days = float64(v)
+	case float64:
+		days = v
+	case string:
+		d, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessage("the value of push time filter isn't number: %s", v)
+		}
+		days = d
+	default:
+		return 0, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the type of push time filter value isn't number")
+	}
+	if days <= 0 || days != float64(int(days)) {
+		return 0, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the value of push time filter must be a positive integer")
+	}
+	return int(days), nil
+}
@@ -34,6 +34,9 @@ const (
 	RegistryTypeDTR              = "dtr"
 	RegistryTypeTencentTcr       = "tencent-tcr"
 	RegistryTypeGithubCR         = "github-ghcr"
+	RegistryTypeOCILayout        = "oci-layout"
+	RegistryTypeOCIR             = "oracle-ocir"
+	RegistryTypeNexus            = "sonatype-nexus"
 
 	RegistryTypeHelmHub     = "helm-hub"
 	RegistryTypeArtifactHub = "artifact-hub"
@@ -14,6 +14,8 @@
 
 package model
 
+import "time"
+
 // the resource type
 const (
 	ResourceTypeArtifact = "artifact"
@@ -33,6 +35,8 @@ type Resource struct {
 	IsDeleteTag bool `json:"is_delete_tag"`
 	// indicate whether the resource can be overridden
 	Override bool `json:"override"`
+	// indicate whether to sync the labels of artifacts and the metadata of project
+	SyncMetadata bool `json:"sync_metadata"`
 }
 
 // ResourceMetadata of resource
@@ -54,4 +58,24 @@ type Artifact struct {
 	Digest string   `json:"digest"`
 	Labels []string `json:"labels"`
 	Tags   []string `json:"tags"`
+	// Severity is the highest vulnerability severity of the artifact, empty if the artifact isn't scanned
+	Severity string `json:"severity,omitempty"`
+	// Signed is true when the artifact is signed as a whole(e.g. by cosign)
+	Signed bool `json:"signed,omitempty"`
+	// SignedTags contains the tags which are signed(e.g. by notary)
+	SignedTags []string `json:"signed_tags,omitempty"`
+	// PushTime is zero if the registry doesn't expose it
+	PushTime time.Time `json:"push_time"`
+	// LabelDetails contains the details of the labels, which are used to create the missing labels
+	// when syncing the labels to the destination registry
+	LabelDetails []*Label `json:"label_details,omitempty"`
+}
+
+// Label attached to the artifact
+type Label struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	// "g" for the global label and "p" for the project label
+	Scope string `json:"scope"`
 }
@@ -86,6 +86,15 @@ type Client interface {
 	PullBlob(repository, digest string) (size int64, blob io.ReadCloser, err error)
 	// PushBlob pushes the specified blob
 	PushBlob(repository, digest string, size int64, blob io.Reader) error
+	// PullBlobChunk pulls the bytes between "start" and "end"(both inclusive) of the specified blob.
+	// The caller must close the returned "blob"
+	PullBlobChunk(repository, digest string, blobSize, start, end int64) (size int64, blob io.ReadCloser, err error)
+	// PushBlobChunk pushes the bytes between "start" and "end"(both inclusive) of the specified blob into
+	// the upload session "location", a new upload session is initiated if the "location" is empty. The upload
+	// is completed when the last chunk is pushed. The location of the upload session for the next chunk and
+	// the offset of the last committed byte are returned. If the push fails, the location returned by the last
+	// successful request is returned so that the upload can be resumed from there
+	PushBlobChunk(repository, digest string, blobSize int64, chunk io.Reader, start, end int64, location string) (nextUploadLocation string, endRange int64, err error)
 	// MountBlob mounts the blob from the source repository
 	MountBlob(srcRepository, digest, dstRepository string) (err error)
 	// DeleteBlob deletes the specified blob
@@ -380,6 +389,121 @@ func (c *client) PushBlob(repository, digest string, size int64, blob io.Reader)
 	return c.monolithicBlobUpload(location, digest, size, blob)
 }
 
+func (c *client) PullBlobChunk(repository, digest string, blobSize, start, end int64) (int64, io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, buildBlobURL(c.url, repository, digest), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Add(http.CanonicalHeaderKey("Accept-Encoding"), "identity")
+	req.Header.Add(http.CanonicalHeaderKey("Range"), fmt.Sprintf("bytes=%d-%d", start, end))
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	size := end - start + 1
+	// the registry ignores the range request and returns the whole blob
+	if resp.StatusCode != http.StatusPartialContent {
+		if _, err = io.CopyN(ioutil.Discard, resp.Body, start); err != nil {
+			defer resp.Body.Close()
+			return 0, nil, err
+		}
+		return size, &limitedReadCloser{
+			Reader: io.LimitReader(resp.Body, size),
+			Closer: resp.Body,
+		}, nil
+	}
+	return size, resp.Body, nil
+}
+
+func (c *client) PushBlobChunk(repository, digest string, blobSize int64, chunk io.Reader, start, end int64, location string) (string, int64, error) {
+	var err error
+	if len(location) == 0 {
+		location, _, err = c.initiateBlobUpload(repository)
+		if err != nil {
+			return "", -1, err
+		}
+	}
+
+	endRange := start - 1
+	// skip the empty chunk which is used to complete the upload whose bytes are all committed
+	if end >= start {
+		url, err := buildChunkBlobUploadURL(c.url, location)
+		if err != nil {
+			return location, -1, err
+		}
+		req, err := http.NewRequest(http.MethodPatch, url, chunk)
+		if err != nil {
+			return location, -1, err
+		}
+		req.ContentLength = end - start + 1
+		req.Header.Set(http.CanonicalHeaderKey("Content-Range"), fmt.Sprintf("%d-%d", start, end))
+		req.Header.Set(http.CanonicalHeaderKey("Content-Type"), "application/octet-stream")
+		resp, err := c.do(req)
+		if err != nil {
+			// return the location to resume the upload session
+			return location, -1, err
+		}
+		defer resp.Body.Close()
+		next, committed, err := parseUploadStatus(resp, location)
+		if err != nil {
+			return location, -1, err
+		}
+		location, endRange = next, committed
+		// the registry doesn't return the range, the whole chunk is regarded as committed
+		if endRange < 0 {
+			endRange = end
+		}
+	}
+
+	// all the bytes are committed, complete the upload
+	if end == blobSize-1 {
+		url, err := buildMonolithicBlobUploadURL(c.url, location, digest)
+		if err != nil {
+			return location, endRange, err
+		}
+		req, err := http.NewRequest(http.MethodPut, url, nil)
+		if err != nil {
+			return location, endRange, err
+		}
+		req.Header.Set(http.CanonicalHeaderKey("Content-Length"), "0")
+		resp, err := c.do(req)
+		if err != nil {
+			return location, endRange, err
+		}
+		defer resp.Body.Close()
+	}
+	return location, endRange, nil
+}
+
+// parseUploadStatus parses the location and the offset of the last committed byte from the
+// response of upload session, the "Range" header is in format "0-<offset>"
+func parseUploadStatus(resp *http.Response, location string) (string, int64, error) {
+	if l := resp.Header.Get(http.CanonicalHeaderKey("Location")); len(l) > 0 {
+		location = l
+	}
+	r := resp.Header.Get(http.CanonicalHeaderKey("Range"))
+	if len(r) == 0 {
+		return location, -1, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r, "bytes="), "-", 2)
+	if len(parts) != 2 {
+		return location, -1, errors.New(nil).WithCode(errors.GeneralCode).
+			WithMessage("invalid range header of upload session: %s", r)
+	}
+	endRange, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return location, -1, errors.New(nil).WithCode(errors.GeneralCode).
+			WithMessage("invalid range header of upload session: %s", r)
+	}
+	return location, endRange, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
 func (c *client) initiateBlobUpload(repository string) (string, string, error) {
 	req, err := http.NewRequest(http.MethodPost, buildInitiateBlobUploadURL(c.url, repository), nil)
 	if err != nil {
@@ -582,6 +706,18 @@ func buildInitiateBlobUploadURL(endpoint, repository string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/uploads/", endpoint, repository)
 }
 
+func buildChunkBlobUploadURL(endpoint, location string) (string, error) {
+	url, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	if url.IsAbs() {
+		return url.String(), nil
+	}
+	// the "relativeurls" is enabled in registry
+	return endpoint + url.String(), nil
+}
+
 func buildMonolithicBlobUploadURL(endpoint, location, digest string) (string, error) {
 	url, err := url.Parse(location)
 	if err != nil {
@@ -16,6 +16,7 @@ package registry
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/goharbor/harbor/src/lib"
@@ -23,6 +24,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -347,6 +349,100 @@ func (c *clientTestSuite) TestPushBlob() {
 	c.Require().Nil(err)
 }
 
+func (c *clientTestSuite) TestPullBlobChunk() {
+	data := []byte("0123456789")
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: "/v2/library/hello-world/blobs/digest",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "bytes=2-5" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(data[2:6])
+			},
+		},
+		&test.RequestHandlerMapping{
+			// the registry which doesn't support the range request
+			Method:  "GET",
+			Pattern: "/v2/library/alpine/blobs/digest",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(data)
+			},
+		})
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", true)
+	for _, repository := range []string{"library/hello-world", "library/alpine"} {
+		size, blob, err := client.PullBlobChunk(repository, "digest", int64(len(data)), 2, 5)
+		c.Require().Nil(err)
+		c.Equal(int64(4), size)
+		b, err := ioutil.ReadAll(blob)
+		c.Require().Nil(err)
+		blob.Close()
+		c.Equal("2345", string(b))
+	}
+}
+
+func (c *clientTestSuite) TestPushBlobChunk() {
+	var uploaded []byte
+	completed := false
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: "/v2/library/hello-world/blobs/uploads/",
+			Handler: test.Handler(&test.Response{
+				StatusCode: http.StatusAccepted,
+				Headers: map[string]string{
+					"Location": "/v2/library/hello-world/blobs/uploads/uuid",
+				},
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  "PATCH",
+			Pattern: "/v2/library/hello-world/blobs/uploads/uuid",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Range") != fmt.Sprintf("%d-%d", len(uploaded), len(uploaded)+int(r.ContentLength)-1) {
+					w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+					return
+				}
+				b, _ := ioutil.ReadAll(r.Body)
+				uploaded = append(uploaded, b...)
+				w.Header().Set("Location", "/v2/library/hello-world/blobs/uploads/uuid")
+				w.Header().Set("Range", fmt.Sprintf("0-%d", len(uploaded)-1))
+				w.WriteHeader(http.StatusAccepted)
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  "PUT",
+			Pattern: "/v2/library/hello-world/blobs/uploads/uuid",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				completed = r.URL.Query().Get("digest") == "digest"
+				w.WriteHeader(http.StatusCreated)
+			},
+		})
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", true)
+	location, endRange, err := client.PushBlobChunk("library/hello-world", "digest", 6, strings.NewReader("012"), 0, 2, "")
+	c.Require().Nil(err)
+	c.Equal("/v2/library/hello-world/blobs/uploads/uuid", location)
+	c.Equal(int64(2), endRange)
+	c.False(completed)
+
+	location, endRange, err = client.PushBlobChunk("library/hello-world", "digest", 6, strings.NewReader("345"), 3, 5, location)
+	c.Require().Nil(err)
+	c.Equal(int64(5), endRange)
+	c.True(completed)
+	c.Equal("012345", string(uploaded))
+
+	// the offset doesn't match the upload session
+	_, _, err = client.PushBlobChunk("library/hello-world", "digest", 6, strings.NewReader("345"), 3, 5, location)
+	c.NotNil(err)
+}
+
 func (c *clientTestSuite) TestDeleteBlob() {
 	server := test.NewServer(
 		&test.RequestHandlerMapping{
@@ -38,10 +38,14 @@ type Policy struct {
 	Enabled                   bool      `orm:"column(enabled)"`
 	Trigger                   string    `orm:"column(trigger)"`
 	Filters                   string    `orm:"column(filters)"`
+	RewriteRules              string    `orm:"column(rewrite_rules)"`
 	ReplicateDeletion         bool      `orm:"column(replicate_deletion)"`
 	CreationTime              time.Time `orm:"column(creation_time);auto_now_add" sort:"default:desc"`
 	UpdateTime                time.Time `orm:"column(update_time);auto_now"`
 	Speed                     int32     `orm:"column(speed_kb)"`
+	BandwidthWindows          string    `orm:"column(bandwidth_windows)"`
+	CopyByChunk               bool      `orm:"column(copy_by_chunk)"`
+	SyncMetadata              bool      `orm:"column(sync_metadata)"`
 }
 
 // TableName set table name for ORM
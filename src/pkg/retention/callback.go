@@ -26,9 +26,11 @@ func retentionTaskCheckInProcessor(ctx context.Context, t *task.Task, sc *job.St
 	// handle checkin
 	if sc.CheckIn != "" {
 		var retainObj struct {
-			Total    int                `json:"total"`
-			Retained int                `json:"retained"`
-			Deleted  []*selector.Result `json:"deleted"`
+			Total      int                `json:"total"`
+			Retained   int                `json:"retained"`
+			DryRun     bool               `json:"dry_run"`
+			Deleted    []*selector.Result `json:"deleted"`
+			Candidates []*DryRunCandidate `json:"candidates"`
 		}
 		if err := json.Unmarshal([]byte(sc.CheckIn), &retainObj); err != nil {
 			log.Errorf("failed to resolve checkin of retention task %d: %v", taskID, err)
@@ -50,6 +52,16 @@ func retentionTaskCheckInProcessor(ctx context.Context, t *task.Task, sc *job.St
 			return err
 		}
 
+		if retainObj.DryRun {
+			for _, c := range retainObj.Candidates {
+				c.ExecutionID = t.ExecutionID
+			}
+			if err = NewManager().SaveDryRunCandidates(ctx, taskID, retainObj.Candidates); err != nil {
+				log.G(ctx).WithField("error", err).Errorf("failed to save the dry run candidates of retention task %d", taskID)
+				return err
+			}
+		}
+
 		e := &event.Event{}
 		metaData := &metadata.RetentionMetaData{
 			Total:    retainObj.Total,
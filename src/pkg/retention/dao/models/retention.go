@@ -19,6 +19,7 @@ func init() {
 		new(RetentionPolicy),
 		new(RetentionExecution),
 		new(RetentionTask),
+		new(RetentionDryRunCandidate),
 	)
 }
 
@@ -61,3 +62,18 @@ type RetentionTask struct {
 	Total          int       `orm:"column(total)"`
 	Retained       int       `orm:"column(retained)"`
 }
+
+// RetentionDryRunCandidate is the candidate artifact evaluated by the dry run execution
+type RetentionDryRunCandidate struct {
+	ID           int64     `orm:"pk;auto;column(id)" sort:"default"`
+	ExecutionID  int64     `orm:"column(execution_id)"`
+	TaskID       int64     `orm:"column(task_id)"`
+	Repository   string    `orm:"column(repository)"`
+	Digest       string    `orm:"column(digest)"`
+	Tags         string    `orm:"column(tags)"` // comma separated
+	PushTime     time.Time `orm:"column(push_time)"`
+	PullTime     time.Time `orm:"column(pull_time)"`
+	Action       string    `orm:"column(action)"`
+	Rules        string    `orm:"column(rules)"` // comma separated, the template IDs of the rules retaining the artifact
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add"`
+}
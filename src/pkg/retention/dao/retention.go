@@ -2,7 +2,9 @@ package dao
 
 import (
 	"context"
+
 	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/retention/dao/models"
 )
 
@@ -52,3 +54,42 @@ func GetPolicy(ctx context.Context, id int64) (*models.RetentionPolicy, error) {
 	}
 	return p, nil
 }
+
+// CreateDryRunCandidates creates the candidates of the dry run task, the candidates created
+// previously for the same task are replaced
+func CreateDryRunCandidates(ctx context.Context, taskID int64, candidates []*models.RetentionDryRunCandidate) error {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = o.Raw("DELETE FROM retention_dry_run_candidate WHERE task_id = ?", taskID).Exec(); err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	_, err = o.InsertMulti(100, candidates)
+	return err
+}
+
+// ListDryRunCandidates lists the candidates of the dry run executions
+func ListDryRunCandidates(ctx context.Context, query *q.Query) ([]*models.RetentionDryRunCandidate, error) {
+	qs, err := orm.QuerySetter(ctx, &models.RetentionDryRunCandidate{}, query)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []*models.RetentionDryRunCandidate{}
+	if _, err = qs.All(&candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// CountDryRunCandidates counts the candidates of the dry run executions
+func CountDryRunCandidates(ctx context.Context, query *q.Query) (int64, error) {
+	qs, err := orm.QuerySetterForCount(ctx, &models.RetentionDryRunCandidate{}, query)
+	if err != nil {
+		return 0, err
+	}
+	return qs.Count()
+}
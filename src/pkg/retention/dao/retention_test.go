@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/retention/dao/models"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
@@ -94,3 +95,52 @@ func TestPolicy(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.True(t, strings.Contains(err.Error(), "no row found"))
 }
+
+func TestDryRunCandidates(t *testing.T) {
+	ctx := orm.Context()
+	o, err := orm.FromContext(ctx)
+	assert.Nil(t, err)
+	var executionID int64
+	err = o.Raw("INSERT INTO execution (vendor_type, vendor_id, status, trigger, start_time) VALUES (?, ?, ?, ?, ?) RETURNING id",
+		"RETENTION", 1, "Running", "MANUAL", time.Now()).QueryRow(&executionID)
+	assert.Nil(t, err)
+	defer o.Raw("DELETE FROM execution WHERE id = ?", executionID).Exec()
+
+	candidates := []*models.RetentionDryRunCandidate{
+		{
+			ExecutionID: executionID,
+			TaskID:      1,
+			Repository:  "library/hello-world",
+			Digest:      "sha256:1",
+			Tags:        "latest",
+			Action:      "retain",
+			Rules:       "latestPushedK",
+		},
+		{
+			ExecutionID: executionID,
+			TaskID:      1,
+			Repository:  "library/hello-world",
+			Digest:      "sha256:2",
+			Tags:        "dev,test",
+			Action:      "delete",
+		},
+	}
+	err = CreateDryRunCandidates(ctx, 1, candidates)
+	assert.Nil(t, err)
+	// the candidates of the same task are replaced
+	err = CreateDryRunCandidates(ctx, 1, candidates)
+	assert.Nil(t, err)
+
+	query := &q.Query{Keywords: map[string]interface{}{"ExecutionID": executionID}}
+	total, err := CountDryRunCandidates(ctx, query)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+
+	query.Keywords["Action"] = "delete"
+	cs, err := ListDryRunCandidates(ctx, query)
+	assert.Nil(t, err)
+	if assert.Len(t, cs, 1) {
+		assert.Equal(t, "sha256:2", cs[0].Digest)
+		assert.Equal(t, "dev,test", cs[0].Tags)
+	}
+}
@@ -119,16 +119,17 @@ func (bc *basicClient) GetCandidates(repository *selector.Repository) ([]*select
 				}
 			}
 			candidate := &selector.Candidate{
-				Kind:         selector.Image,
-				NamespaceID:  repository.NamespaceID,
-				Namespace:    repository.Namespace,
-				Repository:   repository.Name,
-				Tags:         tags,
-				Digest:       art.Digest,
-				Labels:       labels,
-				CreationTime: art.PushTime.Unix(),
-				PulledTime:   lastPulledTime.Unix(),
-				PushedTime:   lastPushedTime.Unix(),
+				Kind:                  selector.Image,
+				NamespaceID:           repository.NamespaceID,
+				Namespace:             repository.Namespace,
+				Repository:            repository.Name,
+				Tags:                  tags,
+				Digest:                art.Digest,
+				Labels:                labels,
+				CreationTime:          art.PushTime.Unix(),
+				PulledTime:            lastPulledTime.Unix(),
+				PushedTime:            lastPushedTime.Unix(),
+				VulnerabilitySeverity: uint(art.Severity().Code()),
 			}
 			candidates = append(candidates, candidate)
 		}
@@ -25,10 +25,12 @@ import (
 
 	"github.com/goharbor/harbor/src/chartserver"
 	jmodels "github.com/goharbor/harbor/src/common/job/models"
-	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/tag"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
+	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	"github.com/goharbor/harbor/src/testing/clients"
 )
@@ -37,8 +39,8 @@ type fakeCoreClient struct {
 	clients.DumbCoreClient
 }
 
-func (f *fakeCoreClient) ListAllArtifacts(project, repository string) ([]*modelsv2.Artifact, error) {
-	image := &modelsv2.Artifact{}
+func (f *fakeCoreClient) ListAllArtifacts(project, repository string) ([]*core.Artifact, error) {
+	image := &core.Artifact{}
 	image.Digest = "sha256:123456"
 	image.Tags = []*tag.Tag{
 		{
@@ -47,7 +49,12 @@ func (f *fakeCoreClient) ListAllArtifacts(project, repository string) ([]*models
 			},
 		},
 	}
-	return []*modelsv2.Artifact{image}, nil
+	image.ScanOverview = map[string]*vuln.NativeReportSummary{
+		v1.MimeTypeNativeReport: {
+			Severity: vuln.High,
+		},
+	}
+	return []*core.Artifact{image}, nil
 }
 
 func (f *fakeCoreClient) ListAllCharts(project, repository string) ([]*chartserver.ChartVersion, error) {
@@ -100,6 +107,7 @@ func (c *clientTestSuite) TestGetCandidates() {
 	assert.Equal(c.T(), "library", candidates[0].Namespace)
 	assert.Equal(c.T(), "hello-world", candidates[0].Repository)
 	assert.Equal(c.T(), "latest", candidates[0].Tags[0])
+	assert.Equal(c.T(), uint(vuln.High.Code()), candidates[0].VulnerabilitySeverity)
 
 	/*
 		// chart repository
@@ -119,11 +119,19 @@ func (pj *Job) Run(ctx job.Context, params job.Parameters) error {
 	// Log stage: results with table view
 	logResults(myLogger, allCandidates, results)
 
+	// Report all the candidates with the rules retaining them for dry run
+	var candidates []*DryRunCandidate
+	if isDryRun {
+		if candidates, err = dryRunCandidates(liteMeta, allCandidates, results); err != nil {
+			return logError(myLogger, err)
+		}
+	}
+
 	// Save retain and total num in DB
-	return saveRetainNum(ctx, results, allCandidates, isDryRun)
+	return saveRetainNum(ctx, results, allCandidates, isDryRun, candidates)
 }
 
-func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []*selector.Candidate, isDryRun bool) error {
+func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []*selector.Candidate, isDryRun bool, candidates []*DryRunCandidate) error {
 	var realDelete []*selector.Result
 	for _, r := range results {
 		if r.Error == nil {
@@ -131,15 +139,17 @@ func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []
 		}
 	}
 	retainObj := struct {
-		Total    int                `json:"total"`
-		Retained int                `json:"retained"`
-		DryRun   bool               `json:"dry_run"`
-		Deleted  []*selector.Result `json:"deleted"`
+		Total      int                `json:"total"`
+		Retained   int                `json:"retained"`
+		DryRun     bool               `json:"dry_run"`
+		Deleted    []*selector.Result `json:"deleted"`
+		Candidates []*DryRunCandidate `json:"candidates,omitempty"`
 	}{
-		Total:    len(allCandidates),
-		Retained: len(allCandidates) - len(realDelete),
-		DryRun:   isDryRun,
-		Deleted:  realDelete,
+		Total:      len(allCandidates),
+		Retained:   len(allCandidates) - len(realDelete),
+		DryRun:     isDryRun,
+		Deleted:    realDelete,
+		Candidates: candidates,
 	}
 	c, err := json.Marshal(retainObj)
 	if err != nil {
@@ -149,6 +159,58 @@ func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []
 	return nil
 }
 
+// dryRunCandidates reports the action performed on each candidate if it isn't a dry run
+// and the templates of the rules retaining the candidate
+func dryRunCandidates(meta *lwp.Metadata, all []*selector.Candidate, results []*selector.Result) ([]*DryRunCandidate, error) {
+	matched, err := policy.MatchRules(meta, all)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := make(map[string]error, len(results))
+	for _, r := range results {
+		if r.Target != nil {
+			hash[r.Target.Hash()] = r.Error
+		}
+	}
+
+	candidates := make([]*DryRunCandidate, 0, len(all))
+	for _, c := range all {
+		action := DryRunActionRetain
+		if e, exists := hash[c.Hash()]; exists {
+			switch e.(type) {
+			case nil:
+				action = DryRunActionDelete
+			case *selector.ImmutableError:
+				action = DryRunActionImmutable
+			default:
+				action = DryRunActionError
+			}
+		}
+
+		rules := make([]string, 0)
+		templates := make(map[string]struct{})
+		for _, r := range matched[c.Hash()] {
+			if _, exists := templates[r.Template]; !exists {
+				templates[r.Template] = struct{}{}
+				rules = append(rules, r.Template)
+			}
+		}
+
+		candidates = append(candidates, &DryRunCandidate{
+			Repository: fmt.Sprintf("%s/%s", c.Namespace, c.Repository),
+			Digest:     c.Digest,
+			Tags:       c.Tags,
+			PushTime:   unixTime(c.PushedTime),
+			PullTime:   unixTime(c.PulledTime),
+			Action:     action,
+			Rules:      rules,
+		})
+	}
+
+	return candidates, nil
+}
+
 func logResults(logger logger.Interface, all []*selector.Candidate, results []*selector.Result) {
 	hash := make(map[string]error, len(results))
 	for _, r := range results {
@@ -219,6 +281,13 @@ func t(tm int64) string {
 	return time.Unix(tm, 0).Format("2006/01/02 15:04:05")
 }
 
+func unixTime(tm int64) time.Time {
+	if tm <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(tm, 0).UTC()
+}
+
 func isStopped(ctx job.Context) (stopped bool) {
 	cmd, ok := ctx.OPCommand()
 	stopped = ok && cmd == job.StopCommand
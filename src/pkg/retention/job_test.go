@@ -109,6 +109,46 @@ func (suite *JobTestSuite) TestRunSuccess() {
 	require.NoError(suite.T(), err)
 }
 
+func (suite *JobTestSuite) TestDryRunCandidates() {
+	ruleParams := make(rule.Parameters)
+	ruleParams[latestps.ParameterK] = 10
+
+	meta := &lwp.Metadata{
+		Algorithm: policy.AlgorithmOR,
+		Rules: []*rule.Metadata{
+			{
+				ID:         1,
+				Action:     action.Retain,
+				Template:   latestps.TemplateID,
+				Parameters: ruleParams,
+				TagSelectors: []*rule.Selector{{
+					Kind:       doublestar.Kind,
+					Decoration: doublestar.Matches,
+					Pattern:    "latest",
+				}},
+			},
+		},
+	}
+
+	all, err := dep.DefaultClient.GetCandidates(&selector.Repository{})
+	require.NoError(suite.T(), err)
+	results := []*selector.Result{{Target: all[1]}}
+
+	candidates, err := dryRunCandidates(meta, all, results)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), candidates, 2)
+
+	suite.Equal("library/harbor", candidates[0].Repository)
+	suite.Equal("latest", candidates[0].Digest)
+	suite.Equal(DryRunActionRetain, candidates[0].Action)
+	suite.Equal([]string{latestps.TemplateID}, candidates[0].Rules)
+	suite.Equal(all[0].PushedTime, candidates[0].PushTime.Unix())
+
+	suite.Equal([]string{"dev", "test"}, candidates[1].Tags)
+	suite.Equal(DryRunActionDelete, candidates[1].Action)
+	suite.Empty(candidates[1].Rules)
+}
+
 type fakeRetentionClient struct{}
 
 // GetCandidates ...
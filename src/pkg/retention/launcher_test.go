@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/common/job"
+	libq "github.com/goharbor/harbor/src/lib/q"
 	_ "github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
 	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/repository/model"
@@ -59,6 +60,15 @@ func (f *fakeRetentionManager) DeletePolicy(ctx context.Context, ID int64) error
 func (f *fakeRetentionManager) GetPolicy(ctx context.Context, ID int64) (*policy.Metadata, error) {
 	return nil, nil
 }
+func (f *fakeRetentionManager) SaveDryRunCandidates(ctx context.Context, taskID int64, candidates []*DryRunCandidate) error {
+	return nil
+}
+func (f *fakeRetentionManager) ListDryRunCandidates(ctx context.Context, executionID int64, query *libq.Query) ([]*DryRunCandidate, error) {
+	return nil, nil
+}
+func (f *fakeRetentionManager) CountDryRunCandidates(ctx context.Context, executionID int64, query *libq.Query) (int64, error) {
+	return 0, nil
+}
 func (f *fakeRetentionManager) CreateExecution(execution *Execution) (int64, error) {
 	return 0, nil
 }
@@ -18,12 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	"strings"
 	"time"
 
 	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/retention/dao"
 	"github.com/goharbor/harbor/src/pkg/retention/dao/models"
+	"github.com/goharbor/harbor/src/pkg/retention/policy"
 )
 
 // Manager defines operations of managing policy
@@ -38,6 +40,12 @@ type Manager interface {
 	DeletePolicy(ctx context.Context, id int64) error
 	// Get the specified policy
 	GetPolicy(ctx context.Context, id int64) (*policy.Metadata, error)
+	// Save the candidates evaluated by the dry run task, replacing the ones saved before
+	SaveDryRunCandidates(ctx context.Context, taskID int64, candidates []*DryRunCandidate) error
+	// List the candidates evaluated by the dry run execution
+	ListDryRunCandidates(ctx context.Context, executionID int64, query *q.Query) ([]*DryRunCandidate, error)
+	// Count the candidates evaluated by the dry run execution
+	CountDryRunCandidates(ctx context.Context, executionID int64, query *q.Query) (int64, error)
 }
 
 // DefaultManager ...
@@ -94,6 +102,65 @@ func (d *DefaultManager) GetPolicy(ctx context.Context, id int64) (*policy.Metad
 	return p, nil
 }
 
+// SaveDryRunCandidates Save Dry Run Candidates
+func (d *DefaultManager) SaveDryRunCandidates(ctx context.Context, taskID int64, candidates []*DryRunCandidate) error {
+	var cs []*models.RetentionDryRunCandidate
+	for _, c := range candidates {
+		cs = append(cs, &models.RetentionDryRunCandidate{
+			ExecutionID: c.ExecutionID,
+			TaskID:      taskID,
+			Repository:  c.Repository,
+			Digest:      c.Digest,
+			Tags:        strings.Join(c.Tags, ","),
+			PushTime:    c.PushTime,
+			PullTime:    c.PullTime,
+			Action:      c.Action,
+			Rules:       strings.Join(c.Rules, ","),
+		})
+	}
+	return dao.CreateDryRunCandidates(ctx, taskID, cs)
+}
+
+// ListDryRunCandidates List Dry Run Candidates
+func (d *DefaultManager) ListDryRunCandidates(ctx context.Context, executionID int64, query *q.Query) ([]*DryRunCandidate, error) {
+	query = q.MustClone(query)
+	query.Keywords["ExecutionID"] = executionID
+	cs, err := dao.ListDryRunCandidates(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*DryRunCandidate
+	for _, c := range cs {
+		candidates = append(candidates, &DryRunCandidate{
+			ID:          c.ID,
+			ExecutionID: c.ExecutionID,
+			TaskID:      c.TaskID,
+			Repository:  c.Repository,
+			Digest:      c.Digest,
+			Tags:        split(c.Tags),
+			PushTime:    c.PushTime,
+			PullTime:    c.PullTime,
+			Action:      c.Action,
+			Rules:       split(c.Rules),
+		})
+	}
+	return candidates, nil
+}
+
+// CountDryRunCandidates Count Dry Run Candidates
+func (d *DefaultManager) CountDryRunCandidates(ctx context.Context, executionID int64, query *q.Query) (int64, error) {
+	query = q.MustClone(query)
+	query.Keywords["ExecutionID"] = executionID
+	return dao.CountDryRunCandidates(ctx, query)
+}
+
+func split(str string) []string {
+	if len(str) == 0 {
+		return []string{}
+	}
+	return strings.Split(str, ",")
+}
+
 // NewManager ...
 func NewManager() Manager {
 	return &DefaultManager{}
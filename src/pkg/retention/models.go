@@ -28,6 +28,11 @@ const (
 
 	ExecutionTriggerManual   string = "Manual"
 	ExecutionTriggerSchedule string = "Schedule"
+
+	DryRunActionRetain    string = "retain"
+	DryRunActionDelete    string = "delete"
+	DryRunActionImmutable string = "immutable"
+	DryRunActionError     string = "error"
 )
 
 // Execution of retention
@@ -68,3 +73,19 @@ type History struct {
 	Artifact  string    `json:"tag"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// DryRunCandidate is the candidate artifact evaluated by the dry run execution
+type DryRunCandidate struct {
+	ID          int64     `json:"id,omitempty"`
+	ExecutionID int64     `json:"execution_id,omitempty"`
+	TaskID      int64     `json:"task_id,omitempty"`
+	Repository  string    `json:"repository"`
+	Digest      string    `json:"digest"`
+	Tags        []string  `json:"tags"`
+	PushTime    time.Time `json:"push_time"`
+	PullTime    time.Time `json:"pull_time"`
+	// the action which is performed if it isn't a dry run, "retain", "delete", "immutable" or "error"
+	Action string `json:"action"`
+	// the template IDs of the rules retaining the artifact
+	Rules []string `json:"rules"`
+}
@@ -163,6 +163,53 @@ func (suite *TestBuilderSuite) TestBuild() {
 	})
 }
 
+// TestMatchRules tests the MatchRules function
+func (suite *TestBuilderSuite) TestMatchRules() {
+	params := make(rule.Parameters)
+	params[latestps.ParameterK] = 10
+
+	lm := &lwp.Metadata{
+		Algorithm: AlgorithmOR,
+		Rules: []*rule.Metadata{
+			{
+				ID:         1,
+				Action:     action.Retain,
+				Template:   latestps.TemplateID,
+				Parameters: params,
+				TagSelectors: []*rule.Selector{
+					{
+						Kind:       doublestar.Kind,
+						Decoration: doublestar.Matches,
+						Pattern:    "latest",
+					},
+				},
+			},
+			{
+				ID:         2,
+				Action:     action.Retain,
+				Template:   latestps.TemplateID,
+				Parameters: params,
+				TagSelectors: []*rule.Selector{
+					{
+						Kind:       label.Kind,
+						Decoration: label.With,
+						Pattern:    "L1",
+					},
+				},
+			},
+		},
+	}
+
+	matched, err := MatchRules(lm, suite.all)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), matched, 1)
+	rules := matched[suite.all[0].Hash()]
+	require.Len(suite.T(), rules, 2)
+	assert.Equal(suite.T(), 1, rules[0].ID)
+	assert.Equal(suite.T(), 2, rules[1].ID)
+	assert.Empty(suite.T(), matched[suite.all[1].Hash()])
+}
+
 type fakeRetentionClient struct{}
 
 func (frc *fakeRetentionClient) DeleteRepository(repo *selector.Repository) error {
@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/selector"
+	index2 "github.com/goharbor/harbor/src/lib/selector/selectors/index"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/lwp"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/index"
+)
+
+// MatchRules evaluates the rules of the policy one by one against the candidates without performing
+// any actions, and returns the rules retaining each candidate keyed by the hash of the candidate
+func MatchRules(policy *lwp.Metadata, candidates []*selector.Candidate) (map[string][]*rule.Metadata, error) {
+	if policy == nil {
+		return nil, errors.New("nil policy to match rules")
+	}
+
+	matched := make(map[string][]*rule.Metadata)
+	for _, r := range policy.Rules {
+		// keep the same with the processor, the rules without tag selectors are ignored
+		if r.Action != action.Retain || len(r.TagSelectors) == 0 {
+			continue
+		}
+
+		evaluator, err := index.Get(r.Template, r.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		// pass array copy to the selectors
+		processed := append([]*selector.Candidate{}, candidates...)
+		for _, s := range r.TagSelectors {
+			sel, err := index2.Get(s.Kind, s.Decoration, s.Pattern, s.Extras)
+			if err != nil {
+				return nil, errors.Wrap(err, "get selector by metadata")
+			}
+			if processed, err = sel.Select(processed); err != nil {
+				return nil, err
+			}
+		}
+
+		if processed, err = evaluator.Process(processed); err != nil {
+			return nil, err
+		}
+		for _, c := range processed {
+			matched[c.Hash()] = append(matched[c.Hash()], r)
+		}
+	}
+
+	return matched, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestk"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpl"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestps"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/severity"
 )
 
 // index for keeping the mapping between template ID and evaluator
@@ -163,6 +164,26 @@ func init() {
 			},
 		},
 	}, daysps.New, daysps.Valid)
+
+	// Register severity
+	Register(&Metadata{
+		TemplateID: severity.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     severity.ParameterSeverity,
+				Type:     "string",
+				Unit:     "severity",
+				Required: true,
+			},
+			{
+				Name:     severity.ParameterN,
+				Type:     "int",
+				Unit:     "days",
+				Required: false,
+			},
+		},
+	}, severity.New, severity.Valid)
 }
 
 // Register the rule evaluator with the corresponding rule template
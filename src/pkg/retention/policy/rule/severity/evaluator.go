@@ -0,0 +1,128 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package severity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+const (
+	// TemplateID of the rule
+	TemplateID = "vulnerabilitySeverityBelow"
+
+	// ParameterSeverity is the name of the metadata parameter for the severity,
+	// the artifacts whose overall vulnerability severity is lower than it are retained
+	ParameterSeverity = TemplateID
+
+	// ParameterN is the name of the metadata parameter for the N value,
+	// the artifacts pushed within the last N days are retained regardless of their severity
+	ParameterN = "nDaysSinceLastPush"
+
+	// DefaultSeverity is the default severity used when the parameter is missing or invalid
+	DefaultSeverity = vuln.Critical
+)
+
+type evaluator struct {
+	severity vuln.Severity
+	n        int
+}
+
+func (e *evaluator) Process(artifacts []*selector.Candidate) (result []*selector.Candidate, err error) {
+	minPushTime := time.Now().UTC().Add(time.Duration(-1*24*e.n) * time.Hour).Unix()
+	for _, a := range artifacts {
+		// the artifacts which are not scanned have the severity code of vuln.None
+		if a.VulnerabilitySeverity < uint(e.severity.Code()) || (e.n > 0 && a.PushedTime >= minPushTime) {
+			result = append(result, a)
+		}
+	}
+
+	return
+}
+
+func (e *evaluator) Action() string {
+	return action.Retain
+}
+
+// New constructs a new 'Vulnerability Severity Below' evaluator
+func New(params rule.Parameters) rule.Evaluator {
+	e := &evaluator{}
+	if params != nil {
+		if s, ok := parseSeverity(params[ParameterSeverity]); ok {
+			e.severity = s
+		}
+		if p, ok := params[ParameterN]; ok {
+			if v, ok := utils.ParseJSONInt(p); ok && v >= 0 {
+				e.n = int(v)
+			}
+		}
+	}
+
+	if len(e.severity) == 0 {
+		log.Warningf("default parameter %s used for rule %s", DefaultSeverity, TemplateID)
+		e.severity = DefaultSeverity
+	}
+
+	return e
+}
+
+// Valid ...
+func Valid(params rule.Parameters) error {
+	if params == nil {
+		return nil
+	}
+	if p, ok := params[ParameterSeverity]; ok {
+		if _, ok := parseSeverity(p); !ok {
+			return fmt.Errorf("%s should be one of %s, %s, %s, %s and %s", ParameterSeverity,
+				vuln.Negligible, vuln.Low, vuln.Medium, vuln.High, vuln.Critical)
+		}
+	}
+	if p, ok := params[ParameterN]; ok {
+		if v, ok := utils.ParseJSONInt(p); ok {
+			if v < 0 {
+				return fmt.Errorf("%s is less than zero", ParameterN)
+			}
+			if v > 20190904 {
+				return fmt.Errorf("%s is too large", ParameterN)
+			}
+		} else {
+			return fmt.Errorf("%s type error", ParameterN)
+		}
+	}
+	return nil
+}
+
+// parseSeverity parses the severity parameter, only the severities higher than vuln.None are valid
+func parseSeverity(p rule.Parameter) (vuln.Severity, bool) {
+	str, ok := p.(string)
+	if !ok {
+		return "", false
+	}
+	severity := vuln.Severity(strings.Title(strings.ToLower(str)))
+	switch severity {
+	case vuln.Negligible, vuln.Low, vuln.Medium, vuln.High, vuln.Critical:
+		return severity, true
+	default:
+		return "", false
+	}
+}
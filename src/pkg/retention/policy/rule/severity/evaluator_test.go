@@ -0,0 +1,124 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package severity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name             string
+		args             rule.Parameters
+		expectedSeverity vuln.Severity
+		expectedN        int
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "High", ParameterN: float64(7)}, expectedSeverity: vuln.High, expectedN: 7},
+		{Name: "Case Insensitive", args: map[string]rule.Parameter{ParameterSeverity: "medium"}, expectedSeverity: vuln.Medium},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Invalid", args: map[string]rule.Parameter{ParameterSeverity: "None"}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: 1, ParameterN: "foo"}, expectedSeverity: DefaultSeverity},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedSeverity, e.severity)
+			require.Equal(t, tt.expectedN, e.n)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	now := time.Now().UTC()
+	data := []*selector.Candidate{
+		{Digest: "none", VulnerabilitySeverity: uint(vuln.None.Code()), PushedTime: daysAgo(now, 30)},
+		{Digest: "low", VulnerabilitySeverity: uint(vuln.Low.Code()), PushedTime: daysAgo(now, 30)},
+		{Digest: "high", VulnerabilitySeverity: uint(vuln.High.Code()), PushedTime: daysAgo(now, 30)},
+		{Digest: "critical", VulnerabilitySeverity: uint(vuln.Critical.Code()), PushedTime: daysAgo(now, 30)},
+		{Digest: "critical-new", VulnerabilitySeverity: uint(vuln.Critical.Code()), PushedTime: daysAgo(now, 1)},
+	}
+
+	tests := []struct {
+		params   rule.Parameters
+		expected []string
+	}{
+		{params: rule.Parameters{ParameterSeverity: "Critical"}, expected: []string{"none", "low", "high"}},
+		{params: rule.Parameters{ParameterSeverity: "High"}, expected: []string{"none", "low"}},
+		{params: rule.Parameters{ParameterSeverity: "Negligible"}, expected: []string{"none"}},
+		{params: rule.Parameters{ParameterSeverity: "Critical", ParameterN: 7}, expected: []string{"none", "low", "high", "critical-new"}},
+		{params: rule.Parameters{ParameterSeverity: "Low", ParameterN: 7}, expected: []string{"none", "critical-new"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(fmt.Sprintf("%v", tt.params), func(t *testing.T) {
+			sut := New(tt.params)
+
+			result, err := sut.Process(data)
+			require.NoError(t, err)
+
+			var digests []string
+			for _, v := range result {
+				digests = append(digests, v.Digest)
+			}
+			assert.Equal(t, tt.expected, digests)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name     string
+		args     rule.Parameters
+		expected error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "critical", ParameterN: 7}, expected: nil},
+		{Name: "Invalid Severity", args: map[string]rule.Parameter{ParameterSeverity: "Unknown"}, expected: errors.New("vulnerabilitySeverityBelow should be one of Negligible, Low, Medium, High and Critical")},
+		{Name: "Negative", args: map[string]rule.Parameter{ParameterSeverity: "High", ParameterN: -1}, expected: errors.New("nDaysSinceLastPush is less than zero")},
+		{Name: "Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: "High", ParameterN: "foo"}, expected: errors.New("nDaysSinceLastPush type error")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestEvaluatorSuite(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
+
+func daysAgo(from time.Time, n int) int64 {
+	return from.Add(time.Duration(-1*24*n) * time.Hour).Unix()
+}
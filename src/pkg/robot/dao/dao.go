@@ -32,6 +32,10 @@ type DAO interface {
 
 	// DeleteByProjectID ...
 	DeleteByProjectID(ctx context.Context, projectID int64) error
+
+	// MarkExpirationNotified marks the expiration of the robot as notified, returns false
+	// if it has been marked already or the robot is refreshed with a new expiration time
+	MarkExpirationNotified(ctx context.Context, id, expiresAt int64) (bool, error)
 }
 
 // New creates a default implementation for Dao
@@ -131,3 +135,21 @@ func (d *dao) DeleteByProjectID(ctx context.Context, projectID int64) error {
 
 	return err
 }
+
+func (d *dao) MarkExpirationNotified(ctx context.Context, id, expiresAt int64) (bool, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	// only one of the concurrent callers can mark it successfully
+	res, err := ormer.Raw("UPDATE robot SET notified_expiresat = ? WHERE id = ? AND expiresat = ? AND notified_expiresat <> ?",
+		expiresAt, id, expiresAt, expiresAt).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
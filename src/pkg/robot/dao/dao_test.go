@@ -168,6 +168,34 @@ func (suite *DaoTestSuite) TestDeleteByProjectID() {
 	suite.Equal(0, len(robots))
 }
 
+func (suite *DaoTestSuite) TestMarkExpirationNotified() {
+	id, err := suite.dao.Create(orm.Context(), &model.Robot{
+		Name:      "test-expired",
+		ProjectID: 1,
+		ExpiresAt: 100,
+	})
+	suite.Require().Nil(err)
+	defer suite.dao.Delete(orm.Context(), id)
+
+	// the robot is refreshed with another expiration time
+	marked, err := suite.dao.MarkExpirationNotified(orm.Context(), id, 50)
+	suite.Nil(err)
+	suite.False(marked)
+
+	marked, err = suite.dao.MarkExpirationNotified(orm.Context(), id, 100)
+	suite.Nil(err)
+	suite.True(marked)
+
+	// marked already
+	marked, err = suite.dao.MarkExpirationNotified(orm.Context(), id, 100)
+	suite.Nil(err)
+	suite.False(marked)
+
+	r, err := suite.dao.Get(orm.Context(), id)
+	suite.Nil(err)
+	suite.Equal(int64(100), r.NotifiedExpiresAt)
+}
+
 func TestDaoTestSuite(t *testing.T) {
 	suite.Run(t, &DaoTestSuite{})
 }
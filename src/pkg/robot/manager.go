@@ -34,6 +34,10 @@ type Manager interface {
 
 	// List ...
 	List(ctx context.Context, query *q.Query) ([]*model.Robot, error)
+
+	// MarkExpirationNotified marks the expiration of the robot as notified, returns false
+	// if it has been marked already or the robot is refreshed with a new expiration time
+	MarkExpirationNotified(ctx context.Context, id, expiresAt int64) (bool, error)
 }
 
 var _ Manager = &manager{}
@@ -83,3 +87,8 @@ func (m *manager) Update(ctx context.Context, r *model.Robot, props ...string) e
 func (m *manager) List(ctx context.Context, query *q.Query) ([]*model.Robot, error) {
 	return m.dao.List(ctx, query)
 }
+
+// MarkExpirationNotified ...
+func (m *manager) MarkExpirationNotified(ctx context.Context, id, expiresAt int64) (bool, error) {
+	return m.dao.MarkExpirationNotified(ctx, id, expiresAt)
+}
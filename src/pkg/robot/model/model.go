@@ -26,6 +26,8 @@ type Robot struct {
 	Visible      bool      `orm:"column(visible)" json:"-"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+	// NotifiedExpiresAt is the expiration time of the robot which has been notified
+	NotifiedExpiresAt int64 `orm:"column(notified_expiresat)" json:"-"`
 }
 
 // TableName ...
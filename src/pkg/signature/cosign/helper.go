@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// signatureTagSuffix is the suffix of the tag cosign uses to store the signature of an artifact
+	signatureTagSuffix = ".sig"
+)
+
+// cosign stores the signature of the artifact "sha256:<hex>" under the tag "sha256-<hex>.sig"
+var signatureTagRegexp = regexp.MustCompile(`^(sha256|sha384|sha512)-([a-f0-9]+)\.sig$`)
+
+// SignatureTag returns the tag under which cosign stores the signature of the artifact specified by the digest
+func SignatureTag(dgst string) string {
+	return strings.Replace(dgst, ":", "-", 1) + signatureTagSuffix
+}
+
+// ParseSignatureTag checks whether the tag is a cosign signature tag, if so, returns
+// the digest of the subject artifact the signature is attached to
+func ParseSignatureTag(tag string) (string, bool) {
+	matches := signatureTagRegexp.FindStringSubmatch(tag)
+	if len(matches) != 3 {
+		return "", false
+	}
+	dgst := fmt.Sprintf("%s:%s", matches[1], matches[2])
+	if _, err := digest.Parse(dgst); err != nil {
+		return "", false
+	}
+	return dgst, true
+}
@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureTag(t *testing.T) {
+	assert.Equal(t, "sha256-418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180.sig",
+		SignatureTag("sha256:418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180"))
+}
+
+func TestParseSignatureTag(t *testing.T) {
+	cases := []struct {
+		tag    string
+		digest string
+		ok     bool
+	}{
+		{
+			tag:    "sha256-418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180.sig",
+			digest: "sha256:418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180",
+			ok:     true,
+		},
+		{
+			tag: "latest",
+			ok:  false,
+		},
+		{
+			tag: "sha256-418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180.att",
+			ok:  false,
+		},
+		{
+			// invalid length of the hex
+			tag: "sha256-418fb88e.sig",
+			ok:  false,
+		},
+	}
+	for _, c := range cases {
+		dgst, ok := ParseSignatureTag(c.tag)
+		assert.Equal(t, c.ok, ok, c.tag)
+		assert.Equal(t, c.digest, dgst, c.tag)
+	}
+}
@@ -338,9 +338,9 @@ export class CreateEditRuleComponent implements OnInit, OnDestroy {
         fbLabel.setControl('value', filterLabel);
         return fbLabel;
       }
-      if (filter.type === FilterType.TAG) {
+      if (filter.type === FilterType.TAG || filter.type === FilterType.PUSH_TIME) {
         return this.fb.group({
-          type: FilterType.TAG,
+          type: filter.type,
           decoration: filter.decoration || Decoration.MATCHES,
           value: filter.value
         });
@@ -361,7 +361,7 @@ export class CreateEditRuleComponent implements OnInit, OnDestroy {
       labelControl.setControl('value', labelArray);
       return labelControl;
     }
-    if (name === FilterType.TAG) {
+    if (name === FilterType.TAG || name === FilterType.PUSH_TIME) {
       return this.fb.group({
         type: name,
         decoration: Decoration.MATCHES,
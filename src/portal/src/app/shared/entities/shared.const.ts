@@ -76,7 +76,8 @@ export const FilterType = {
   NAME: "name",
   TAG: "tag",
   LABEL: "label",
-  RESOURCE: "resource"
+  RESOURCE: "resource",
+  PUSH_TIME: "push_time"
 };
 
 export const enum ConfirmationButtons {
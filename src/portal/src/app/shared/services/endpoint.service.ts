@@ -26,7 +26,10 @@ export const ADAPTERS_MAP = {
   "quay": "Quay",
   "dtr": "DTR",
   "tencent-tcr": "Tencent TCR",
-  "github-ghcr": "Github GHCR"
+  "github-ghcr": "Github GHCR",
+  "oci-layout": "OCI Image Layout",
+  "oracle-ocir": "Oracle OCIR",
+  "sonatype-nexus": "Sonatype Nexus"
 };
 
 export const HELM_HUB: string = "helm-hub";
@@ -8,6 +8,9 @@ import (
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/accessory"
+	accessorymodel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	"github.com/goharbor/harbor/src/pkg/signature"
 	"github.com/goharbor/harbor/src/server/middleware"
 	"github.com/goharbor/harbor/src/server/middleware/util"
@@ -26,6 +29,37 @@ var (
 		}
 		return checker.IsArtifactSigned(art.Digest), nil
 	}
+
+	// isArtifactCosignSigned checks whether the artifact has cosign signatures attached as accessories
+	isArtifactCosignSigned = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
+		a, err := artifact.Ctl.GetByReference(req.Context(), art.Repository, art.Digest, &artifact.Option{WithAccessory: true})
+		if err != nil {
+			return false, err
+		}
+		for _, acc := range a.Accessories {
+			if acc.Type == accessorymodel.TypeCosignSignature {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	// isCosignSignature checks whether the artifact is a cosign signature attached to another artifact,
+	// the signatures aren't signed themselves but must be pullable for the verification
+	isCosignSignature = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
+		a, err := artifact.Ctl.GetByReference(req.Context(), art.Repository, art.Digest, nil)
+		if err != nil {
+			return false, err
+		}
+		count, err := accessory.Mgr.Count(req.Context(), q.New(q.KeyWords{
+			"ArtifactID": a.ID,
+			"Type":       accessorymodel.TypeCosignSignature,
+		}))
+		if err != nil {
+			return false, err
+		}
+		return count > 0, nil
+	}
 )
 
 // Middleware handle docker pull content trust check
@@ -59,12 +93,27 @@ func Middleware() func(http.Handler) http.Handler {
 		}
 
 		if pro.ContentTrustEnabled() {
+			sig, err := isCosignSignature(r, af)
+			if err != nil {
+				return err
+			}
+			if sig {
+				logger.Debugf("artifact %s@%s is a cosign signature, skip the checking", af.Repository, af.Digest)
+				return nil
+			}
 			match, err := isArtifactSigned(r, af)
 			if err != nil {
 				return err
 			}
+			// the artifact isn't signed in Notary, accept the cosign signature as well
+			if !match {
+				match, err = isArtifactCosignSigned(r, af)
+				if err != nil {
+					return err
+				}
+			}
 			if !match {
-				pkgE := errors.New(nil).WithCode(errors.PROJECTPOLICYVIOLATION).WithMessage("The image is not signed in Notary.")
+				pkgE := errors.New(nil).WithCode(errors.PROJECTPOLICYVIOLATION).WithMessage("The image is not signed in Notary or by cosign.")
 				return pkgE
 			}
 		}
@@ -26,10 +26,13 @@ import (
 	"github.com/goharbor/harbor/src/controller/artifact/processor/image"
 	"github.com/goharbor/harbor/src/controller/project"
 	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/pkg/accessory"
+	accessorymodel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	securitytesting "github.com/goharbor/harbor/src/testing/common/security"
 	artifacttesting "github.com/goharbor/harbor/src/testing/controller/artifact"
 	projecttesting "github.com/goharbor/harbor/src/testing/controller/project"
 	"github.com/goharbor/harbor/src/testing/mock"
+	accessorytesting "github.com/goharbor/harbor/src/testing/pkg/accessory"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -42,11 +45,16 @@ type MiddlewareTestSuite struct {
 	originalProjectController project.Controller
 	projectController         *projecttesting.Controller
 
+	originalAccessoryManager accessory.Manager
+	accessoryManager         *accessorytesting.Manager
+
 	artifact *artifact.Artifact
 	project  *proModels.Project
 
-	isArtifactSigned func(req *http.Request, art lib.ArtifactInfo) (bool, error)
-	next             http.Handler
+	isArtifactSigned       func(req *http.Request, art lib.ArtifactInfo) (bool, error)
+	isArtifactCosignSigned func(req *http.Request, art lib.ArtifactInfo) (bool, error)
+	isCosignSignature      func(req *http.Request, art lib.ArtifactInfo) (bool, error)
+	next                   http.Handler
 }
 
 func (suite *MiddlewareTestSuite) SetupTest() {
@@ -58,7 +66,13 @@ func (suite *MiddlewareTestSuite) SetupTest() {
 	suite.projectController = &projecttesting.Controller{}
 	project.Ctl = suite.projectController
 
+	suite.originalAccessoryManager = accessory.Mgr
+	suite.accessoryManager = &accessorytesting.Manager{}
+	accessory.Mgr = suite.accessoryManager
+
 	suite.isArtifactSigned = isArtifactSigned
+	suite.isArtifactCosignSigned = isArtifactCosignSigned
+	suite.isCosignSignature = isCosignSignature
 	suite.artifact = &artifact.Artifact{}
 	suite.artifact.Type = image.ArtifactTypeImage
 	suite.artifact.ProjectID = 1
@@ -80,11 +94,21 @@ func (suite *MiddlewareTestSuite) SetupTest() {
 	isArtifactSigned = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
 		return false, nil
 	}
+	isArtifactCosignSigned = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
+		return false, nil
+	}
+	isCosignSignature = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
+		return false, nil
+	}
 }
 
 func (suite *MiddlewareTestSuite) TearDownTest() {
 	artifact.Ctl = suite.originalArtifactController
 	project.Ctl = suite.originalProjectController
+	accessory.Mgr = suite.originalAccessoryManager
+	isArtifactSigned = suite.isArtifactSigned
+	isArtifactCosignSigned = suite.isArtifactCosignSigned
+	isCosignSignature = suite.isCosignSignature
 }
 
 func (suite *MiddlewareTestSuite) makeRequest() *http.Request {
@@ -187,6 +211,77 @@ func (suite *MiddlewareTestSuite) TestUnAuthenticatedUserPulling() {
 	suite.Equal(rr.Code, http.StatusPreconditionFailed)
 }
 
+// pull an image signed by cosign when policy checker is enabled.
+func (suite *MiddlewareTestSuite) TestCosignSignedPulling() {
+	mock.OnAnything(suite.artifactController, "GetByReference").Return(suite.artifact, nil)
+	mock.OnAnything(suite.projectController, "GetByName").Return(suite.project, nil)
+	isArtifactCosignSigned = func(req *http.Request, art lib.ArtifactInfo) (bool, error) {
+		return true, nil
+	}
+
+	req := suite.makeRequest()
+	rr := httptest.NewRecorder()
+
+	Middleware()(suite.next).ServeHTTP(rr, req)
+	suite.Equal(rr.Code, http.StatusOK)
+}
+
+// pull the cosign signature which isn't signed itself when policy checker is enabled.
+func (suite *MiddlewareTestSuite) TestCosignSignaturePulling() {
+	sig := &artifact.Artifact{}
+	sig.ID = 2
+	sig.ProjectID = 1
+	sig.RepositoryName = "library/photon"
+	sig.Digest = "sha256:sig"
+	mock.OnAnything(suite.artifactController, "GetByReference").Return(sig, nil)
+	mock.OnAnything(suite.projectController, "GetByName").Return(suite.project, nil)
+	mock.OnAnything(suite.accessoryManager, "Count").Return(int64(1), nil)
+	isCosignSignature = suite.isCosignSignature
+
+	req := httptest.NewRequest("GET", "/v2/library/photon/manifests/sha256-abcdef.sig", nil)
+	req = req.WithContext(lib.WithArtifactInfo(req.Context(), lib.ArtifactInfo{
+		Repository: "library/photon",
+		Reference:  "sha256-abcdef.sig",
+		Tag:        "sha256-abcdef.sig",
+	}))
+	rr := httptest.NewRecorder()
+
+	Middleware()(suite.next).ServeHTTP(rr, req)
+	suite.Equal(http.StatusOK, rr.Code)
+}
+
+// the artifact isn't a cosign signature, the signature checking is still required
+func (suite *MiddlewareTestSuite) TestIsCosignSignature() {
+	mock.OnAnything(suite.artifactController, "GetByReference").Return(suite.artifact, nil)
+	mock.OnAnything(suite.accessoryManager, "Count").Return(int64(0), nil)
+
+	sig, err := suite.isCosignSignature(suite.makeRequest(), lib.ArtifactInfo{
+		Repository: "library/photon",
+		Digest:     "digest",
+	})
+	suite.Require().Nil(err)
+	suite.False(sig)
+}
+
+func (suite *MiddlewareTestSuite) TestIsArtifactCosignSigned() {
+	art := &artifact.Artifact{}
+	art.Accessories = []*accessorymodel.Accessory{
+		{
+			ArtifactID:        2,
+			SubjectArtifactID: 1,
+			Type:              accessorymodel.TypeCosignSignature,
+		},
+	}
+	mock.OnAnything(suite.artifactController, "GetByReference").Return(art, nil)
+
+	signed, err := suite.isArtifactCosignSigned(suite.makeRequest(), lib.ArtifactInfo{
+		Repository: "library/photon",
+		Digest:     "digest",
+	})
+	suite.Require().Nil(err)
+	suite.True(signed)
+}
+
 func TestMiddlewareTestSuite(t *testing.T) {
 	suite.Run(t, &MiddlewareTestSuite{})
 }
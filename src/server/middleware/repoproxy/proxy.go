@@ -30,6 +30,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/errors"
 	httpLib "github.com/goharbor/harbor/src/lib/http"
 	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/metric"
 	"github.com/goharbor/harbor/src/lib/orm"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -41,6 +42,8 @@ const (
 	contentType         = "Content-Type"
 	dockerContentDigest = "Docker-Content-Digest"
 	etag                = "Etag"
+	warning             = "Warning"
+	staleWarning        = `110 - "Response is Stale"`
 	ensureTagInterval   = 10 * time.Second
 	ensureTagMaxRetry   = 60
 )
@@ -81,6 +84,50 @@ func handleBlob(w http.ResponseWriter, r *http.Request, next http.Handler) error
 	return nil
 }
 
+// TagsListMiddleware lists the tags of the repository in the upstream registries for the proxy cache project,
+// the tags are injected into the context and merged with the local ones by the handler
+func TagsListMiddleware() func(http.Handler) http.Handler {
+	return middleware.New(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		tags, err := handleTagsList(r)
+		if err != nil {
+			log.Warningf("failed to list tags from the upstream registries, fallback to local, request uri: %v, error: %v", r.RequestURI, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if tags != nil {
+			r = r.WithContext(lib.WithUpstreamTags(r.Context(), tags))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func handleTagsList(r *http.Request) ([]string, error) {
+	ctx := r.Context()
+	art, p, proxyCtl, err := preCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !canProxy(ctx, p) {
+		return nil, nil
+	}
+	remote, err := proxy.NewRemoteHelper(ctx, p.ProxyRegistryIDs()...)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := proxyCtl.ProxyTags(ctx, art, remote)
+	if err != nil {
+		// the repository doesn't exist in the upstream registries, only the local tags are listed
+		if errors.IsNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
 func preCheck(ctx context.Context) (art lib.ArtifactInfo, p *proModels.Project, ctl proxy.Controller, err error) {
 	none := lib.ArtifactInfo{}
 	art = lib.GetArtifactInfo(ctx)
@@ -88,7 +135,8 @@ func preCheck(ctx context.Context) (art lib.ArtifactInfo, p *proModels.Project,
 		return none, nil, nil, errors.New("artifactinfo is not found").WithCode(errors.NotFoundCode)
 	}
 	ctl = proxy.ControllerInstance()
-	p, err = project.Ctl.GetByName(ctx, art.ProjectName, project.Metadata(false))
+	// the metadata contains the fallback upstream registries of the proxy cache project
+	p, err = project.Ctl.GetByName(ctx, art.ProjectName)
 	return
 }
 
@@ -96,7 +144,7 @@ func preCheck(ctx context.Context) (art lib.ArtifactInfo, p *proModels.Project,
 func ManifestMiddleware() func(http.Handler) http.Handler {
 	return middleware.New(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
 		if err := handleManifest(w, r, next); err != nil {
-			if errors.IsNotFoundErr(err) {
+			if errors.IsNotFoundErr(err) || errors.IsErr(err, errors.PreconditionCode) {
 				httpLib.SendError(w, err)
 				return
 			}
@@ -112,18 +160,38 @@ func handleManifest(w http.ResponseWriter, r *http.Request, next http.Handler) e
 	if err != nil {
 		return err
 	}
+	// serve the stale manifest when the upstream registries are unreachable
+	onUpstreamErr := func(upstreamErr error) error {
+		served, err := serveStaleManifest(w, r, next, proxyCtl, p, art, upstreamErr)
+		if err != nil {
+			return err
+		}
+		if served {
+			return nil
+		}
+		return upstreamErr
+	}
 	if !canProxy(r.Context(), p) {
+		if p.IsProxy() {
+			if served, err := serveStaleManifest(w, r, next, proxyCtl, p, art,
+				errors.New(nil).WithMessage("no healthy upstream registry")); err != nil || served {
+				return err
+			}
+		}
 		next.ServeHTTP(w, r)
 		return nil
 	}
-	remote, err := proxy.NewRemoteHelper(r.Context(), p.RegistryID)
+	remote, err := proxy.NewRemoteHelper(r.Context(), p.ProxyRegistryIDs()...)
 	if err != nil {
-		return err
+		return onUpstreamErr(err)
 	}
 	useLocal, man, err := proxyCtl.UseLocalManifest(ctx, art, remote)
 
 	if err != nil {
-		return err
+		if errors.IsNotFoundErr(err) {
+			return err
+		}
+		return onUpstreamErr(err)
 	}
 	if useLocal {
 		if man != nil {
@@ -152,12 +220,46 @@ func handleManifest(w http.ResponseWriter, r *http.Request, next http.Handler) e
 		if errors.IsNotFoundErr(err) {
 			return err
 		}
+		if served, e := serveStaleManifest(w, r, next, proxyCtl, p, art, err); e != nil || served {
+			return e
+		}
 		log.Warningf("Proxy to remote failed, fallback to local repo, error: %v", err)
 		next.ServeHTTP(w, r)
 	}
 	return nil
 }
 
+// serveStaleManifest serves the manifest cached locally when the upstream registries are unreachable
+// if the project enables it. The served is false when the project doesn't enable it or the manifest
+// isn't cached, and an error is returned when the cached manifest exceeds the max staleness
+func serveStaleManifest(w http.ResponseWriter, r *http.Request, next http.Handler, ctl proxy.Controller,
+	p *proModels.Project, art lib.ArtifactInfo, upstreamErr error) (bool, error) {
+	// the content referenced by digest never changes, only the tag can be stale
+	if !p.ProxyServeStale() || len(art.Tag) == 0 {
+		return false, nil
+	}
+	age, exist, err := ctl.CachedManifestAge(r.Context(), art)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return false, nil
+	}
+	if maxStaleness := p.ProxyMaxStaleness(); maxStaleness > 0 && age > maxStaleness {
+		log.Errorf("the upstream registry is unreachable and the cached manifest of %s:%s is validated %v ago, error: %v",
+			art.Repository, art.Tag, age, upstreamErr)
+		return false, errors.New(nil).WithCode(errors.PreconditionCode).
+			WithMessage("the upstream registry is unreachable and the cached manifest of %s:%s exceeds the max staleness %v",
+				art.Repository, art.Tag, maxStaleness)
+	}
+	log.Warningf("the upstream registry is unreachable, serve the cached manifest of %s:%s which is validated %v ago, error: %v",
+		art.Repository, art.Tag, age, upstreamErr)
+	w.Header().Set(warning, staleWarning)
+	metric.ProxyStaleServeCnt.WithLabelValues(p.Name).Inc()
+	next.ServeHTTP(w, r)
+	return true, nil
+}
+
 func proxyManifestGet(ctx context.Context, w http.ResponseWriter, ctl proxy.Controller, p *proModels.Project, art lib.ArtifactInfo, remote proxy.RemoteInterface) error {
 	man, err := ctl.ProxyManifest(ctx, art, remote)
 	if err != nil {
@@ -178,15 +280,19 @@ func canProxy(ctx context.Context, p *proModels.Project) bool {
 	if p.RegistryID < 1 {
 		return false
 	}
-	reg, err := registry.Ctl.Get(ctx, p.RegistryID)
-	if err != nil {
-		log.Errorf("failed to get registry, error:%v", err)
-		return false
-	}
-	if reg.Status != model.Healthy {
+	// the project can be proxied when any of the upstream registries is healthy
+	for _, regID := range p.ProxyRegistryIDs() {
+		reg, err := registry.Ctl.Get(ctx, regID)
+		if err != nil {
+			log.Errorf("failed to get registry, error:%v", err)
+			continue
+		}
+		if reg.Status == model.Healthy {
+			return true
+		}
 		log.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
 	}
-	return reg.Status == model.Healthy
+	return false
 }
 
 func setHeaders(w http.ResponseWriter, size int64, mediaType string, dig string) {
@@ -16,11 +16,19 @@ package repoproxy
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/common/security/proxycachesecret"
 	securitySecret "github.com/goharbor/harbor/src/common/security/secret"
+	"github.com/goharbor/harbor/src/controller/proxy"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 )
 
 func TestIsProxySession(t *testing.T) {
@@ -56,3 +64,87 @@ func TestIsProxySession(t *testing.T) {
 		})
 	}
 }
+
+// fakeProxyController only implements the methods used by serving stale manifest
+type fakeProxyController struct {
+	proxy.Controller
+	age   time.Duration
+	exist bool
+}
+
+func (f *fakeProxyController) CachedManifestAge(ctx context.Context, art lib.ArtifactInfo) (time.Duration, bool, error) {
+	return f.age, f.exist, nil
+}
+
+func TestServeStaleManifest(t *testing.T) {
+	cases := []struct {
+		name     string
+		metadata map[string]string
+		tag      string
+		ctl      *fakeProxyController
+		served   bool
+		err      bool
+	}{
+		{
+			name:     `not enabled`,
+			metadata: map[string]string{},
+			tag:      "latest",
+			ctl:      &fakeProxyController{age: time.Minute, exist: true},
+			served:   false,
+		},
+		{
+			name:     `pull by digest`,
+			metadata: map[string]string{proModels.ProMetaProxyServeStale: "true"},
+			ctl:      &fakeProxyController{age: time.Minute, exist: true},
+			served:   false,
+		},
+		{
+			name:     `not cached`,
+			metadata: map[string]string{proModels.ProMetaProxyServeStale: "true"},
+			tag:      "latest",
+			ctl:      &fakeProxyController{exist: false},
+			served:   false,
+		},
+		{
+			name:     `exceed max staleness`,
+			metadata: map[string]string{proModels.ProMetaProxyServeStale: "true", proModels.ProMetaProxyMaxStaleness: "60"},
+			tag:      "latest",
+			ctl:      &fakeProxyController{age: 2 * time.Hour, exist: true},
+			served:   false,
+			err:      true,
+		},
+		{
+			name:     `served`,
+			metadata: map[string]string{proModels.ProMetaProxyServeStale: "true", proModels.ProMetaProxyMaxStaleness: "60"},
+			tag:      "latest",
+			ctl:      &fakeProxyController{age: time.Minute, exist: true},
+			served:   true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &proModels.Project{Name: "proxy", RegistryID: 1, Metadata: tt.metadata}
+			art := lib.ArtifactInfo{Repository: "proxy/library/hello-world", Tag: tt.tag, Digest: "sha256:1234"}
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { nextCalled = true })
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v2/proxy/library/hello-world/manifests/latest", nil)
+
+			served, err := serveStaleManifest(w, r, next, tt.ctl, p, art, fmt.Errorf("upstream error"))
+			if tt.err {
+				if err == nil || !errors.IsErr(err, errors.PreconditionCode) {
+					t.Errorf("expected precondition error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if served != tt.served || nextCalled != tt.served {
+				t.Errorf("served = %v, next called = %v; want %v", served, nextCalled, tt.served)
+			}
+			if tt.served && w.Header().Get(warning) != staleWarning {
+				t.Errorf("warning header = %q; want %q", w.Header().Get(warning), staleWarning)
+			}
+		})
+	}
+}
@@ -44,6 +44,7 @@ func RegisterRoutes() {
 		Method(http.MethodGet).
 		Path("/*/tags/list").
 		Middleware(metric.InjectOpIDMiddleware(metric.ListTagOperationID)).
+		Middleware(repoproxy.TagsListMiddleware()).
 		Handler(newTagHandler())
 	// manifest
 	root.NewRoute().
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"github.com/goharbor/harbor/src/controller/repository"
 	"github.com/goharbor/harbor/src/controller/tag"
+	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	lib_http "github.com/goharbor/harbor/src/lib/http"
 	"github.com/goharbor/harbor/src/lib/q"
@@ -70,33 +71,16 @@ func (t *tagHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	tagNames := make([]string, 0)
-
 	t.repositoryName = router.Param(req.Context(), ":splat")
-	repository, err := t.repoCtl.GetByName(req.Context(), t.repositoryName)
+	tagNames, err := t.listTags(req)
 	if err != nil {
 		lib_http.SendError(w, err)
 		return
 	}
-
-	// get tags ...
-	tags, err := t.tagCtl.List(req.Context(), &q.Query{
-		Keywords: map[string]interface{}{
-			"RepositoryID": repository.RepositoryID,
-		}}, nil)
-	if err != nil {
-		lib_http.SendError(w, err)
-		return
-	}
-	if len(tags) == 0 {
+	if len(tagNames) == 0 {
 		t.sendResponse(w, req, tagNames)
 		return
 	}
-
-	for _, tag := range tags {
-		tagNames = append(tagNames, tag.Name)
-	}
-	sort.Strings(tagNames)
 	if !withN {
 		t.sendResponse(w, req, tagNames)
 		return
@@ -145,6 +129,53 @@ func (t *tagHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	return
 }
 
+// listTags returns the sorted tag names of the repository, for the proxy cache project,
+// the tags listed from the upstream registries are merged with the local ones
+func (t *tagHandler) listTags(req *http.Request) ([]string, error) {
+	upstreamTags, proxied := lib.GetUpstreamTags(req.Context())
+	tagNames := make([]string, 0)
+	repository, err := t.repoCtl.GetByName(req.Context(), t.repositoryName)
+	if err != nil {
+		// the repository isn't cached yet
+		if proxied && errors.IsNotFoundErr(err) {
+			return mergeTags(tagNames, upstreamTags), nil
+		}
+		return nil, err
+	}
+
+	// get tags ...
+	tags, err := t.tagCtl.List(req.Context(), &q.Query{
+		Keywords: map[string]interface{}{
+			"RepositoryID": repository.RepositoryID,
+		}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+	if proxied {
+		return mergeTags(tagNames, upstreamTags), nil
+	}
+	sort.Strings(tagNames)
+	return tagNames, nil
+}
+
+// mergeTags merges the tags and removes the duplicated ones, the result is sorted
+func mergeTags(tags []string, others []string) []string {
+	set := make(map[string]struct{}, len(tags)+len(others))
+	result := make([]string, 0, len(tags)+len(others))
+	for _, tag := range append(tags, others...) {
+		if _, exist := set[tag]; exist {
+			continue
+		}
+		set[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
 // sendResponse ...
 func (t *tagHandler) sendResponse(w http.ResponseWriter, req *http.Request, tagNames []string) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -18,6 +18,8 @@ import (
 	"encoding/json"
 	"github.com/goharbor/harbor/src/controller/repository"
 	"github.com/goharbor/harbor/src/controller/tag"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/repository/model"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	repotesting "github.com/goharbor/harbor/src/testing/controller/repository"
@@ -200,6 +202,59 @@ func (c *tagTestSuite) TestListTagPagination3() {
 	c.Equal("v2", ctlg.Tags[0])
 }
 
+func (c *tagTestSuite) TestListTagWithUpstreamTags() {
+	c.SetupTest()
+	req := httptest.NewRequest(http.MethodGet, "/v2/dockerhub/library/hello-world/tags/list", nil)
+	req = req.WithContext(lib.WithUpstreamTags(req.Context(), []string{"v3", "v1"}))
+	mock.OnAnything(c.repoCtl, "GetByName").Return(&model.RepoRecord{
+		RepositoryID: 1,
+		Name:         "dockerhub/library/hello-world",
+	}, nil)
+	c.tagCtl.On("List").Return([]*tag.Tag{
+		{
+			Tag: model_tag.Tag{
+				RepositoryID: 1,
+				Name:         "v1",
+			},
+		},
+		{
+			Tag: model_tag.Tag{
+				RepositoryID: 1,
+				Name:         "v2",
+			},
+		},
+	}, nil)
+	w := httptest.NewRecorder()
+	newTagHandler().ServeHTTP(w, req)
+	c.Equal(http.StatusOK, w.Code)
+	var tagsAPIResponse struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	decoder := json.NewDecoder(w.Body)
+	err := decoder.Decode(&tagsAPIResponse)
+	c.Nil(err)
+	c.Equal([]string{"v1", "v2", "v3"}, tagsAPIResponse.Tags)
+}
+
+func (c *tagTestSuite) TestListTagNotCachedRepository() {
+	c.SetupTest()
+	req := httptest.NewRequest(http.MethodGet, "/v2/dockerhub/library/hello-world/tags/list", nil)
+	req = req.WithContext(lib.WithUpstreamTags(req.Context(), []string{"v2", "v1"}))
+	mock.OnAnything(c.repoCtl, "GetByName").Return(nil, errors.NotFoundError(nil))
+	w := httptest.NewRecorder()
+	newTagHandler().ServeHTTP(w, req)
+	c.Equal(http.StatusOK, w.Code)
+	var tagsAPIResponse struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	decoder := json.NewDecoder(w.Body)
+	err := decoder.Decode(&tagsAPIResponse)
+	c.Nil(err)
+	c.Equal([]string{"v1", "v2"}, tagsAPIResponse.Tags)
+}
+
 func TestTagTestSuite(t *testing.T) {
 	suite.Run(t, &tagTestSuite{})
 }
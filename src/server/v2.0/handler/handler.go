@@ -51,6 +51,7 @@ func New() http.Handler {
 		LdapAPI:               newLdapAPI(),
 		LabelAPI:              newLabelAPI(),
 		GCAPI:                 newGCAPI(),
+		PurgeAPI:              newPurgeAPI(),
 		QuotaAPI:              newQuotaAPI(),
 		RetentionAPI:          newRetentionAPI(),
 		WebhookAPI:            newNotificationPolicyAPI(),
@@ -64,6 +65,7 @@ func New() http.Handler {
 		HealthAPI:             newHealthAPI(),
 		StatisticAPI:          newStatisticAPI(),
 		ProjectMetadataAPI:    newProjectMetadaAPI(),
+		WarmupAPI:             newWarmUpAPI(),
 	})
 	if err != nil {
 		log.Fatal(err)
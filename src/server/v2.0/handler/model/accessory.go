@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/go-openapi/strfmt"
+	"github.com/goharbor/harbor/src/pkg/accessory/model"
+	"github.com/goharbor/harbor/src/server/v2.0/models"
+)
+
+// Accessory model
+type Accessory struct {
+	*model.Accessory
+}
+
+// ToSwagger converts the accessory to the swagger model
+func (a *Accessory) ToSwagger() *models.Accessory {
+	return &models.Accessory{
+		ArtifactID:        a.ArtifactID,
+		CreationTime:      strfmt.DateTime(a.CreationTime),
+		Digest:            a.Digest,
+		ID:                a.ID,
+		Size:              a.Size,
+		SubjectArtifactID: a.SubjectArtifactID,
+		Type:              a.Type,
+	}
+}
+
+// NewAccessory ...
+func NewAccessory(a *model.Accessory) *Accessory {
+	return &Accessory{Accessory: a}
+}
@@ -63,6 +63,9 @@ func (a *Artifact) ToSwagger() *models.Artifact {
 	for _, label := range a.Labels {
 		art.Labels = append(art.Labels, NewLabel(label).ToSwagger())
 	}
+	for _, accessory := range a.Accessories {
+		art.Accessories = append(art.Accessories, NewAccessory(accessory).ToSwagger())
+	}
 	if len(a.ScanOverview) > 0 {
 		art.ScanOverview = models.ScanOverview{}
 		for key, value := range a.ScanOverview {
@@ -14,14 +14,15 @@ type NotificationJob struct {
 // ToSwagger ...
 func (n *NotificationJob) ToSwagger() *models.WebhookJob {
 	return &models.WebhookJob{
-		ID:           n.ID,
-		EventType:    n.EventType,
-		JobDetail:    n.JobDetail,
-		NotifyType:   n.NotifyType,
-		PolicyID:     n.PolicyID,
-		Status:       n.Status,
-		CreationTime: strfmt.DateTime(n.CreationTime),
-		UpdateTime:   strfmt.DateTime(n.UpdateTime),
+		ID:            n.ID,
+		EventType:     n.EventType,
+		JobDetail:     n.JobDetail,
+		NotifyType:    n.NotifyType,
+		PolicyID:      n.PolicyID,
+		Status:        n.Status,
+		TargetAddress: n.TargetAddress,
+		CreationTime:  strfmt.DateTime(n.CreationTime),
+		UpdateTime:    strfmt.DateTime(n.UpdateTime),
 	}
 }
 
@@ -31,3 +32,27 @@ func NewNotificationJob(j *model.Job) *NotificationJob {
 		Job: j,
 	}
 }
+
+// NotificationJobAttempt ...
+type NotificationJobAttempt struct {
+	*model.Attempt
+}
+
+// ToSwagger ...
+func (n *NotificationJobAttempt) ToSwagger() *models.WebhookJobAttempt {
+	return &models.WebhookJobAttempt{
+		ID:           n.ID,
+		JobID:        n.JobID,
+		StatusCode:   int64(n.StatusCode),
+		Response:     n.Response,
+		Error:        n.Error,
+		CreationTime: strfmt.DateTime(n.CreationTime),
+	}
+}
+
+// NewNotificationJobAttempt ...
+func NewNotificationJobAttempt(a *model.Attempt) *NotificationJobAttempt {
+	return &NotificationJobAttempt{
+		Attempt: a,
+	}
+}
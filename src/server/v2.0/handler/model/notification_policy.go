@@ -24,6 +24,19 @@ func (n *NotifiactionPolicy) ToSwagger() *models.WebhookPolicy {
 		Name:         n.Name,
 		ProjectID:    n.ProjectID,
 		Targets:      n.ToTargets(),
+		RetryPolicy:  n.ToRetryPolicy(),
+	}
+}
+
+// ToRetryPolicy ...
+func (n *NotifiactionPolicy) ToRetryPolicy() *models.WebhookRetryPolicy {
+	if n.RetryPolicy == nil {
+		return nil
+	}
+	return &models.WebhookRetryPolicy{
+		MaxRetries: int64(n.RetryPolicy.MaxRetries),
+		Backoff:    int64(n.RetryPolicy.Backoff),
+		MaxBackoff: int64(n.RetryPolicy.MaxBackoff),
 	}
 }
 
@@ -36,6 +49,7 @@ func (n *NotifiactionPolicy) ToTargets() []*models.WebhookTargetObject {
 			Address:        t.Address,
 			AuthHeader:     t.AuthHeader,
 			SkipCertVerify: t.SkipCertVerify,
+			PayloadFormat:  t.PayloadFormat,
 		})
 	}
 	return results
@@ -72,3 +72,20 @@ func (e *RetentionTask) ToSwagger() *models.RetentionExecutionTask {
 func NewRetentionTask(task *retention.Task) *RetentionTask {
 	return &RetentionTask{task}
 }
+
+// RetentionCandidate ...
+type RetentionCandidate struct {
+	*retention.DryRunCandidate
+}
+
+// ToSwagger ...
+func (c *RetentionCandidate) ToSwagger() *models.RetentionExecutionCandidate {
+	var result models.RetentionExecutionCandidate
+	lib.JSONCopy(&result, c)
+	return &result
+}
+
+// NewRetentionCandidate ...
+func NewRetentionCandidate(candidate *retention.DryRunCandidate) *RetentionCandidate {
+	return &RetentionCandidate{candidate}
+}
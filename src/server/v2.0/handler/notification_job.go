@@ -2,10 +2,18 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+
 	"github.com/go-openapi/runtime/middleware"
+	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/controller/event/handler/util"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/notification/job"
+	job_model "github.com/goharbor/harbor/src/pkg/notification/job/model"
 	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	notifier_model "github.com/goharbor/harbor/src/pkg/notifier/model"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 	"github.com/goharbor/harbor/src/server/v2.0/restapi/operations/webhookjob"
@@ -65,3 +73,103 @@ func (n *notificationJobAPI) ListWebhookJobs(ctx context.Context, params webhook
 		WithLink(n.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
 		WithPayload(results)
 }
+
+func (n *notificationJobAPI) ListWebhookJobAttempts(ctx context.Context, params webhookjob.ListWebhookJobAttemptsParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionList, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	j, _, err := n.getJobOfProject(ctx, projectNameOrID, params.WebhookJobID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	attempts, err := n.webhookjobMgr.ListAttempts(ctx, j.ID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	var results []*models.WebhookJobAttempt
+	for _, a := range attempts {
+		results = append(results, model.NewNotificationJobAttempt(a).ToSwagger())
+	}
+
+	return operation.NewListWebhookJobAttemptsOK().WithPayload(results)
+}
+
+func (n *notificationJobAPI) RedeliverWebhookJob(ctx context.Context, params webhookjob.RedeliverWebhookJobParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	j, ply, err := n.getJobOfProject(ctx, projectNameOrID, params.WebhookJobID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	if j.Status != common_models.JobError && j.Status != common_models.JobStopped {
+		return n.SendError(ctx, errors.PreconditionFailedError(nil).
+			WithMessage("only the failed or stopped webhook job can be re-delivered, the status of job %d is %s", j.ID, j.Status))
+	}
+
+	target := findJobTarget(ply, j)
+	if target == nil {
+		return n.SendError(ctx, errors.PreconditionFailedError(nil).
+			WithMessage("the target %s of webhook job %d doesn't exist in policy %d any more", j.TargetAddress, j.ID, ply.ID))
+	}
+
+	payload := &notifier_model.Payload{}
+	if err := json.Unmarshal([]byte(j.JobDetail), payload); err != nil {
+		return n.SendError(ctx, errors.Wrapf(err, "failed to decode the payload of webhook job %d", j.ID))
+	}
+
+	// only re-deliver the payload to the target of the job
+	p := *ply
+	p.Targets = []policy_model.EventTarget{*target}
+	if err := util.SendHookWithPolicies([]*policy_model.Policy{&p}, payload, j.EventType); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return operation.NewRedeliverWebhookJobAccepted()
+}
+
+// getJobOfProject returns the webhook job and its policy, a not found error is returned
+// if the policy of the job doesn't belong to the specified project
+func (n *notificationJobAPI) getJobOfProject(ctx context.Context, projectNameOrID interface{}, jobID int64) (*job_model.Job, *policy_model.Policy, error) {
+	projectID, err := getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	j, err := n.webhookjobMgr.Get(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ply, err := n.webhookPolicyMgr.Get(ctx, j.PolicyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ply.ProjectID != projectID {
+		return nil, nil, errors.NotFoundError(nil).WithMessage("webhook job %d not found", jobID)
+	}
+	return j, ply, nil
+}
+
+// findJobTarget returns the target of policy which the job delivers to
+func findJobTarget(ply *policy_model.Policy, j *job_model.Job) *policy_model.EventTarget {
+	var candidates []policy_model.EventTarget
+	for _, target := range ply.Targets {
+		if target.Type != j.NotifyType {
+			continue
+		}
+		if len(j.TargetAddress) > 0 && target.Address == j.TargetAddress {
+			return &target
+		}
+		candidates = append(candidates, target)
+	}
+	// the jobs created before the target address is recorded can only be matched by the notify type
+	if len(j.TargetAddress) == 0 && len(candidates) == 1 {
+		return &candidates[0]
+	}
+	return nil
+}
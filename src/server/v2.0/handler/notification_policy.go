@@ -3,6 +3,8 @@ package handler
 import (
 	"context"
 	"fmt"
+	"net/mail"
+
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/goharbor/harbor/src/common/rbac"
@@ -14,6 +16,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/notification/job"
 	"github.com/goharbor/harbor/src/pkg/notification/policy"
 	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	notifier_model "github.com/goharbor/harbor/src/pkg/notifier/model"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 	"github.com/goharbor/harbor/src/server/v2.0/restapi/operations/webhook"
@@ -91,6 +94,11 @@ func (n *notificationPolicyAPI) CreateWebhookPolicyOfProject(ctx context.Context
 	if ok, err := n.validateTargets(policy); !ok {
 		return n.SendError(ctx, err)
 	}
+	if policy.RetryPolicy != nil {
+		if err := policy.RetryPolicy.Validate(); err != nil {
+			return n.SendError(ctx, err)
+		}
+	}
 
 	projectID, err := getProjectID(ctx, projectNameOrID)
 	if err != nil {
@@ -121,12 +129,24 @@ func (n *notificationPolicyAPI) UpdateWebhookPolicyOfProject(ctx context.Context
 	if ok, err := n.validateTargets(policy); !ok {
 		return n.SendError(ctx, err)
 	}
+	if policy.RetryPolicy != nil {
+		if err := policy.RetryPolicy.Validate(); err != nil {
+			return n.SendError(ctx, err)
+		}
+	}
 
 	projectID, err := getProjectID(ctx, projectNameOrID)
 	if err != nil {
 		return n.SendError(ctx, err)
 	}
 	policy.ProjectID = projectID
+	// the signing secrets are never returned by the API, so keep the existing ones if they aren't specified
+	existing, err := n.getPolicy(ctx, params.WebhookPolicyID, projectID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	policy.ID = existing.ID
+	retainSigningSecrets(existing, policy)
 	if err := n.webhookPolicyMgr.Update(ctx, policy); err != nil {
 		return n.SendError(ctx, err)
 	}
@@ -140,6 +160,13 @@ func (n *notificationPolicyAPI) DeleteWebhookPolicyOfProject(ctx context.Context
 		return n.SendError(ctx, err)
 	}
 
+	projectID, err := getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	if _, err := n.getPolicy(ctx, params.WebhookPolicyID, projectID); err != nil {
+		return n.SendError(ctx, err)
+	}
 	if err := n.webhookPolicyMgr.Delete(ctx, params.WebhookPolicyID); err != nil {
 		return n.SendError(ctx, err)
 	}
@@ -152,7 +179,11 @@ func (n *notificationPolicyAPI) GetWebhookPolicyOfProject(ctx context.Context, p
 		return n.SendError(ctx, err)
 	}
 
-	policy, err := n.webhookPolicyMgr.Get(ctx, params.WebhookPolicyID)
+	projectID, err := getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	policy, err := n.getPolicy(ctx, params.WebhookPolicyID, projectID)
 	if err != nil {
 		return n.SendError(ctx, err)
 	}
@@ -160,6 +191,137 @@ func (n *notificationPolicyAPI) GetWebhookPolicyOfProject(ctx context.Context, p
 	return operation.NewGetWebhookPolicyOfProjectOK().WithPayload(model.NewNotifiactionPolicy(policy).ToSwagger())
 }
 
+func (n *notificationPolicyAPI) ListSystemWebhookPolicies(ctx context.Context, params webhook.ListSystemWebhookPoliciesParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionList, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	query, err := n.BuildQuery(ctx, params.Q, params.Sort, params.Page, params.PageSize)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	query.Keywords["ProjectID"] = policy_model.SystemLevelProjectID
+
+	total, err := n.webhookPolicyMgr.Count(ctx, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policies, err := n.webhookPolicyMgr.List(ctx, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	var results []*models.WebhookPolicy
+	for _, p := range policies {
+		results = append(results, model.NewNotifiactionPolicy(p).ToSwagger())
+	}
+
+	return operation.NewListSystemWebhookPoliciesOK().
+		WithXTotalCount(total).
+		WithLink(n.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
+		WithPayload(results)
+}
+
+func (n *notificationPolicyAPI) CreateSystemWebhookPolicy(ctx context.Context, params webhook.CreateSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionCreate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy := &policy_model.Policy{}
+	lib.JSONCopy(policy, params.Policy)
+	if err := n.validatePolicy(policy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy.ProjectID = policy_model.SystemLevelProjectID
+	id, err := n.webhookPolicyMgr.Create(ctx, policy)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	location := fmt.Sprintf("%s/%d", strings.TrimSuffix(params.HTTPRequest.URL.Path, "/"), id)
+	return operation.NewCreateSystemWebhookPolicyCreated().WithLocation(location)
+}
+
+func (n *notificationPolicyAPI) GetSystemWebhookPolicy(ctx context.Context, params webhook.GetSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy, err := n.getPolicy(ctx, params.WebhookPolicyID, policy_model.SystemLevelProjectID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return operation.NewGetSystemWebhookPolicyOK().WithPayload(model.NewNotifiactionPolicy(policy).ToSwagger())
+}
+
+func (n *notificationPolicyAPI) UpdateSystemWebhookPolicy(ctx context.Context, params webhook.UpdateSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy := &policy_model.Policy{}
+	lib.JSONCopy(policy, params.Policy)
+	if err := n.validatePolicy(policy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	existing, err := n.getPolicy(ctx, params.WebhookPolicyID, policy_model.SystemLevelProjectID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	policy.ID = existing.ID
+	policy.ProjectID = policy_model.SystemLevelProjectID
+	retainSigningSecrets(existing, policy)
+	if err := n.webhookPolicyMgr.Update(ctx, policy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return operation.NewUpdateSystemWebhookPolicyOK()
+}
+
+func (n *notificationPolicyAPI) DeleteSystemWebhookPolicy(ctx context.Context, params webhook.DeleteSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionDelete, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	if _, err := n.getPolicy(ctx, params.WebhookPolicyID, policy_model.SystemLevelProjectID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.webhookPolicyMgr.Delete(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	return operation.NewDeleteSystemWebhookPolicyOK()
+}
+
+// getPolicy returns the policy, a not found error is returned if the policy doesn't belong to
+// the specified project, the system level policies belong to the project SystemLevelProjectID
+func (n *notificationPolicyAPI) getPolicy(ctx context.Context, id, projectID int64) (*policy_model.Policy, error) {
+	policy, err := n.webhookPolicyMgr.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || policy.ProjectID != projectID {
+		return nil, errors.NotFoundError(nil).WithMessage("webhook policy %d not found", id)
+	}
+	return policy, nil
+}
+
+// validatePolicy validates the event types, targets and retry policy of the policy
+func (n *notificationPolicyAPI) validatePolicy(policy *policy_model.Policy) error {
+	if ok, err := n.validateEventTypes(policy); !ok {
+		return err
+	}
+	if ok, err := n.validateTargets(policy); !ok {
+		return err
+	}
+	if policy.RetryPolicy != nil {
+		return policy.RetryPolicy.Validate()
+	}
+	return nil
+}
+
 func (n *notificationPolicyAPI) LastTrigger(ctx context.Context, params webhook.LastTriggerParams) middleware.Responder {
 	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
 	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
@@ -225,19 +387,66 @@ func (n *notificationPolicyAPI) validateTargets(policy *policy_model.Policy) (bo
 		return false, errors.New(nil).WithMessage("empty notification target with policy %s", policy.Name).WithCode(errors.BadRequestCode)
 	}
 	for _, target := range policy.Targets {
+		_, ok := notification.SupportedNotifyTypes[target.Type]
+		if !ok {
+			return false, errors.New(nil).WithMessage("unsupported target type %s with policy %s", target.Type, policy.Name).WithCode(errors.BadRequestCode)
+		}
+
+		if len(target.SigningSecret) > 0 && target.Type != notifier_model.NotifyTypeHTTP {
+			return false, errors.New(nil).WithMessage("signing secret is only supported by the http target with policy %s", policy.Name).WithCode(errors.BadRequestCode)
+		}
+		if len(target.SigningSecret) > 0 && target.ClearSigningSecret {
+			return false, errors.New(nil).WithMessage("the signing secret can't be set and cleared at the same time with policy %s", policy.Name).WithCode(errors.BadRequestCode)
+		}
+
+		// the address of email target is the recipients separated by comma
+		if target.Type == notifier_model.NotifyTypeEmail {
+			if _, err := mail.ParseAddressList(target.Address); err != nil {
+				return false, errors.New(err).WithMessage("invalid email recipients %s with policy %s", target.Address, policy.Name).WithCode(errors.BadRequestCode)
+			}
+			continue
+		}
+
+		switch target.PayloadFormat {
+		case "", policy_model.PayloadFormatDefault:
+		case policy_model.PayloadFormatCloudEventsStructured, policy_model.PayloadFormatCloudEventsBinary:
+			if target.Type != notifier_model.NotifyTypeHTTP {
+				return false, errors.New(nil).WithMessage("payload format %s is only supported by the http target with policy %s", target.PayloadFormat, policy.Name).WithCode(errors.BadRequestCode)
+			}
+		default:
+			return false, errors.New(nil).WithMessage("unsupported payload format %s with policy %s", target.PayloadFormat, policy.Name).WithCode(errors.BadRequestCode)
+		}
+
 		url, err := utils.ParseEndpoint(target.Address)
 		if err != nil {
 			return false, errors.New(err).WithCode(errors.BadRequestCode)
 		}
 		// Prevent SSRF security issue #3755
 		target.Address = url.Scheme + "://" + url.Host + url.Path
+	}
+	return true, nil
+}
 
-		_, ok := notification.SupportedNotifyTypes[target.Type]
-		if !ok {
-			return false, errors.New(nil).WithMessage("unsupported target type %s with policy %s", target.Type, policy.Name).WithCode(errors.BadRequestCode)
+// retainSigningSecrets copies the signing secrets of the existing targets to the
+// targets which have the same address but don't specify the secret, the secret of
+// the target with ClearSigningSecret set is removed instead
+func retainSigningSecrets(existing, policy *policy_model.Policy) {
+	for i := range policy.Targets {
+		if policy.Targets[i].ClearSigningSecret {
+			policy.Targets[i].SigningSecret = ""
+			policy.Targets[i].ClearSigningSecret = false
+			continue
+		}
+		if existing == nil || len(policy.Targets[i].SigningSecret) > 0 {
+			continue
+		}
+		for _, target := range existing.Targets {
+			if target.Type == policy.Targets[i].Type && target.Address == policy.Targets[i].Address {
+				policy.Targets[i].SigningSecret = target.SigningSecret
+				break
+			}
 		}
 	}
-	return true, nil
 }
 
 func (n *notificationPolicyAPI) validateEventTypes(policy *policy_model.Policy) (bool, error) {
@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_retainSigningSecrets(t *testing.T) {
+	existing := &policy_model.Policy{
+		Targets: []policy_model.EventTarget{
+			{Type: "http", Address: "http://a.example.com", SigningSecret: "secret-a"},
+			{Type: "http", Address: "http://b.example.com", SigningSecret: "secret-b"},
+			{Type: "http", Address: "http://c.example.com", SigningSecret: "secret-c"},
+		},
+	}
+	policy := &policy_model.Policy{
+		Targets: []policy_model.EventTarget{
+			// keep the existing secret
+			{Type: "http", Address: "http://a.example.com"},
+			// replace the existing secret
+			{Type: "http", Address: "http://b.example.com", SigningSecret: "new-secret"},
+			// clear the existing secret
+			{Type: "http", Address: "http://c.example.com", ClearSigningSecret: true},
+			// new target without secret
+			{Type: "http", Address: "http://d.example.com"},
+		},
+	}
+	retainSigningSecrets(existing, policy)
+	assert.Equal(t, "secret-a", policy.Targets[0].SigningSecret)
+	assert.Equal(t, "new-secret", policy.Targets[1].SigningSecret)
+	assert.Empty(t, policy.Targets[2].SigningSecret)
+	assert.False(t, policy.Targets[2].ClearSigningSecret)
+	assert.Empty(t, policy.Targets[3].SigningSecret)
+}
@@ -543,6 +543,15 @@ func (a *projectAPI) UpdateProject(ctx context.Context, params operation.UpdateP
 	if params.Project.Metadata != nil && p.IsProxy() {
 		params.Project.Metadata.EnableContentTrust = nil
 	}
+	if params.Project.Metadata != nil && params.Project.Metadata.ProxyUpstreamRegistries != nil {
+		if !p.IsProxy() {
+			return a.SendError(ctx, errors.BadRequestError(nil).
+				WithMessage("proxy_upstream_registries is only available for the proxy cache project"))
+		}
+		if err := validateProxyUpstreamRegistries(ctx, *params.Project.Metadata.ProxyUpstreamRegistries); err != nil {
+			return a.SendError(ctx, err)
+		}
+	}
 	lib.JSONCopy(&p.Metadata, params.Project.Metadata)
 
 	if err := a.projectCtl.Update(ctx, p); err != nil {
@@ -669,20 +678,17 @@ func (a *projectAPI) validateProjectReq(ctx context.Context, req *models.Project
 		if *req.RegistryID <= 0 {
 			return errors.BadRequestError(fmt.Errorf("%d is invalid value of registry_id, it should be geater than 0", *req.RegistryID))
 		}
-
-		registry, err := registry.Ctl.Get(ctx, *req.RegistryID)
-		if err != nil {
-			return fmt.Errorf("failed to get the registry %d: %v", *req.RegistryID, err)
+		if err := validateProxyRegistry(ctx, *req.RegistryID); err != nil {
+			return err
 		}
-		permitted := false
-		for _, t := range config.GetPermittedRegistryTypesForProxyCache() {
-			if string(registry.Type) == t {
-				permitted = true
-				break
-			}
+	}
+
+	if req.Metadata != nil && req.Metadata.ProxyUpstreamRegistries != nil {
+		if req.RegistryID == nil {
+			return errors.BadRequestError(fmt.Errorf("proxy_upstream_registries is only available for the proxy cache project"))
 		}
-		if !permitted {
-			return errors.BadRequestError(fmt.Errorf("unsupported registry type %s", string(registry.Type)))
+		if err := validateProxyUpstreamRegistries(ctx, *req.Metadata.ProxyUpstreamRegistries); err != nil {
+			return err
 		}
 	}
 
@@ -696,6 +702,43 @@ func (a *projectAPI) validateProjectReq(ctx context.Context, req *models.Project
 	return nil
 }
 
+// validateProxyRegistry checks whether the registry can be used as the upstream of proxy cache project
+func validateProxyRegistry(ctx context.Context, registryID int64) error {
+	registry, err := registry.Ctl.Get(ctx, registryID)
+	if err != nil {
+		return fmt.Errorf("failed to get the registry %d: %v", registryID, err)
+	}
+	permitted := false
+	for _, t := range config.GetPermittedRegistryTypesForProxyCache() {
+		if string(registry.Type) == t {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return errors.BadRequestError(fmt.Errorf("unsupported registry type %s", string(registry.Type)))
+	}
+	return nil
+}
+
+// validateProxyUpstreamRegistries validates the comma separated IDs of the fallback upstream registries
+func validateProxyUpstreamRegistries(ctx context.Context, upstreams string) error {
+	for _, str := range strings.Split(upstreams, ",") {
+		str = strings.TrimSpace(str)
+		if len(str) == 0 {
+			continue
+		}
+		id, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || id <= 0 {
+			return errors.BadRequestError(fmt.Errorf("%s is invalid value of the upstream registry ID, it should be geater than 0", str))
+		}
+		if err := validateProxyRegistry(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *projectAPI) populateProperties(ctx context.Context, p *project.Project) error {
 	if secCtx, ok := security.FromContext(ctx); ok {
 		if sc, ok := secCtx.(*local.SecurityContext); ok {
@@ -141,7 +141,8 @@ func (p *projectMetadataAPI) validate(metas map[string]string) (map[string]strin
 
 	switch key {
 	case proModels.ProMetaPublic, proModels.ProMetaEnableContentTrust,
-		proModels.ProMetaPreventVul, proModels.ProMetaAutoScan:
+		proModels.ProMetaPreventVul, proModels.ProMetaAutoScan, proModels.ProMetaProxyServeStale,
+		proModels.ProMetaProxyEviction:
 		v, err := strconv.ParseBool(value)
 		if err != nil {
 			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid value: %s", value)
@@ -153,6 +154,18 @@ func (p *projectMetadataAPI) validate(metas map[string]string) (map[string]strin
 			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid value: %s", value)
 		}
 		metas[proModels.ProMetaSeverity] = strings.ToLower(severity.String())
+	case proModels.ProMetaProxyMaxStaleness:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || v < 0 {
+			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid value: %s", value)
+		}
+		metas[key] = strconv.FormatInt(v, 10)
+	case proModels.ProMetaProxyEvictionThreshold:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || v <= 0 || v > 100 {
+			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid value: %s", value)
+		}
+		metas[key] = strconv.FormatInt(v, 10)
 	default:
 		return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("invalid key: %s", key)
 	}
@@ -219,7 +219,11 @@ func (r *registryAPI) PingRegistry(ctx context.Context, params operation.PingReg
 			registry.Type = *params.Registry.Type
 		}
 		if params.Registry.URL != nil {
-			url, err := lib.ValidateHTTPURL(*params.Registry.URL)
+			validateURL := lib.ValidateHTTPURL
+			if registry.Type == model.RegistryTypeOCILayout {
+				validateURL = lib.ValidateFileURL
+			}
+			url, err := validateURL(*params.Registry.URL)
 			if err != nil {
 				return r.SendError(ctx, err)
 			}
@@ -25,6 +25,7 @@ import (
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/controller/replication"
 	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
@@ -65,6 +66,8 @@ func (r *replicationAPI) CreateReplicationPolicy(ctx context.Context, params ope
 		ReplicateDeletion: params.Policy.Deletion,
 		Override:          params.Policy.Override,
 		Enabled:           params.Policy.Enabled,
+		CopyByChunk:       params.Policy.CopyByChunk,
+		SyncMetadata:      params.Policy.SyncMetadata,
 	}
 	// Make this field be optional to keep backward compatibility
 	if params.Policy.DestNamespaceReplaceCount != nil {
@@ -91,6 +94,25 @@ func (r *replicationAPI) CreateReplicationPolicy(ctx context.Context, params ope
 			})
 		}
 	}
+	if len(params.Policy.RewriteRules) > 0 {
+		for _, rule := range params.Policy.RewriteRules {
+			policy.RewriteRules = append(policy.RewriteRules, &repctlmodel.RewriteRule{
+				Type:        rule.Type,
+				Match:       rule.Match,
+				Replacement: rule.Replacement,
+			})
+		}
+	}
+	if len(params.Policy.BandwidthWindows) > 0 {
+		for _, window := range params.Policy.BandwidthWindows {
+			policy.BandwidthWindows = append(policy.BandwidthWindows, &transfer.BandwidthWindow{
+				Start:    window.Start,
+				End:      window.End,
+				Timezone: window.Timezone,
+				Speed:    window.Speed,
+			})
+		}
+	}
 	if params.Policy.Trigger != nil {
 		policy.Trigger = &model.Trigger{
 			Type: params.Policy.Trigger.Type,
@@ -127,6 +149,8 @@ func (r *replicationAPI) UpdateReplicationPolicy(ctx context.Context, params ope
 		ReplicateDeletion: params.Policy.Deletion,
 		Override:          params.Policy.Override,
 		Enabled:           params.Policy.Enabled,
+		CopyByChunk:       params.Policy.CopyByChunk,
+		SyncMetadata:      params.Policy.SyncMetadata,
 	}
 	// Make this field be optional to keep backward compatibility
 	if params.Policy.DestNamespaceReplaceCount != nil {
@@ -154,6 +178,25 @@ func (r *replicationAPI) UpdateReplicationPolicy(ctx context.Context, params ope
 			})
 		}
 	}
+	if len(params.Policy.RewriteRules) > 0 {
+		for _, rule := range params.Policy.RewriteRules {
+			policy.RewriteRules = append(policy.RewriteRules, &repctlmodel.RewriteRule{
+				Type:        rule.Type,
+				Match:       rule.Match,
+				Replacement: rule.Replacement,
+			})
+		}
+	}
+	if len(params.Policy.BandwidthWindows) > 0 {
+		for _, window := range params.Policy.BandwidthWindows {
+			policy.BandwidthWindows = append(policy.BandwidthWindows, &transfer.BandwidthWindow{
+				Start:    window.Start,
+				End:      window.End,
+				Timezone: window.Timezone,
+				Speed:    window.Speed,
+			})
+		}
+	}
 	if params.Policy.Trigger != nil {
 		policy.Trigger = &model.Trigger{
 			Type: params.Policy.Trigger.Type,
@@ -244,7 +287,12 @@ func (r *replicationAPI) StartReplication(ctx context.Context, params operation.
 		trigger = task.ExecutionTriggerSchedule
 	}
 
-	executionID, err := r.ctl.Start(ctx, policy, nil, trigger)
+	var executionID int64
+	if params.Execution.Mode == replication.ExecutionModeVerify {
+		executionID, err = r.ctl.Verify(ctx, policy)
+	} else {
+		executionID, err = r.ctl.Start(ctx, policy, nil, trigger)
+	}
 	if err != nil {
 		return r.SendError(ctx, err)
 	}
@@ -416,6 +464,7 @@ func (r *replicationAPI) GetReplicationLog(ctx context.Context, params operation
 func convertReplicationPolicy(policy *repctlmodel.Policy) *models.ReplicationPolicy {
 	replaceCount := policy.DestNamespaceReplaceCount
 	p := &models.ReplicationPolicy{
+		CopyByChunk:               policy.CopyByChunk,
 		CreationTime:              strfmt.DateTime(policy.CreationTime),
 		Deletion:                  policy.ReplicateDeletion,
 		Description:               policy.Description,
@@ -427,6 +476,7 @@ func convertReplicationPolicy(policy *repctlmodel.Policy) *models.ReplicationPol
 		Override:                  policy.Override,
 		ReplicateDeletion:         policy.ReplicateDeletion,
 		Speed:                     &policy.Speed,
+		SyncMetadata:              policy.SyncMetadata,
 		UpdateTime:                strfmt.DateTime(policy.UpdateTime),
 	}
 	if policy.SrcRegistry != nil {
@@ -444,6 +494,25 @@ func convertReplicationPolicy(policy *repctlmodel.Policy) *models.ReplicationPol
 			})
 		}
 	}
+	if len(policy.RewriteRules) > 0 {
+		for _, rule := range policy.RewriteRules {
+			p.RewriteRules = append(p.RewriteRules, &models.ReplicationRewriteRule{
+				Type:        rule.Type,
+				Match:       rule.Match,
+				Replacement: rule.Replacement,
+			})
+		}
+	}
+	if len(policy.BandwidthWindows) > 0 {
+		for _, window := range policy.BandwidthWindows {
+			p.BandwidthWindows = append(p.BandwidthWindows, &models.ReplicationBandwidthWindow{
+				Start:    window.Start,
+				End:      window.End,
+				Timezone: window.Timezone,
+				Speed:    window.Speed,
+			})
+		}
+	}
 	if policy.Trigger != nil {
 		trigger := &models.ReplicationTrigger{
 			Type: string(policy.Trigger.Type),
@@ -488,6 +557,7 @@ func convertExecution(execution *replication.Execution) *models.ReplicationExecu
 		ID:         execution.ID,
 		PolicyID:   execution.PolicyID,
 		StatusText: execution.StatusMessage,
+		Mode:       execution.Mode,
 		StartTime:  strfmt.DateTime(execution.StartTime),
 		EndTime:    strfmt.DateTime(execution.EndTime),
 	}
@@ -524,15 +594,17 @@ func convertExecution(execution *replication.Execution) *models.ReplicationExecu
 
 func convertTask(task *replication.Task) *models.ReplicationTask {
 	tk := &models.ReplicationTask{
-		ID:           task.ID,
-		ExecutionID:  task.ExecutionID,
-		JobID:        task.JobID,
-		Operation:    task.Operation,
-		ResourceType: task.ResourceType,
-		SrcResource:  task.SourceResource,
-		DstResource:  task.DestinationResource,
-		StartTime:    strfmt.DateTime(task.StartTime),
-		EndTime:      strfmt.DateTime(task.EndTime),
+		ID:                  task.ID,
+		ExecutionID:         task.ExecutionID,
+		JobID:               task.JobID,
+		Operation:           task.Operation,
+		ResourceType:        task.ResourceType,
+		SrcResource:         task.SourceResource,
+		DstResource:         task.DestinationResource,
+		MissingArtifacts:    task.MissingArtifacts,
+		MismatchedArtifacts: task.MismatchedArtifacts,
+		StartTime:           strfmt.DateTime(task.StartTime),
+		EndTime:             strfmt.DateTime(task.EndTime),
 	}
 	// keep backward compatibility
 	switch task.Status {
@@ -2,17 +2,23 @@ package handler
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/goharbor/harbor/src/common/rbac"
 	projectCtl "github.com/goharbor/harbor/src/controller/project"
 	retentionCtl "github.com/goharbor/harbor/src/controller/retention"
 	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/project/metadata"
+	"github.com/goharbor/harbor/src/pkg/retention"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
 	"github.com/goharbor/harbor/src/pkg/task"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
@@ -86,6 +92,23 @@ var (
 					},
 				},
 			},
+			{
+				RuleTemplate: "vulnerabilitySeverityBelow",
+				DisplayText:  "without vulnerabilities of # severity or higher",
+				Action:       "retain",
+				Params: []*models.RetentionRuleParamMetadata{
+					{
+						Type:     "string",
+						Unit:     "SEVERITY",
+						Required: true,
+					},
+					{
+						Type:     "int",
+						Unit:     "DAYS",
+						Required: false,
+					},
+				},
+			},
 			{
 				RuleTemplate: "always",
 				DisplayText:  "always",
@@ -357,6 +380,111 @@ func (r *retentionAPI) GetRetentionTaskLog(ctx context.Context, params operation
 	return operation.NewGetRetentionTaskLogOK().WithPayload(string(log))
 }
 
+func (r *retentionAPI) ListRetentionExecutionCandidates(ctx context.Context, params operation.ListRetentionExecutionCandidatesParams) middleware.Responder {
+	query, err := r.BuildQuery(ctx, params.Q, nil, params.Page, params.PageSize)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	if err = r.requireDryRunExec(ctx, params.ID, params.Eid, rbac.ActionList); err != nil {
+		return r.SendError(ctx, err)
+	}
+	candidates, err := r.retentionCtl.ListRetentionExecCandidates(ctx, params.Eid, query)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	total, err := r.retentionCtl.GetTotalOfRetentionExecCandidates(ctx, params.Eid, query)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	var payload []*models.RetentionExecutionCandidate
+	for _, c := range candidates {
+		payload = append(payload, model.NewRetentionCandidate(c).ToSwagger())
+	}
+	return operation.NewListRetentionExecutionCandidatesOK().WithXTotalCount(total).
+		WithLink(r.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
+		WithPayload(payload)
+}
+
+func (r *retentionAPI) ExportRetentionExecutionCandidates(ctx context.Context, params operation.ExportRetentionExecutionCandidatesParams) middleware.Responder {
+	format := "csv"
+	if params.Format != nil {
+		format = *params.Format
+	}
+	if format != "csv" && format != "json" {
+		return r.SendError(ctx, errors.BadRequestError(nil).WithMessage("unsupported format %s", format))
+	}
+	if err := r.requireDryRunExec(ctx, params.ID, params.Eid, rbac.ActionRead); err != nil {
+		return r.SendError(ctx, err)
+	}
+	candidates, err := r.retentionCtl.ListRetentionExecCandidates(ctx, params.Eid, nil)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+
+	filename := fmt.Sprintf("retention-%d-execution-%d-candidates.%s", params.ID, params.Eid, format)
+	return middleware.ResponderFunc(func(w http.ResponseWriter, p runtime.Producer) {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if format == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			if candidates == nil {
+				candidates = []*retention.DryRunCandidate{}
+			}
+			if err := json.NewEncoder(w).Encode(candidates); err != nil {
+				log.Errorf("failed to export the candidates of retention execution %d: %v", params.Eid, err)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"Repository", "Digest", "Tags", "PushTime", "PullTime", "Action", "Rules"})
+		for _, c := range candidates {
+			_ = writer.Write([]string{
+				c.Repository,
+				c.Digest,
+				strings.Join(c.Tags, " "),
+				formatTime(c.PushTime),
+				formatTime(c.PullTime),
+				c.Action,
+				strings.Join(c.Rules, " "),
+			})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			log.Errorf("failed to export the candidates of retention execution %d: %v", params.Eid, err)
+		}
+	})
+}
+
+// requireDryRunExec checks the access of the retention and that the execution is a dry run one of the retention
+func (r *retentionAPI) requireDryRunExec(ctx context.Context, policyID, executionID int64, action rbac.Action) error {
+	p, err := r.retentionCtl.GetRetention(ctx, policyID)
+	if err != nil {
+		return errors.BadRequestError(err)
+	}
+	if err = r.requireAccess(ctx, p, action); err != nil {
+		return err
+	}
+	exec, err := r.retentionCtl.GetRetentionExec(ctx, executionID)
+	if err != nil {
+		return err
+	}
+	if exec.PolicyID != policyID {
+		return errors.NotFoundError(nil).WithMessage("retention execution %d not found", executionID)
+	}
+	if !exec.DryRun {
+		return errors.BadRequestError(nil).WithMessage("retention execution %d isn't a dry run", executionID)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func (r *retentionAPI) requireAccess(ctx context.Context, p *policy.Metadata, action rbac.Action, subresources ...rbac.Resource) error {
 	switch p.Scope.Level {
 	case "project":
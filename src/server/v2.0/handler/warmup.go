@@ -0,0 +1,186 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/controller/proxy/warmup"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/server/v2.0/models"
+	operation "github.com/goharbor/harbor/src/server/v2.0/restapi/operations/warmup"
+)
+
+func newWarmUpAPI() *warmUpAPI {
+	return &warmUpAPI{
+		warmUpCtl:  warmup.Ctl,
+		projectCtl: project.Ctl,
+	}
+}
+
+type warmUpAPI struct {
+	BaseAPI
+	warmUpCtl  warmup.Controller
+	projectCtl project.Controller
+}
+
+func (w *warmUpAPI) Prepare(ctx context.Context, operation string, params interface{}) middleware.Responder {
+	return nil
+}
+
+func (w *warmUpAPI) StartWarmUp(ctx context.Context, params operation.StartWarmUpParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := w.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionCreate, rbac.ResourceProxyWarmUp); err != nil {
+		return w.SendError(ctx, err)
+	}
+	projectID, err := w.getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	id, err := w.warmUpCtl.Start(ctx, warmup.Policy{
+		ProjectID: projectID,
+		Patterns:  params.Policy.Patterns,
+	}, task.ExecutionTriggerManual)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	location := fmt.Sprintf("%s/executions/%d", strings.TrimSuffix(params.HTTPRequest.URL.Path, "/"), id)
+	return operation.NewStartWarmUpCreated().WithLocation(location)
+}
+
+func (w *warmUpAPI) CreateWarmUpSchedule(ctx context.Context, params operation.CreateWarmUpScheduleParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := w.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionCreate, rbac.ResourceProxyWarmUp); err != nil {
+		return w.SendError(ctx, err)
+	}
+	projectID, err := w.getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	schedule := params.Schedule.Schedule
+	if schedule == nil {
+		return w.SendError(ctx, errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("the schedule of the warm-up is required"))
+	}
+	policy := warmup.Policy{
+		ProjectID: projectID,
+		Patterns:  params.Schedule.Patterns,
+	}
+	switch schedule.Type {
+	case ScheduleNone:
+		err = w.warmUpCtl.DeleteSchedule(ctx, projectID)
+	case ScheduleHourly, ScheduleDaily, ScheduleWeekly, ScheduleCustom:
+		err = w.updateSchedule(ctx, schedule.Type, schedule.Cron, policy)
+	default:
+		err = errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("unsupported schedule type %s for the warm-up", schedule.Type)
+	}
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	return operation.NewCreateWarmUpScheduleCreated().WithLocation(params.HTTPRequest.URL.Path)
+}
+
+// updateSchedule replaces the existing warm-up schedule of the project, the controller
+// removes the existing one only after the request is validated
+func (w *warmUpAPI) updateSchedule(ctx context.Context, cronType, cron string, policy warmup.Policy) error {
+	if cron == "" {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("empty cron string for warm-up schedule")
+	}
+	_, err := w.warmUpCtl.CreateSchedule(ctx, cronType, cron, policy)
+	return err
+}
+
+func (w *warmUpAPI) ListWarmUpExecutions(ctx context.Context, params operation.ListWarmUpExecutionsParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := w.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionList, rbac.ResourceProxyWarmUp); err != nil {
+		return w.SendError(ctx, err)
+	}
+	projectID, err := w.getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	query, err := w.BuildQuery(ctx, params.Q, params.Sort, params.Page, params.PageSize)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	total, err := w.warmUpCtl.ExecutionCount(ctx, projectID, query)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	execs, err := w.warmUpCtl.ListExecutions(ctx, projectID, query)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	var executions []*models.Execution
+	for _, exec := range execs {
+		executions = append(executions, convertWarmUpExecution(exec))
+	}
+	return operation.NewListWarmUpExecutionsOK().
+		WithXTotalCount(total).
+		WithLink(w.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
+		WithPayload(executions)
+}
+
+func (w *warmUpAPI) GetWarmUpExecution(ctx context.Context, params operation.GetWarmUpExecutionParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := w.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionRead, rbac.ResourceProxyWarmUp); err != nil {
+		return w.SendError(ctx, err)
+	}
+	projectID, err := w.getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	exec, err := w.warmUpCtl.GetExecution(ctx, params.ExecutionID)
+	if err != nil {
+		return w.SendError(ctx, err)
+	}
+	// the execution must belong to the project
+	if exec.ProjectID != projectID {
+		return w.SendError(ctx, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessage("warm-up execution %d not found in project %d", params.ExecutionID, projectID))
+	}
+	return operation.NewGetWarmUpExecutionOK().WithPayload(convertWarmUpExecution(exec))
+}
+
+func (w *warmUpAPI) getProjectID(ctx context.Context, projectNameOrID interface{}) (int64, error) {
+	p, err := w.projectCtl.Get(ctx, projectNameOrID, project.Metadata(false))
+	if err != nil {
+		return 0, err
+	}
+	return p.ProjectID, nil
+}
+
+func convertWarmUpExecution(exec *warmup.Execution) *models.Execution {
+	return &models.Execution{
+		ID:            exec.ID,
+		VendorType:    warmup.VendorType,
+		VendorID:      exec.ProjectID,
+		Status:        exec.Status,
+		StatusMessage: exec.StatusMessage,
+		Trigger:       exec.Trigger,
+		ExtraAttrs:    exec.ExtraAttrs,
+		StartTime:     exec.StartTime.Format(time.RFC3339),
+		EndTime:       exec.EndTime.Format(time.RFC3339),
+	}
+}
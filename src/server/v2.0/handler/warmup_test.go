@@ -0,0 +1,257 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/controller/proxy/warmup"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/task"
+	swaggermodels "github.com/goharbor/harbor/src/server/v2.0/models"
+	"github.com/goharbor/harbor/src/server/v2.0/restapi"
+	projecttesting "github.com/goharbor/harbor/src/testing/controller/project"
+	warmuptesting "github.com/goharbor/harbor/src/testing/controller/proxy/warmup"
+	"github.com/goharbor/harbor/src/testing/mock"
+	htesting "github.com/goharbor/harbor/src/testing/server/v2.0/handler"
+	"github.com/stretchr/testify/suite"
+)
+
+type WarmUpTestSuite struct {
+	htesting.Suite
+
+	warmUpCtl  *warmuptesting.Controller
+	projectCtl *projecttesting.Controller
+	project    *models.Project
+}
+
+func (suite *WarmUpTestSuite) SetupSuite() {
+	suite.project = &models.Project{
+		ProjectID:  1,
+		Name:       "proxy",
+		RegistryID: 1,
+	}
+
+	suite.warmUpCtl = &warmuptesting.Controller{}
+	suite.projectCtl = &projecttesting.Controller{}
+
+	suite.Config = &restapi.Config{
+		WarmupAPI: &warmUpAPI{
+			warmUpCtl:  suite.warmUpCtl,
+			projectCtl: suite.projectCtl,
+		},
+	}
+
+	suite.Suite.SetupSuite()
+}
+
+func (suite *WarmUpTestSuite) TestAuthorization() {
+	newBody := func(body interface{}) io.Reader {
+		if body == nil {
+			return nil
+		}
+
+		buf, err := json.Marshal(body)
+		suite.Require().NoError(err)
+		return bytes.NewBuffer(buf)
+	}
+
+	reqs := []struct {
+		method string
+		url    string
+		body   interface{}
+	}{
+		{http.MethodPost, "/projects/1/warmup", &swaggermodels.WarmUpPolicy{Patterns: []string{"library/nginx"}}},
+		{http.MethodPost, "/projects/1/warmup/schedule", &swaggermodels.WarmUpSchedule{Schedule: &swaggermodels.ScheduleObj{Type: "None"}}},
+		{http.MethodGet, "/projects/1/warmup/executions", nil},
+		{http.MethodGet, "/projects/1/warmup/executions/1", nil},
+	}
+	for _, req := range reqs {
+		{
+			// authorized required
+			suite.Security.On("IsAuthenticated").Return(false).Once()
+
+			res, err := suite.DoReq(req.method, req.url, newBody(req.body))
+			suite.NoError(err)
+			suite.Equal(401, res.StatusCode)
+		}
+
+		{
+			// permission required
+			suite.Security.On("IsAuthenticated").Return(true).Once()
+			suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(false).Once()
+			suite.Security.On("GetUsername").Return("username").Once()
+
+			res, err := suite.DoReq(req.method, req.url, newBody(req.body))
+			suite.NoError(err)
+			suite.Equal(403, res.StatusCode)
+		}
+	}
+}
+
+func (suite *WarmUpTestSuite) TestStartWarmUp() {
+	times := 2
+	suite.Security.On("IsAuthenticated").Return(true).Times(times)
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true).Times(times)
+	mock.OnAnything(suite.projectCtl, "Get").Return(suite.project, nil).Times(times)
+
+	{
+		// start failed
+		mock.OnAnything(suite.warmUpCtl, "Start").Return(int64(0), fmt.Errorf("failed to start")).Once()
+
+		res, err := suite.PostJSON("/projects/1/warmup", &swaggermodels.WarmUpPolicy{Patterns: []string{"library/nginx"}})
+		suite.NoError(err)
+		suite.Equal(500, res.StatusCode)
+	}
+
+	{
+		suite.warmUpCtl.On("Start", mock.Anything, warmup.Policy{
+			ProjectID: 1,
+			Patterns:  []string{"library/nginx:1.*"},
+		}, task.ExecutionTriggerManual).Return(int64(2), nil).Once()
+
+		res, err := suite.PostJSON("/projects/1/warmup", &swaggermodels.WarmUpPolicy{Patterns: []string{"library/nginx:1.*"}})
+		suite.NoError(err)
+		suite.Equal(201, res.StatusCode)
+		suite.Equal("/api/v2.0/projects/1/warmup/executions/2", res.Header.Get("Location"))
+	}
+}
+
+func (suite *WarmUpTestSuite) TestCreateWarmUpSchedule() {
+	times := 4
+	suite.Security.On("IsAuthenticated").Return(true).Times(times)
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true).Times(times)
+	mock.OnAnything(suite.projectCtl, "Get").Return(suite.project, nil).Times(times)
+
+	{
+		// the existing schedule is replaced
+		suite.warmUpCtl.On("CreateSchedule", mock.Anything, "Daily", "0 0 0 * * *", warmup.Policy{
+			ProjectID: 1,
+			Patterns:  []string{"library/nginx"},
+		}).Return(int64(1), nil).Once()
+
+		res, err := suite.PostJSON("/projects/1/warmup/schedule", &swaggermodels.WarmUpSchedule{
+			Schedule: &swaggermodels.ScheduleObj{Type: "Daily", Cron: "0 0 0 * * *"},
+			Patterns: []string{"library/nginx"},
+		})
+		suite.NoError(err)
+		suite.Equal(201, res.StatusCode)
+		suite.warmUpCtl.AssertNotCalled(suite.T(), "DeleteSchedule", mock.Anything, int64(1))
+	}
+
+	{
+		// the schedule is removed
+		suite.warmUpCtl.On("DeleteSchedule", mock.Anything, int64(1)).Return(nil).Once()
+
+		res, err := suite.PostJSON("/projects/1/warmup/schedule", &swaggermodels.WarmUpSchedule{
+			Schedule: &swaggermodels.ScheduleObj{Type: "None"},
+		})
+		suite.NoError(err)
+		suite.Equal(201, res.StatusCode)
+	}
+
+	{
+		// the invalid request doesn't remove the existing schedule
+		suite.warmUpCtl.On("CreateSchedule", mock.Anything, "Custom", "invalid", mock.Anything).
+			Return(int64(0), errors.New(nil).WithCode(errors.BadRequestCode)).Once()
+
+		res, err := suite.PostJSON("/projects/1/warmup/schedule", &swaggermodels.WarmUpSchedule{
+			Schedule: &swaggermodels.ScheduleObj{Type: "Custom", Cron: "invalid"},
+			Patterns: []string{"library/nginx"},
+		})
+		suite.NoError(err)
+		suite.Equal(400, res.StatusCode)
+		suite.warmUpCtl.AssertNumberOfCalls(suite.T(), "DeleteSchedule", 1)
+	}
+
+	{
+		// empty cron
+		res, err := suite.PostJSON("/projects/1/warmup/schedule", &swaggermodels.WarmUpSchedule{
+			Schedule: &swaggermodels.ScheduleObj{Type: "Custom"},
+			Patterns: []string{"library/nginx"},
+		})
+		suite.NoError(err)
+		suite.Equal(400, res.StatusCode)
+	}
+}
+
+func (suite *WarmUpTestSuite) TestListWarmUpExecutions() {
+	times := 2
+	suite.Security.On("IsAuthenticated").Return(true).Times(times)
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true).Times(times)
+	mock.OnAnything(suite.projectCtl, "Get").Return(suite.project, nil).Times(times)
+
+	{
+		// count failed
+		mock.OnAnything(suite.warmUpCtl, "ExecutionCount").Return(int64(0), fmt.Errorf("failed to count")).Once()
+
+		res, err := suite.Get("/projects/1/warmup/executions")
+		suite.NoError(err)
+		suite.Equal(500, res.StatusCode)
+	}
+
+	{
+		suite.warmUpCtl.On("ExecutionCount", mock.Anything, int64(1), mock.Anything).Return(int64(1), nil).Once()
+		suite.warmUpCtl.On("ListExecutions", mock.Anything, int64(1), mock.Anything).Return([]*warmup.Execution{
+			{ID: 2, ProjectID: 1, Status: "Running", Trigger: "MANUAL"},
+		}, nil).Once()
+
+		var executions []*swaggermodels.Execution
+		res, err := suite.GetJSON("/projects/1/warmup/executions", &executions)
+		suite.NoError(err)
+		suite.Equal(200, res.StatusCode)
+		suite.Equal("1", res.Header.Get("X-Total-Count"))
+		suite.Require().Len(executions, 1)
+		suite.Equal(int64(2), executions[0].ID)
+		suite.Equal(warmup.VendorType, executions[0].VendorType)
+		suite.Equal(int64(1), executions[0].VendorID)
+	}
+}
+
+func (suite *WarmUpTestSuite) TestGetWarmUpExecution() {
+	times := 2
+	suite.Security.On("IsAuthenticated").Return(true).Times(times)
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true).Times(times)
+	mock.OnAnything(suite.projectCtl, "Get").Return(suite.project, nil).Times(times)
+
+	{
+		// the execution belongs to another project
+		suite.warmUpCtl.On("GetExecution", mock.Anything, int64(3)).Return(&warmup.Execution{ID: 3, ProjectID: 2}, nil).Once()
+
+		res, err := suite.Get("/projects/1/warmup/executions/3")
+		suite.NoError(err)
+		suite.Equal(404, res.StatusCode)
+	}
+
+	{
+		suite.warmUpCtl.On("GetExecution", mock.Anything, int64(2)).Return(&warmup.Execution{ID: 2, ProjectID: 1, Status: "Success"}, nil).Once()
+
+		var execution swaggermodels.Execution
+		res, err := suite.GetJSON("/projects/1/warmup/executions/2", &execution)
+		suite.NoError(err)
+		suite.Equal(200, res.StatusCode)
+		suite.Equal("Success", execution.Status)
+	}
+}
+
+func TestWarmUpTestSuite(t *testing.T) {
+	suite.Run(t, &WarmUpTestSuite{})
+}
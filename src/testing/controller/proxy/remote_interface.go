@@ -7,6 +7,8 @@ import (
 
 	distribution "github.com/docker/distribution"
 
+	model "github.com/goharbor/harbor/src/pkg/reg/model"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -45,6 +47,52 @@ func (_m *RemoteInterface) BlobReader(repo string, dig string) (int64, io.ReadCl
 	return r0, r1, r2
 }
 
+// FetchArtifacts provides a mock function with given fields: filters
+func (_m *RemoteInterface) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	ret := _m.Called(filters)
+
+	var r0 []*model.Resource
+	if rf, ok := ret.Get(0).(func([]*model.Filter) []*model.Resource); ok {
+		r0 = rf(filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Resource)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*model.Filter) error); ok {
+		r1 = rf(filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTags provides a mock function with given fields: repo
+func (_m *RemoteInterface) ListTags(repo string) ([]string, error) {
+	ret := _m.Called(repo)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(repo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Manifest provides a mock function with given fields: repo, ref
 func (_m *RemoteInterface) Manifest(repo string, ref string) (distribution.Manifest, string, error) {
 	ret := _m.Called(repo, ref)
@@ -104,3 +152,17 @@ func (_m *RemoteInterface) ManifestExist(repo string, ref string) (bool, *distri
 
 	return r0, r1, r2
 }
+
+// Upstream provides a mock function with given fields:
+func (_m *RemoteInterface) Upstream() int64 {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
@@ -334,3 +334,24 @@ func (_m *Controller) UpdatePolicy(ctx context.Context, policy *model.Policy, pr
 
 	return r0
 }
+
+// Verify provides a mock function with given fields: ctx, policy
+func (_m *Controller) Verify(ctx context.Context, policy *model.Policy) (int64, error) {
+	ret := _m.Called(ctx, policy)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) int64); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Policy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -145,6 +145,27 @@ func (_m *Controller) GetRetentionExecTaskLog(ctx context.Context, taskID int64)
 	return r0, r1
 }
 
+// GetTotalOfRetentionExecCandidates provides a mock function with given fields: ctx, executionID, query
+func (_m *Controller) GetTotalOfRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) (int64, error) {
+	ret := _m.Called(ctx, executionID, query)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *q.Query) int64); ok {
+		r0 = rf(ctx, executionID, query)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *q.Query) error); ok {
+		r1 = rf(ctx, executionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTotalOfRetentionExecTasks provides a mock function with given fields: ctx, executionID
 func (_m *Controller) GetTotalOfRetentionExecTasks(ctx context.Context, executionID int64) (int64, error) {
 	ret := _m.Called(ctx, executionID)
@@ -187,6 +208,29 @@ func (_m *Controller) GetTotalOfRetentionExecs(ctx context.Context, policyID int
 	return r0, r1
 }
 
+// ListRetentionExecCandidates provides a mock function with given fields: ctx, executionID, query
+func (_m *Controller) ListRetentionExecCandidates(ctx context.Context, executionID int64, query *q.Query) ([]*pkgretention.DryRunCandidate, error) {
+	ret := _m.Called(ctx, executionID, query)
+
+	var r0 []*pkgretention.DryRunCandidate
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *q.Query) []*pkgretention.DryRunCandidate); ok {
+		r0 = rf(ctx, executionID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*pkgretention.DryRunCandidate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *q.Query) error); ok {
+		r1 = rf(ctx, executionID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRetentionExecTasks provides a mock function with given fields: ctx, executionID, query
 func (_m *Controller) ListRetentionExecTasks(ctx context.Context, executionID int64, query *q.Query) ([]*pkgretention.Task, error) {
 	ret := _m.Called(ctx, executionID, query)
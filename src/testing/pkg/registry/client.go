@@ -106,6 +106,22 @@ func (f *FakeClient) PushBlob(repository, digest string, size int64, blob io.Rea
 	return args.Error(0)
 }
 
+// PullBlobChunk ...
+func (f *FakeClient) PullBlobChunk(repository, digest string, blobSize, start, end int64) (int64, io.ReadCloser, error) {
+	args := f.Called()
+	var blob io.ReadCloser
+	if args[1] != nil {
+		blob = args[1].(io.ReadCloser)
+	}
+	return int64(args.Int(0)), blob, args.Error(2)
+}
+
+// PushBlobChunk ...
+func (f *FakeClient) PushBlobChunk(repository, digest string, blobSize int64, chunk io.Reader, start, end int64, location string) (string, int64, error) {
+	args := f.Called()
+	return args.String(0), int64(args.Int(1)), args.Error(2)
+}
+
 // MountBlob ...
 func (f *FakeClient) MountBlob(srcRepository, digest, dstRepository string) (err error) {
 	args := f.Called()
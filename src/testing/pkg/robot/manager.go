@@ -132,6 +132,27 @@ func (_m *Manager) List(ctx context.Context, query *q.Query) ([]*model.Robot, er
 	return r0, r1
 }
 
+// MarkExpirationNotified provides a mock function with given fields: ctx, id, expiresAt
+func (_m *Manager) MarkExpirationNotified(ctx context.Context, id int64, expiresAt int64) (bool, error) {
+	ret := _m.Called(ctx, id, expiresAt)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
+		r0 = rf(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, m, props
 func (_m *Manager) Update(ctx context.Context, m *model.Robot, props ...string) error {
 	_va := make([]interface{}, len(props))